@@ -18,11 +18,9 @@ package v1
 
 import (
 	"fmt"
-	"io"
 	"os"
 
 	cli "github.com/urfave/cli/v2"
-	"k8s.io/klog/v2"
 
 	"sigs.k8s.io/yaml"
 )
@@ -32,24 +30,57 @@ const Version = "v1"
 
 // Config is a versioned struct used to hold configuration information.
 type Config struct {
-	Version   string    `json:"version"             yaml:"version"`
-	Flags     Flags     `json:"flags,omitempty"     yaml:"flags,omitempty"`
-	Resources Resources `json:"resources,omitempty" yaml:"resources,omitempty"`
-	Sharing   Sharing   `json:"sharing,omitempty"   yaml:"sharing,omitempty"`
-	Imex      Imex      `json:"imex,omitempty"      yaml:"imex,omitempty"`
+	Version      string       `json:"version"                yaml:"version"`
+	Flags        Flags        `json:"flags,omitempty"        yaml:"flags,omitempty"`
+	Resources    Resources    `json:"resources,omitempty"    yaml:"resources,omitempty"`
+	Sharing      Sharing      `json:"sharing,omitempty"      yaml:"sharing,omitempty"`
+	Imex         Imex         `json:"imex,omitempty"         yaml:"imex,omitempty"`
+	HealthChecks HealthChecks `json:"healthChecks,omitempty" yaml:"healthChecks,omitempty"`
+}
+
+// VersionedConfig is implemented by the configs of versions other than v1.
+// A config of such a version is converted to a v1 config when it is loaded
+// since the components are configured through a v1 config.
+type VersionedConfig interface {
+	ToV1() (*Config, error)
+	// FieldPath returns the path of the field that corresponds to the
+	// specified field of a v1 config.
+	FieldPath(v1Path string) string
 }
 
 // NewConfig builds out a Config struct from a config file (or command line flags).
 // The data stored in the config will be populated in order of precedence from
 // (1) command line, (2) environment variable, (3) config file.
 func NewConfig(c *cli.Context, flags []cli.Flag) (*Config, error) {
+	config, _, err := NewConfigWithContents(c, flags)
+	return config, err
+}
+
+// NewConfigWithContents builds out a Config struct as NewConfig does and also
+// returns the contents of the config file that the config was parsed from.
+// This identifies the config that was loaded even if the config file is
+// replaced in the meantime. The contents are also returned if these cannot be
+// parsed, and are empty if no config file is specified.
+func NewConfigWithContents(c *cli.Context, flags []cli.Flag) (*Config, []byte, error) {
+	return NewConfigWithParser(c, flags, Parse)
+}
+
+// NewConfigWithParser builds out a Config struct as NewConfigWithContents does
+// but uses the specified function to parse the contents of the config file.
+// This allows config files of other versions to be converted to a v1 config.
+func NewConfigWithParser(c *cli.Context, flags []cli.Flag, parse func([]byte) (*Config, error)) (*Config, []byte, error) {
 	config := &Config{Version: Version}
 
+	var contents []byte
 	if configFile := c.String("config-file"); configFile != "" {
 		var err error
-		config, err = parseConfig(configFile)
+		contents, err = os.ReadFile(configFile)
 		if err != nil {
-			return nil, fmt.Errorf("unable to parse config file: %v", err)
+			return nil, nil, fmt.Errorf("unable to parse config file: error opening config file: %v", err)
+		}
+		config, err = parse(contents)
+		if err != nil {
+			return nil, contents, fmt.Errorf("unable to parse config file: error parsing config file: %v", err)
 		}
 	}
 
@@ -62,6 +93,9 @@ func NewConfig(c *cli.Context, flags []cli.Flag) (*Config, error) {
 	if c.IsSet("imex-required") {
 		config.Imex.Required = c.Bool("imex-required")
 	}
+	// The legacy health check environment variables take precedence over
+	// the values in the config file.
+	config.HealthChecks.updateFromEnvvars()
 
 	// If nvidiaDevRoot (the path to the device nodes on the host) is not set,
 	// we default to using the driver root on the host.
@@ -75,52 +109,13 @@ func NewConfig(c *cli.Context, flags []cli.Flag) (*Config, error) {
 		config.Sharing.MPS.FailRequestsGreaterThanOne = &t
 	}
 
-	return config, nil
-}
-
-// DisableResourceNamingInConfig temporarily disable the resource renaming feature of the plugin.
-// This may be reenabled in a future release.
-func DisableResourceNamingInConfig(config *Config) {
-	// Disable resource renaming through config.Resource
-	if len(config.Resources.GPUs) > 0 || len(config.Resources.MIGs) > 0 {
-		klog.Warning("Customizing the 'resources' field is not yet supported in the config. Ignoring...")
-	}
-	config.Resources.GPUs = nil
-	config.Resources.MIGs = nil
-
-	// Disable renaming / device selection in Sharing.TimeSlicing.Resources
-	config.Sharing.TimeSlicing.disableResoureRenaming("timeSlicing")
-	// Disable renaming / device selection in Sharing.MPS.Resources
-	config.Sharing.MPS.disableResoureRenaming("mps")
+	return config, contents, nil
 }
 
-// parseConfig parses a config file as either YAML of JSON and unmarshals it into a Config struct.
-func parseConfig(configFile string) (*Config, error) {
-	reader, err := os.Open(configFile)
-	if err != nil {
-		return nil, fmt.Errorf("error opening config file: %v", err)
-	}
-	defer reader.Close()
-
-	config, err := parseConfigFrom(reader)
-	if err != nil {
-		return nil, fmt.Errorf("error parsing config file: %v", err)
-	}
-
-	return config, nil
-}
-
-func parseConfigFrom(reader io.Reader) (*Config, error) {
-	var err error
-	var configYaml []byte
-
-	configYaml, err = io.ReadAll(reader)
-	if err != nil {
-		return nil, fmt.Errorf("read error: %v", err)
-	}
-
+// Parse parses the contents of a v1 config file as either YAML or JSON.
+func Parse(contents []byte) (*Config, error) {
 	var config Config
-	err = yaml.Unmarshal(configYaml, &config)
+	err := yaml.Unmarshal(contents, &config)
 	if err != nil {
 		return nil, fmt.Errorf("unmarshal error: %v", err)
 	}
@@ -17,6 +17,8 @@
 package v1
 
 import (
+	"time"
+
 	cdiapi "tags.cncf.io/container-device-interface/pkg/cdi"
 )
 
@@ -48,12 +50,43 @@ const (
 	DeviceIDStrategyIndex = "index"
 )
 
+// Constants to represent the various modes of labeling individual devices
+const (
+	DeviceLabelsNone  = "none"
+	DeviceLabelsIndex = "index"
+	DeviceLabelsUUID  = "uuid"
+)
+
 // Constants to represent the various allocation policies
 const (
 	AllocationPolicyDistributed = "distributed"
 	AllocationPolicyPacked      = "packed"
 )
 
+// Constants to represent the various health recovery policies
+const (
+	HealthRecoveryPolicyNone        = "none"
+	HealthRecoveryPolicyQuietPeriod = "quiet-period"
+	HealthRecoveryPolicyNVMLProbe   = "nvml-probe"
+)
+
+// Constants to represent the confidential computing (CC) modes that devices
+// are expected to be in
+const (
+	CCModeAny      = "any"
+	CCModeOn       = "on"
+	CCModeOff      = "off"
+	CCModeDevtools = "devtools"
+)
+
+// DefaultHealthRecoveryQuietPeriod is the default period without health
+// events after which an unhealthy device is considered for recovery.
+const DefaultHealthRecoveryQuietPeriod = 5 * time.Minute
+
+// DefaultMigPollInterval is the default interval at which GFD checks whether
+// the MIG layout of the GPUs has changed.
+const DefaultMigPollInterval = 10 * time.Second
+
 // Constants related to generating CDI specifications
 const (
 	DefaultCDIAnnotationPrefix = cdiapi.AnnotationPrefix
@@ -42,8 +42,8 @@ func updateFromCLIFlag[T any](pflag **T, c *cli.Context, flagName string) {
 			if gf, ok := c.Generic(flagName).(*DurationValue); ok && gf.Value != nil {
 				*flag = gf.Value
 			}
-		case **deviceListStrategyFlag:
-			*flag = ptr((deviceListStrategyFlag)(c.StringSlice(flagName)))
+		case **DeviceListStrategyFlag:
+			*flag = ptr((DeviceListStrategyFlag)(c.StringSlice(flagName)))
 		default:
 			panic(fmt.Errorf("unsupported flag type for %v: %T", flagName, flag))
 		}
@@ -74,21 +74,24 @@ type CommandLineFlags struct {
 // PluginCommandLineFlags holds the list of command line flags specific to the device plugin.
 type PluginCommandLineFlags struct {
 	PassDeviceSpecs               *bool                   `json:"passDeviceSpecs"               yaml:"passDeviceSpecs"`
-	DeviceListStrategy            *deviceListStrategyFlag `json:"deviceListStrategy"            yaml:"deviceListStrategy"`
+	DeviceListStrategy            *DeviceListStrategyFlag `json:"deviceListStrategy"            yaml:"deviceListStrategy"`
 	DeviceIDStrategy              *string                 `json:"deviceIDStrategy"              yaml:"deviceIDStrategy"`
 	CDIAnnotationPrefix           *string                 `json:"cdiAnnotationPrefix"           yaml:"cdiAnnotationPrefix"`
 	NvidiaCTKPath                 *string                 `json:"nvidiaCTKPath"                 yaml:"nvidiaCTKPath"`
 	ContainerDriverRoot           *string                 `json:"containerDriverRoot"           yaml:"containerDriverRoot"`
 	SharedDevicesAllocationPolicy *string                 `json:"sharedDevicesAllocationPolicy" yaml:"sharedDevicesAllocationPolicy"`
+	HealthRecoveryPolicy          *string                 `json:"healthRecoveryPolicy"          yaml:"healthRecoveryPolicy"`
+	HealthRecoveryQuietPeriod     *Duration               `json:"healthRecoveryQuietPeriod"     yaml:"healthRecoveryQuietPeriod"`
+	CCMode                        *string                 `json:"ccMode,omitempty"              yaml:"ccMode,omitempty"`
 }
 
-// deviceListStrategyFlag is a custom type for parsing the deviceListStrategy flag.
-type deviceListStrategyFlag []string
+// DeviceListStrategyFlag is a custom type for parsing the deviceListStrategy flag.
+type DeviceListStrategyFlag []string
 
-// UnmarshalJSON implements the custom unmarshaler for the deviceListStrategyFlag type.
+// UnmarshalJSON implements the custom unmarshaler for the DeviceListStrategyFlag type.
 // Since this option allows a single string or a list of strings to be specified,
 // we need to handle both cases.
-func (f *deviceListStrategyFlag) UnmarshalJSON(b []byte) error {
+func (f *DeviceListStrategyFlag) UnmarshalJSON(b []byte) error {
 	var single string
 	err := json.Unmarshal(b, &single)
 	if err == nil {
@@ -112,6 +115,15 @@ type GFDCommandLineFlags struct {
 	SleepInterval   *Duration `json:"sleepInterval"   yaml:"sleepInterval"`
 	OutputFile      *string   `json:"outputFile"      yaml:"outputFile"`
 	MachineTypeFile *string   `json:"machineTypeFile" yaml:"machineTypeFile"`
+	// DeviceLabels selects whether labels are also generated for each
+	// individual GPU and whether these are keyed by index or UUID.
+	DeviceLabels *string `json:"deviceLabels,omitempty" yaml:"deviceLabels,omitempty"`
+	// MigPollInterval is the interval at which the MIG layout of the GPUs is
+	// checked for changes that require relabeling. A value of 0 disables this.
+	MigPollInterval *Duration `json:"migPollInterval,omitempty" yaml:"migPollInterval,omitempty"`
+	// TriggerSocket is the path of a unix socket on which connections trigger
+	// relabeling. No socket is created if this is empty.
+	TriggerSocket *string `json:"triggerSocket,omitempty" yaml:"triggerSocket,omitempty"`
 }
 
 // UpdateFromCLIFlags updates Flags from settings in the cli Flags if they are set.
@@ -160,6 +172,12 @@ func (f *Flags) UpdateFromCLIFlags(c *cli.Context, flags []cli.Flag) {
 				updateFromCLIFlag(&f.Plugin.ContainerDriverRoot, c, n)
 			case "shared-devices-allocation-policy":
 				updateFromCLIFlag(&f.Plugin.SharedDevicesAllocationPolicy, c, n)
+			case "health-recovery-policy":
+				updateFromCLIFlag(&f.Plugin.HealthRecoveryPolicy, c, n)
+			case "health-recovery-quiet-period":
+				updateFromCLIFlag(&f.Plugin.HealthRecoveryQuietPeriod, c, n)
+			case "cc-mode":
+				updateFromCLIFlag(&f.Plugin.CCMode, c, n)
 			}
 			// GFD specific flags
 			if f.GFD == nil {
@@ -176,6 +194,12 @@ func (f *Flags) UpdateFromCLIFlags(c *cli.Context, flags []cli.Flag) {
 				updateFromCLIFlag(&f.GFD.NoTimestamp, c, n)
 			case "machine-type-file":
 				updateFromCLIFlag(&f.GFD.MachineTypeFile, c, n)
+			case "device-labels":
+				updateFromCLIFlag(&f.GFD.DeviceLabels, c, n)
+			case "mig-poll-interval":
+				updateFromCLIFlag(&f.GFD.MigPollInterval, c, n)
+			case "trigger-socket":
+				updateFromCLIFlag(&f.GFD.TriggerSocket, c, n)
 			}
 		}
 	}
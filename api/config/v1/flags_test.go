@@ -129,7 +129,7 @@ func TestUnmarshalFlags(t *testing.T) {
 			output: Flags{
 				CommandLineFlags{
 					Plugin: &PluginCommandLineFlags{
-						DeviceListStrategy: &deviceListStrategyFlag{"envvar"},
+						DeviceListStrategy: &DeviceListStrategyFlag{"envvar"},
 					},
 				},
 			},
@@ -143,7 +143,7 @@ func TestUnmarshalFlags(t *testing.T) {
 			output: Flags{
 				CommandLineFlags{
 					Plugin: &PluginCommandLineFlags{
-						DeviceListStrategy: &deviceListStrategyFlag{"envvar", "cdi-annotations"},
+						DeviceListStrategy: &DeviceListStrategyFlag{"envvar", "cdi-annotations"},
 					},
 				},
 			},
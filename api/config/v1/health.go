@@ -0,0 +1,407 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// envDisableHealthChecks defines the environment variable that is checked to determine whether healthchecks
+	// should be disabled. If this envvar is set to "all" or contains the string "xids", healthchecks are
+	// disabled entirely. If set, the envvar is treated as a comma-separated list of Xids to ignore. Note that
+	// this is in addition to the Application errors that are already ignored.
+	// If set, this overrides healthChecks.disabledXIDs in the config file.
+	envDisableHealthChecks = "DP_DISABLE_HEALTHCHECKS"
+	// envEnableHealthChecks defines the environment variable that is checked to
+	// determine which XIDs should be explicitly enabled. XIDs specified here
+	// override the ones specified in the `DP_DISABLE_HEALTHCHECKS`.
+	// Note that this also allows individual XIDs to be selected when ALL XIDs
+	// are disabled.
+	// If set, this overrides healthChecks.enabledXIDs in the config file.
+	envEnableHealthChecks = "DP_ENABLE_HEALTHCHECKS"
+)
+
+// HealthEventType represents a type of NVML event that is considered for health checks.
+type HealthEventType string
+
+// Constants representing the supported health event types
+const (
+	HealthEventTypeXid          = HealthEventType("xid")
+	HealthEventTypeDoubleBitEcc = HealthEventType("double-bit-ecc")
+	HealthEventTypeSingleBitEcc = HealthEventType("single-bit-ecc")
+)
+
+// DefaultHealthEventTypes returns the event types that are watched if none are specified.
+func DefaultHealthEventTypes() []HealthEventType {
+	return []HealthEventType{
+		HealthEventTypeXid,
+		HealthEventTypeDoubleBitEcc,
+		HealthEventTypeSingleBitEcc,
+	}
+}
+
+// HealthChecks defines the health checks performed on the devices managed by the device plugin.
+type HealthChecks struct {
+	// DisabledXIDs defines the XIDs that are ignored when checking device health.
+	// These are in addition to the application XIDs that are always ignored.
+	// The special value "all" disables all XID-based health checks.
+	DisabledXIDs *XIDs `json:"disabledXIDs,omitempty" yaml:"disabledXIDs,omitempty"`
+	// EnabledXIDs defines the XIDs that are explicitly enabled. These take
+	// precedence over disabled XIDs and allow specific XIDs to be selected
+	// even if all XIDs are disabled.
+	EnabledXIDs *XIDs `json:"enabledXIDs,omitempty"  yaml:"enabledXIDs,omitempty"`
+	// EventTypes defines the event types that are watched. If this is empty,
+	// all supported event types are watched.
+	EventTypes []HealthEventType `json:"eventTypes,omitempty"   yaml:"eventTypes,omitempty"`
+	// Rules defines additional health rules that are evaluated by polling
+	// the device state.
+	Rules *HealthRules `json:"rules,omitempty"        yaml:"rules,omitempty"`
+	// Resources defines per-resource overrides for the settings above.
+	Resources []ResourceHealthChecks `json:"resources,omitempty"    yaml:"resources,omitempty"`
+}
+
+// ResourceHealthChecks overrides the health checks for a specific resource.
+// Fields that are not set are inherited from the top-level health checks.
+type ResourceHealthChecks struct {
+	Name         ResourceName      `json:"name"                   yaml:"name"`
+	DisabledXIDs *XIDs             `json:"disabledXIDs,omitempty" yaml:"disabledXIDs,omitempty"`
+	EnabledXIDs  *XIDs             `json:"enabledXIDs,omitempty"  yaml:"enabledXIDs,omitempty"`
+	EventTypes   []HealthEventType `json:"eventTypes,omitempty"   yaml:"eventTypes,omitempty"`
+	Rules        *HealthRules      `json:"rules,omitempty"        yaml:"rules,omitempty"`
+}
+
+// HealthRules defines the health rules that are evaluated by polling the
+// state of each device. A rule is only evaluated if it is specified.
+type HealthRules struct {
+	// PollInterval defines how often the rules are evaluated.
+	PollInterval  *Duration          `json:"pollInterval,omitempty"  yaml:"pollInterval,omitempty"`
+	DoubleBitECC  *DoubleBitECCRule  `json:"doubleBitECC,omitempty"  yaml:"doubleBitECC,omitempty"`
+	RetiredPages  *RetiredPagesRule  `json:"retiredPages,omitempty"  yaml:"retiredPages,omitempty"`
+	RemappedRows  *RemappedRowsRule  `json:"remappedRows,omitempty"  yaml:"remappedRows,omitempty"`
+	Temperature   *TemperatureRule   `json:"temperature,omitempty"   yaml:"temperature,omitempty"`
+	Power         *PowerRule         `json:"power,omitempty"         yaml:"power,omitempty"`
+	ClockThrottle *ClockThrottleRule `json:"clockThrottle,omitempty" yaml:"clockThrottle,omitempty"`
+}
+
+// DoubleBitECCRule marks a device unhealthy if the number of volatile
+// uncorrected (double-bit) ECC errors reaches the threshold. Double-bit ECC
+// error events also mark a device unhealthy if this rule is specified.
+type DoubleBitECCRule struct {
+	Threshold uint64 `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+// RetiredPagesRule marks a device unhealthy if the number of pages retired due
+// to ECC errors reaches the threshold. If Pending is set, a device with pages
+// pending retirement is also marked unhealthy.
+type RetiredPagesRule struct {
+	Threshold int  `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+	Pending   bool `json:"pending,omitempty"   yaml:"pending,omitempty"`
+}
+
+// RemappedRowsRule marks a device unhealthy if a row remapping failure has
+// occurred or the number of rows remapped due to uncorrectable errors reaches
+// the threshold. If Pending is set, a device with pending row remappings is
+// also marked unhealthy.
+type RemappedRowsRule struct {
+	UncorrectableThreshold int  `json:"uncorrectableThreshold,omitempty" yaml:"uncorrectableThreshold,omitempty"`
+	Pending                bool `json:"pending,omitempty"                yaml:"pending,omitempty"`
+}
+
+// TemperatureRule marks a device unhealthy if its temperature reaches
+// MaxCelsius. If MaxCelsius is not set, the slowdown threshold reported by the
+// device is used.
+type TemperatureRule struct {
+	MaxCelsius uint32 `json:"maxCelsius,omitempty" yaml:"maxCelsius,omitempty"`
+}
+
+// PowerRule marks a device unhealthy if its power usage exceeds the specified
+// percentage of the enforced power limit.
+type PowerRule struct {
+	MaxPercentOfLimit uint32 `json:"maxPercentOfLimit,omitempty" yaml:"maxPercentOfLimit,omitempty"`
+}
+
+// ClockThrottleRule marks a device unhealthy if its clocks are throttled for
+// any of the specified reasons.
+type ClockThrottleRule struct {
+	Reasons []ClockThrottleReason `json:"reasons,omitempty" yaml:"reasons,omitempty"`
+}
+
+// ClockThrottleReason represents a reason for which device clocks are throttled.
+type ClockThrottleReason string
+
+// Constants representing the supported clock throttle reasons
+const (
+	ClockThrottleReasonHwSlowdown           = ClockThrottleReason("hw-slowdown")
+	ClockThrottleReasonHwThermalSlowdown    = ClockThrottleReason("hw-thermal-slowdown")
+	ClockThrottleReasonHwPowerBrakeSlowdown = ClockThrottleReason("hw-power-brake-slowdown")
+	ClockThrottleReasonSwThermalSlowdown    = ClockThrottleReason("sw-thermal-slowdown")
+	ClockThrottleReasonSwPowerCap           = ClockThrottleReason("sw-power-cap")
+)
+
+// DefaultHealthRulesPollInterval is the default interval at which health rules are evaluated.
+const DefaultHealthRulesPollInterval = 30 * time.Second
+
+// XIDs represents a set of XIDs. If All is set, the set matches all XIDs.
+type XIDs struct {
+	All bool
+	IDs []uint64
+}
+
+// ForResource returns the health checks that apply to the specified resource.
+// The returned HealthChecks has all per-resource overrides applied.
+func (h *HealthChecks) ForResource(name ResourceName) HealthChecks {
+	effective := HealthChecks{
+		DisabledXIDs: h.DisabledXIDs,
+		EnabledXIDs:  h.EnabledXIDs,
+		EventTypes:   h.EventTypes,
+		Rules:        h.Rules,
+	}
+	for _, r := range h.Resources {
+		if r.Name != name {
+			continue
+		}
+		if r.DisabledXIDs != nil {
+			effective.DisabledXIDs = r.DisabledXIDs
+		}
+		if r.EnabledXIDs != nil {
+			effective.EnabledXIDs = r.EnabledXIDs
+		}
+		if len(r.EventTypes) > 0 {
+			effective.EventTypes = r.EventTypes
+		}
+		if r.Rules != nil {
+			effective.Rules = r.Rules
+		}
+	}
+	if len(effective.EventTypes) == 0 {
+		effective.EventTypes = DefaultHealthEventTypes()
+	}
+	return effective
+}
+
+// WatchesEventType checks whether the specified event type is watched.
+func (h *HealthChecks) WatchesEventType(eventType HealthEventType) bool {
+	eventTypes := h.EventTypes
+	if len(eventTypes) == 0 {
+		eventTypes = DefaultHealthEventTypes()
+	}
+	for _, e := range eventTypes {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// updateFromEnvvars updates the XIDs from the (legacy) environment variables if these are set.
+// Since the environment variables take precedence over the config file, they
+// also replace the XIDs of any per-resource overrides.
+func (h *HealthChecks) updateFromEnvvars() {
+	if value, exists := os.LookupEnv(envDisableHealthChecks); exists {
+		h.DisabledXIDs = newXIDsFromEnvvar(value)
+		for i := range h.Resources {
+			h.Resources[i].DisabledXIDs = nil
+		}
+	}
+	if value, exists := os.LookupEnv(envEnableHealthChecks); exists {
+		h.EnabledXIDs = newXIDsFromEnvvar(value)
+		for i := range h.Resources {
+			h.Resources[i].EnabledXIDs = nil
+		}
+	}
+}
+
+// newXIDsFromEnvvar converts a comma-separated list of XIDs to an XIDs struct.
+// Special xid values 'all' and 'xids' return an XIDs struct that matches all
+// xids.
+// For other xids, these are converted to a uint64 values with invalid values
+// being ignored.
+func newXIDsFromEnvvar(value string) *XIDs {
+	xids := &XIDs{}
+	for _, xid := range strings.Split(strings.ToLower(value), ",") {
+		trimmed := strings.TrimSpace(xid)
+		if trimmed == "all" || trimmed == "xids" {
+			return &XIDs{All: true}
+		}
+		if trimmed == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(trimmed, 10, 64)
+		if err != nil {
+			klog.Infof("Ignoring malformed Xid value %v: %v", trimmed, err)
+			continue
+		}
+		xids.IDs = append(xids.IDs, id)
+	}
+	return xids
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'HealthChecks' struct.
+// Per-resource overrides are validated to reference unique resources.
+func (h *HealthChecks) UnmarshalJSON(b []byte) error {
+	type healthChecks HealthChecks
+	var hc healthChecks
+	if err := json.Unmarshal(b, &hc); err != nil {
+		return err
+	}
+
+	seen := make(map[ResourceName]bool)
+	for _, r := range hc.Resources {
+		if seen[r.Name] {
+			return invalidFieldError("resources", fmt.Errorf("duplicate health check overrides for resource %q", r.Name))
+		}
+		seen[r.Name] = true
+	}
+
+	*h = HealthChecks(hc)
+	return nil
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'ResourceHealthChecks' struct.
+func (r *ResourceHealthChecks) UnmarshalJSON(b []byte) error {
+	type resourceHealthChecks ResourceHealthChecks
+	var rhc resourceHealthChecks
+	if err := json.Unmarshal(b, &rhc); err != nil {
+		return err
+	}
+	if rhc.Name == "" {
+		return requiredFieldError("name", fmt.Errorf("no resource name specified"))
+	}
+	*r = ResourceHealthChecks(rhc)
+	return nil
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'HealthEventType'.
+func (e *HealthEventType) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	eventType := HealthEventType(raw)
+	for _, supported := range DefaultHealthEventTypes() {
+		if eventType == supported {
+			*e = eventType
+			return nil
+		}
+	}
+	return fmt.Errorf("unsupported health event type: %q", raw)
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'HealthRules' struct.
+func (r *HealthRules) UnmarshalJSON(b []byte) error {
+	type healthRules HealthRules
+	var hr healthRules
+	if err := json.Unmarshal(b, &hr); err != nil {
+		return err
+	}
+	if hr.PollInterval != nil && *hr.PollInterval <= 0 {
+		return invalidFieldError("pollInterval", fmt.Errorf("pollInterval must be > 0"))
+	}
+	if hr.RetiredPages != nil && hr.RetiredPages.Threshold < 0 {
+		return invalidFieldError("retiredPages.threshold", fmt.Errorf("retiredPages.threshold must be >= 0"))
+	}
+	if hr.RemappedRows != nil && hr.RemappedRows.UncorrectableThreshold < 0 {
+		return invalidFieldError("remappedRows.uncorrectableThreshold", fmt.Errorf("remappedRows.uncorrectableThreshold must be >= 0"))
+	}
+	if hr.Power != nil && hr.Power.MaxPercentOfLimit == 0 {
+		return invalidFieldError("power.maxPercentOfLimit", fmt.Errorf("power.maxPercentOfLimit must be > 0"))
+	}
+	if hr.ClockThrottle != nil && len(hr.ClockThrottle.Reasons) == 0 {
+		return invalidFieldError("clockThrottle.reasons", fmt.Errorf("clockThrottle.reasons must not be empty"))
+	}
+	*r = HealthRules(hr)
+	return nil
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'ClockThrottleReason'.
+func (c *ClockThrottleReason) UnmarshalJSON(b []byte) error {
+	var raw string
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	switch reason := ClockThrottleReason(raw); reason {
+	case ClockThrottleReasonHwSlowdown,
+		ClockThrottleReasonHwThermalSlowdown,
+		ClockThrottleReasonHwPowerBrakeSlowdown,
+		ClockThrottleReasonSwThermalSlowdown,
+		ClockThrottleReasonSwPowerCap:
+		*c = reason
+		return nil
+	}
+	return fmt.Errorf("unsupported clock throttle reason: %q", raw)
+}
+
+// UnmarshalJSON unmarshals raw bytes into an 'XIDs' struct.
+// XIDs can be specified as the string 'all' or as a list of XIDs. A list of
+// XIDs may also include the special value 'all'.
+func (x *XIDs) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err == nil {
+		if str != "all" {
+			return fmt.Errorf("xids set as '%v' but the only valid string input is 'all'", str)
+		}
+		*x = XIDs{All: true}
+		return nil
+	}
+
+	var list []json.RawMessage
+	if err := json.Unmarshal(b, &list); err != nil {
+		return fmt.Errorf("unrecognized type for xids: %v", string(b))
+	}
+
+	xids := XIDs{IDs: []uint64{}}
+	for _, item := range list {
+		var id uint64
+		if err := json.Unmarshal(item, &id); err == nil {
+			xids.IDs = append(xids.IDs, id)
+			continue
+		}
+		var str string
+		if err := json.Unmarshal(item, &str); err == nil {
+			if str == "all" {
+				*x = XIDs{All: true}
+				return nil
+			}
+			if id, err := strconv.ParseUint(str, 10, 64); err == nil {
+				xids.IDs = append(xids.IDs, id)
+				continue
+			}
+		}
+		return fmt.Errorf("invalid xid: %v", string(item))
+	}
+	*x = xids
+	return nil
+}
+
+// MarshalJSON marshals 'XIDs' to its raw bytes representation.
+func (x XIDs) MarshalJSON() ([]byte, error) {
+	if x.All {
+		return json.Marshal("all")
+	}
+	if x.IDs == nil {
+		return json.Marshal([]uint64{})
+	}
+	return json.Marshal(x.IDs)
+}
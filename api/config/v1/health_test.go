@@ -0,0 +1,381 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewXIDsFromEnvvar(t *testing.T) {
+	testCases := []struct {
+		input    string
+		expected *XIDs
+	}{
+		{
+			expected: &XIDs{},
+		},
+		{
+			input:    ",",
+			expected: &XIDs{},
+		},
+		{
+			input:    "not-an-int",
+			expected: &XIDs{},
+		},
+		{
+			input:    "68",
+			expected: &XIDs{IDs: []uint64{68}},
+		},
+		{
+			input:    "-68",
+			expected: &XIDs{},
+		},
+		{
+			input:    "68  ",
+			expected: &XIDs{IDs: []uint64{68}},
+		},
+		{
+			input:    "68,",
+			expected: &XIDs{IDs: []uint64{68}},
+		},
+		{
+			input:    ",68",
+			expected: &XIDs{IDs: []uint64{68}},
+		},
+		{
+			input:    "68,67",
+			expected: &XIDs{IDs: []uint64{68, 67}},
+		},
+		{
+			input:    "68,not-an-int,67",
+			expected: &XIDs{IDs: []uint64{68, 67}},
+		},
+		{
+			input:    "all",
+			expected: &XIDs{All: true},
+		},
+		{
+			input:    "68,XIDS",
+			expected: &XIDs{All: true},
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			require.EqualValues(t, tc.expected, newXIDsFromEnvvar(tc.input))
+		})
+	}
+}
+
+func TestUnmarshalHealthChecks(t *testing.T) {
+	testCases := []struct {
+		description string
+		input       string
+		output      HealthChecks
+		err         bool
+	}{
+		{
+			description: "empty",
+			input:       `{}`,
+			output:      HealthChecks{},
+		},
+		{
+			description: "xids as list",
+			input: `{
+				"disabledXIDs": [79, "48"],
+				"enabledXIDs": []
+			}`,
+			output: HealthChecks{
+				DisabledXIDs: &XIDs{IDs: []uint64{79, 48}},
+				EnabledXIDs:  &XIDs{IDs: []uint64{}},
+			},
+		},
+		{
+			description: "xids as all",
+			input: `{
+				"disabledXIDs": "all",
+				"enabledXIDs": [79]
+			}`,
+			output: HealthChecks{
+				DisabledXIDs: &XIDs{All: true},
+				EnabledXIDs:  &XIDs{IDs: []uint64{79}},
+			},
+		},
+		{
+			description: "all in xid list",
+			input: `{
+				"disabledXIDs": [79, "all"]
+			}`,
+			output: HealthChecks{
+				DisabledXIDs: &XIDs{All: true},
+			},
+		},
+		{
+			description: "invalid xid string",
+			input: `{
+				"disabledXIDs": "79"
+			}`,
+			err: true,
+		},
+		{
+			description: "invalid xid in list",
+			input: `{
+				"disabledXIDs": [79, "not-an-int"]
+			}`,
+			err: true,
+		},
+		{
+			description: "negative xid in list",
+			input: `{
+				"disabledXIDs": [-79]
+			}`,
+			err: true,
+		},
+		{
+			description: "event types",
+			input: `{
+				"eventTypes": ["xid", "double-bit-ecc"]
+			}`,
+			output: HealthChecks{
+				EventTypes: []HealthEventType{HealthEventTypeXid, HealthEventTypeDoubleBitEcc},
+			},
+		},
+		{
+			description: "unsupported event type",
+			input: `{
+				"eventTypes": ["xid", "thermal"]
+			}`,
+			err: true,
+		},
+		{
+			description: "rules",
+			input: `{
+				"rules": {
+					"pollInterval": "1m",
+					"doubleBitECC": {},
+					"retiredPages": {"threshold": 60, "pending": true},
+					"temperature": {"maxCelsius": 90},
+					"clockThrottle": {"reasons": ["hw-slowdown", "sw-power-cap"]}
+				}
+			}`,
+			output: HealthChecks{
+				Rules: &HealthRules{
+					PollInterval: ptr(Duration(time.Minute)),
+					DoubleBitECC: &DoubleBitECCRule{},
+					RetiredPages: &RetiredPagesRule{Threshold: 60, Pending: true},
+					Temperature:  &TemperatureRule{MaxCelsius: 90},
+					ClockThrottle: &ClockThrottleRule{
+						Reasons: []ClockThrottleReason{ClockThrottleReasonHwSlowdown, ClockThrottleReasonSwPowerCap},
+					},
+				},
+			},
+		},
+		{
+			description: "rules with invalid poll interval",
+			input: `{
+				"rules": {"pollInterval": "0s"}
+			}`,
+			err: true,
+		},
+		{
+			description: "power rule without limit",
+			input: `{
+				"rules": {"power": {}}
+			}`,
+			err: true,
+		},
+		{
+			description: "clock throttle rule without reasons",
+			input: `{
+				"rules": {"clockThrottle": {"reasons": []}}
+			}`,
+			err: true,
+		},
+		{
+			description: "unsupported clock throttle reason",
+			input: `{
+				"rules": {"clockThrottle": {"reasons": ["display-clock-setting"]}}
+			}`,
+			err: true,
+		},
+		{
+			description: "resource overrides",
+			input: `{
+				"disabledXIDs": [79],
+				"resources": [
+					{
+						"name": "gpu",
+						"disabledXIDs": "all"
+					}
+				]
+			}`,
+			output: HealthChecks{
+				DisabledXIDs: &XIDs{IDs: []uint64{79}},
+				Resources: []ResourceHealthChecks{
+					{
+						Name:         "nvidia.com/gpu",
+						DisabledXIDs: &XIDs{All: true},
+					},
+				},
+			},
+		},
+		{
+			description: "resource override without name",
+			input: `{
+				"resources": [
+					{
+						"disabledXIDs": "all"
+					}
+				]
+			}`,
+			err: true,
+		},
+		{
+			description: "duplicate resource overrides",
+			input: `{
+				"resources": [
+					{
+						"name": "gpu",
+						"disabledXIDs": "all"
+					},
+					{
+						"name": "nvidia.com/gpu",
+						"enabledXIDs": [79]
+					}
+				]
+			}`,
+			err: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var output HealthChecks
+			err := json.Unmarshal([]byte(tc.input), &output)
+			if tc.err {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.output, output)
+		})
+	}
+}
+
+func TestMarshalHealthChecks(t *testing.T) {
+	input := HealthChecks{
+		DisabledXIDs: &XIDs{All: true},
+		EnabledXIDs:  &XIDs{IDs: []uint64{79, 48}},
+		EventTypes:   []HealthEventType{HealthEventTypeXid},
+	}
+
+	output, err := json.Marshal(input)
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"disabledXIDs": "all",
+		"enabledXIDs": [79, 48],
+		"eventTypes": ["xid"]
+	}`, string(output))
+}
+
+func TestHealthChecksForResource(t *testing.T) {
+	healthChecks := HealthChecks{
+		DisabledXIDs: &XIDs{IDs: []uint64{79}},
+		Resources: []ResourceHealthChecks{
+			{
+				Name:         "nvidia.com/gpu.shared",
+				DisabledXIDs: &XIDs{All: true},
+				EnabledXIDs:  &XIDs{IDs: []uint64{48}},
+			},
+			{
+				Name:       "nvidia.com/mig-1g.10gb",
+				EventTypes: []HealthEventType{HealthEventTypeXid},
+			},
+		},
+	}
+
+	require.Equal(t,
+		HealthChecks{
+			DisabledXIDs: &XIDs{IDs: []uint64{79}},
+			EventTypes:   DefaultHealthEventTypes(),
+		},
+		healthChecks.ForResource("nvidia.com/gpu"),
+	)
+	require.Equal(t,
+		HealthChecks{
+			DisabledXIDs: &XIDs{All: true},
+			EnabledXIDs:  &XIDs{IDs: []uint64{48}},
+			EventTypes:   DefaultHealthEventTypes(),
+		},
+		healthChecks.ForResource("nvidia.com/gpu.shared"),
+	)
+	require.Equal(t,
+		HealthChecks{
+			DisabledXIDs: &XIDs{IDs: []uint64{79}},
+			EventTypes:   []HealthEventType{HealthEventTypeXid},
+		},
+		healthChecks.ForResource("nvidia.com/mig-1g.10gb"),
+	)
+}
+
+func TestNewConfigHealthChecksFromEnvvars(t *testing.T) {
+	t.Setenv(envDisableHealthChecks, "all")
+	t.Setenv(envEnableHealthChecks, "79")
+
+	config, err := newConfigForTest(t, `
+version: v1
+healthChecks:
+  disabledXIDs: [48]
+  eventTypes: [xid]
+`)
+	require.NoError(t, err)
+	require.Equal(t,
+		HealthChecks{
+			DisabledXIDs: &XIDs{All: true},
+			EnabledXIDs:  &XIDs{IDs: []uint64{79}},
+			EventTypes:   []HealthEventType{HealthEventTypeXid},
+		},
+		config.HealthChecks,
+	)
+}
+
+func TestNewConfigHealthChecksFromEnvvarsOverrideResources(t *testing.T) {
+	t.Setenv(envDisableHealthChecks, "all")
+
+	config, err := newConfigForTest(t, `
+version: v1
+healthChecks:
+  resources:
+  - name: nvidia.com/gpu
+    disabledXIDs: [48]
+    enabledXIDs: [79]
+`)
+	require.NoError(t, err)
+	require.Equal(t,
+		HealthChecks{
+			DisabledXIDs: &XIDs{All: true},
+			EnabledXIDs:  &XIDs{IDs: []uint64{79}},
+			EventTypes:   DefaultHealthEventTypes(),
+		},
+		config.HealthChecks.ForResource("nvidia.com/gpu"),
+	)
+}
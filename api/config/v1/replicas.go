@@ -23,7 +23,7 @@ import (
 	"strings"
 
 	"github.com/google/uuid"
-	"k8s.io/klog/v2"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // ReplicatedResources defines generic options for replicating devices.
@@ -33,38 +33,6 @@ type ReplicatedResources struct {
 	Resources                  []ReplicatedResource `json:"resources,omitempty"                  yaml:"resources,omitempty"`
 }
 
-func (rrs *ReplicatedResources) disableResoureRenaming(id string) {
-	if rrs == nil {
-		return
-	}
-	renameByDefault := rrs.RenameByDefault
-	setsNonDefaultRename := false
-	setsDevices := false
-	for i, r := range rrs.Resources {
-		if !renameByDefault && r.Rename != "" {
-			setsNonDefaultRename = true
-			rrs.Resources[i].Rename = ""
-		}
-		if renameByDefault && r.Rename != r.Name.DefaultSharedRename() {
-			setsNonDefaultRename = true
-			rrs.Resources[i].Rename = r.Name.DefaultSharedRename()
-		}
-		if !r.Devices.All {
-			setsDevices = true
-			rrs.Resources[i].Devices.All = true
-			rrs.Resources[i].Devices.Count = 0
-			rrs.Resources[i].Devices.List = nil
-		}
-	}
-	if setsNonDefaultRename {
-		klog.Warningf("Setting the 'rename' field in sharing.%s.resources is not yet supported in the config. Ignoring...", id)
-	}
-	if setsDevices {
-		klog.Warningf("Customizing the 'devices' field in sharing.%s.resources is not yet supported in the config. Ignoring...", id)
-	}
-
-}
-
 func (rrs *ReplicatedResources) isReplicated() bool {
 	if rrs == nil {
 		return false
@@ -83,6 +51,34 @@ type ReplicatedResource struct {
 	Rename   ResourceName      `json:"rename,omitempty" yaml:"rename,omitempty"`
 	Devices  ReplicatedDevices `json:"devices"          yaml:"devices,flow"`
 	Replicas int               `json:"replicas"         yaml:"replicas"`
+	// DeviceReplicas optionally overrides the number of replicas for specific
+	// devices. Devices not referenced here use Replicas.
+	DeviceReplicas []DeviceReplicas `json:"deviceReplicas,omitempty" yaml:"deviceReplicas,omitempty"`
+	// MemoryLimit optionally defines the amount of device memory each replica
+	// is limited to. This is only supported for time-slicing.
+	MemoryLimit *ReplicaMemoryLimit `json:"memoryLimit,omitempty" yaml:"memoryLimit,omitempty"`
+	// ActiveThreadPercentage optionally defines the percentage of the SMs of a
+	// device that each replica may use. This is only supported for MPS.
+	ActiveThreadPercentage *int `json:"activeThreadPercentage,omitempty" yaml:"activeThreadPercentage,omitempty"`
+	// PinnedMemoryLimit optionally defines the amount of device memory each
+	// replica may pin. This is only supported for MPS.
+	PinnedMemoryLimit *ReplicaMemoryLimit `json:"pinnedMemoryLimit,omitempty" yaml:"pinnedMemoryLimit,omitempty"`
+}
+
+// DeviceReplicas overrides the number of replicas for a list of devices.
+type DeviceReplicas struct {
+	Devices  ReplicatedDevices `json:"devices"  yaml:"devices,flow"`
+	Replicas int               `json:"replicas" yaml:"replicas"`
+}
+
+// ReplicaMemoryLimit defines the memory limit for each replica of a device.
+// This struct should be treated as a 'union' and only one of the fields in this
+// struct should be set at any given time.
+type ReplicaMemoryLimit struct {
+	// Percent is the percentage of the total memory of a device.
+	Percent int
+	// Bytes is an absolute limit in bytes.
+	Bytes uint64
 }
 
 // ReplicatedDevices encapsulates the set of devices that should be replicated for a given resource.
@@ -189,7 +185,7 @@ func (s *ReplicatedResources) UnmarshalJSON(b []byte) error {
 
 	resources, exists := ts["resources"]
 	if !exists {
-		return fmt.Errorf("no resources specified")
+		return requiredFieldError("resources", fmt.Errorf("no resources specified"))
 	}
 
 	err = json.Unmarshal(resources, &s.Resources)
@@ -198,13 +194,20 @@ func (s *ReplicatedResources) UnmarshalJSON(b []byte) error {
 	}
 
 	if len(s.Resources) == 0 {
-		return fmt.Errorf("no resources specified")
+		return invalidFieldError("resources", fmt.Errorf("no resources specified"))
 	}
 
 	for i, r := range s.Resources {
 		if s.RenameByDefault && r.Rename == "" {
 			s.Resources[i].Rename = r.Name.DefaultSharedRename()
 		}
+		// If only some of the devices are shared, the shared devices must be
+		// advertised under a different name than the devices that are not.
+		// Configs that predate the devices selector did not need a rename, so
+		// the default shared name is used instead of rejecting them.
+		if !r.Devices.All && s.Resources[i].Rename == "" {
+			s.Resources[i].Rename = r.Name.DefaultSharedRename()
+		}
 	}
 
 	return nil
@@ -220,7 +223,7 @@ func (s *ReplicatedResource) UnmarshalJSON(b []byte) error {
 
 	name, exists := rr["name"]
 	if !exists {
-		return fmt.Errorf("no resource name specified")
+		return requiredFieldError("name", fmt.Errorf("no resource name specified"))
 	}
 
 	err = json.Unmarshal(name, &s.Name)
@@ -240,7 +243,7 @@ func (s *ReplicatedResource) UnmarshalJSON(b []byte) error {
 
 	replicas, exists := rr["replicas"]
 	if !exists {
-		return fmt.Errorf("no replicas specified")
+		return requiredFieldError("replicas", fmt.Errorf("no replicas specified"))
 	}
 
 	err = json.Unmarshal(replicas, &s.Replicas)
@@ -249,7 +252,38 @@ func (s *ReplicatedResource) UnmarshalJSON(b []byte) error {
 	}
 
 	if s.Replicas < 2 {
-		return fmt.Errorf("number of replicas must be >= 2")
+		return invalidFieldError("replicas", fmt.Errorf("number of replicas must be >= 2"))
+	}
+
+	if deviceReplicas, exists := rr["deviceReplicas"]; exists {
+		err = json.Unmarshal(deviceReplicas, &s.DeviceReplicas)
+		if err != nil {
+			return err
+		}
+	}
+
+	if memoryLimit, exists := rr["memoryLimit"]; exists {
+		err = json.Unmarshal(memoryLimit, &s.MemoryLimit)
+		if err != nil {
+			return err
+		}
+	}
+
+	if activeThreadPercentage, exists := rr["activeThreadPercentage"]; exists {
+		err = json.Unmarshal(activeThreadPercentage, &s.ActiveThreadPercentage)
+		if err != nil {
+			return err
+		}
+		if p := *s.ActiveThreadPercentage; p <= 0 || p > 100 {
+			return invalidFieldError("activeThreadPercentage", fmt.Errorf("activeThreadPercentage set as '%v' but must be in the range (0, 100]", p))
+		}
+	}
+
+	if pinnedMemoryLimit, exists := rr["pinnedMemoryLimit"]; exists {
+		err = json.Unmarshal(pinnedMemoryLimit, &s.PinnedMemoryLimit)
+		if err != nil {
+			return err
+		}
 	}
 
 	rename, exists := rr["rename"]
@@ -265,6 +299,113 @@ func (s *ReplicatedResource) UnmarshalJSON(b []byte) error {
 	return nil
 }
 
+// ReplicasFor returns the number of replicas for the device identified by the
+// specified references (e.g. its index and UUID). If no override matches the
+// device, the number of replicas for the resource is returned.
+func (s *ReplicatedResource) ReplicasFor(refs ...string) int {
+	for _, dr := range s.DeviceReplicas {
+		for _, d := range dr.Devices.List {
+			for _, ref := range refs {
+				if ref != "" && string(d) == ref {
+					return dr.Replicas
+				}
+			}
+		}
+	}
+	return s.Replicas
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'DeviceReplicas' struct.
+func (s *DeviceReplicas) UnmarshalJSON(b []byte) error {
+	dr := make(map[string]json.RawMessage)
+	err := json.Unmarshal(b, &dr)
+	if err != nil {
+		return err
+	}
+
+	devices, exists := dr["devices"]
+	if !exists {
+		return requiredFieldError("devices", fmt.Errorf("no devices specified for replica override"))
+	}
+
+	err = json.Unmarshal(devices, &s.Devices)
+	if err != nil {
+		return err
+	}
+
+	if len(s.Devices.List) == 0 {
+		return invalidFieldError("devices", fmt.Errorf("devices for a replica override must be a list of device indices or UUIDs"))
+	}
+
+	replicas, exists := dr["replicas"]
+	if !exists {
+		return requiredFieldError("replicas", fmt.Errorf("no replicas specified for replica override"))
+	}
+
+	err = json.Unmarshal(replicas, &s.Replicas)
+	if err != nil {
+		return err
+	}
+
+	if s.Replicas < 2 {
+		return invalidFieldError("replicas", fmt.Errorf("number of replicas must be >= 2"))
+	}
+
+	return nil
+}
+
+// ForDevice returns the memory limit in bytes for a replica of a device with
+// the specified total memory. An absolute limit is capped at the total memory
+// of the device if this is known.
+func (l *ReplicaMemoryLimit) ForDevice(totalMemory uint64) uint64 {
+	if l == nil {
+		return 0
+	}
+	if l.Percent > 0 {
+		return totalMemory * uint64(l.Percent) / 100
+	}
+	if totalMemory > 0 && l.Bytes > totalMemory {
+		return totalMemory
+	}
+	return l.Bytes
+}
+
+// UnmarshalJSON unmarshals raw bytes into a 'ReplicaMemoryLimit' struct.
+// A limit is specified as a percentage (e.g. "25%") or as a quantity (e.g. "4Gi").
+func (l *ReplicaMemoryLimit) UnmarshalJSON(b []byte) error {
+	var str string
+	if err := json.Unmarshal(b, &str); err != nil {
+		return fmt.Errorf("memoryLimit must be a percentage or a quantity: %v", string(b))
+	}
+
+	if percent, found := strings.CutSuffix(str, "%"); found {
+		value, err := strconv.Atoi(percent)
+		if err != nil || value <= 0 || value > 100 {
+			return fmt.Errorf("memoryLimit set as '%v' but a percentage must be in the range (0, 100]", str)
+		}
+		*l = ReplicaMemoryLimit{Percent: value}
+		return nil
+	}
+
+	quantity, err := resource.ParseQuantity(str)
+	if err != nil {
+		return fmt.Errorf("invalid memoryLimit '%v': %w", str, err)
+	}
+	if quantity.Sign() <= 0 {
+		return fmt.Errorf("memoryLimit set as '%v' but a quantity must be > 0", str)
+	}
+	*l = ReplicaMemoryLimit{Bytes: uint64(quantity.Value())}
+	return nil
+}
+
+// MarshalJSON marshals a 'ReplicaMemoryLimit' to its raw bytes representation.
+func (l ReplicaMemoryLimit) MarshalJSON() ([]byte, error) {
+	if l.Percent > 0 {
+		return json.Marshal(fmt.Sprintf("%d%%", l.Percent))
+	}
+	return json.Marshal(resource.NewQuantity(int64(l.Bytes), resource.BinarySI).String())
+}
+
 // UnmarshalJSON unmarshals raw bytes into a 'ReplicatedDevices' struct.
 func (s *ReplicatedDevices) UnmarshalJSON(b []byte) error {
 	// Match the string 'all'
@@ -17,6 +17,7 @@
 package v1
 
 import (
+	"encoding/json"
 	"fmt"
 	"testing"
 
@@ -355,6 +356,149 @@ func TestUnmarshalReplicatedResource(t *testing.T) {
 			}`,
 			err: true,
 		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": "25%"
+			}`,
+			output: ReplicatedResource{
+				Name:        NoErrorNewResourceName("valid"),
+				Devices:     ReplicatedDevices{All: true},
+				Replicas:    2,
+				MemoryLimit: &ReplicaMemoryLimit{Percent: 25},
+			},
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": "4Gi"
+			}`,
+			output: ReplicatedResource{
+				Name:        NoErrorNewResourceName("valid"),
+				Devices:     ReplicatedDevices{All: true},
+				Replicas:    2,
+				MemoryLimit: &ReplicaMemoryLimit{Bytes: 4 * 1024 * 1024 * 1024},
+			},
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": "0%"
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": "101%"
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": "-1Gi"
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"memoryLimit": 1024
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"deviceReplicas": [
+					{"devices": [1, "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"], "replicas": 7}
+				]
+			}`,
+			output: ReplicatedResource{
+				Name:     NoErrorNewResourceName("valid"),
+				Devices:  ReplicatedDevices{All: true},
+				Replicas: 2,
+				DeviceReplicas: []DeviceReplicas{
+					{
+						Devices: ReplicatedDevices{
+							List: []ReplicatedDeviceRef{"1", "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"},
+						},
+						Replicas: 7,
+					},
+				},
+			},
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"deviceReplicas": [{"devices": "all", "replicas": 7}]
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"deviceReplicas": [{"devices": [0]}]
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"deviceReplicas": [{"devices": [0], "replicas": 1}]
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"activeThreadPercentage": 30,
+				"pinnedMemoryLimit": "2Gi"
+			}`,
+			output: ReplicatedResource{
+				Name:                   NoErrorNewResourceName("valid"),
+				Devices:                ReplicatedDevices{All: true},
+				Replicas:               2,
+				ActiveThreadPercentage: ptr(30),
+				PinnedMemoryLimit:      &ReplicaMemoryLimit{Bytes: 2 * 1024 * 1024 * 1024},
+			},
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"activeThreadPercentage": 0
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"activeThreadPercentage": 101
+			}`,
+			err: true,
+		},
+		{
+			input: `{
+				"name": "valid",
+				"replicas": 2,
+				"pinnedMemoryLimit": "0%"
+			}`,
+			err: true,
+		},
 	}
 
 	for i, tc := range testCases {
@@ -449,6 +593,72 @@ func TestUnmarshalReplicatedResources(t *testing.T) {
 			}`,
 			err: true,
 		},
+		{
+			input: `{
+				"resources": [
+					{
+						"name": "valid",
+						"devices": [0, 1],
+						"replicas": 2
+					}
+				]
+			}`,
+			output: ReplicatedResources{
+				Resources: []ReplicatedResource{
+					{
+						Name:     NoErrorNewResourceName("valid"),
+						Rename:   NoErrorNewResourceName("valid.shared"),
+						Devices:  ReplicatedDevices{List: []ReplicatedDeviceRef{"0", "1"}},
+						Replicas: 2,
+					},
+				},
+			},
+		},
+		{
+			input: `{
+				"resources": [
+					{
+						"name": "valid",
+						"rename": "valid-shared",
+						"devices": [0, 1],
+						"replicas": 2
+					}
+				]
+			}`,
+			output: ReplicatedResources{
+				Resources: []ReplicatedResource{
+					{
+						Name:     NoErrorNewResourceName("valid"),
+						Rename:   NoErrorNewResourceName("valid-shared"),
+						Devices:  ReplicatedDevices{List: []ReplicatedDeviceRef{"0", "1"}},
+						Replicas: 2,
+					},
+				},
+			},
+		},
+		{
+			input: `{
+				"renameByDefault": true,
+				"resources": [
+					{
+						"name": "valid",
+						"devices": 2,
+						"replicas": 2
+					}
+				]
+			}`,
+			output: ReplicatedResources{
+				RenameByDefault: true,
+				Resources: []ReplicatedResource{
+					{
+						Name:     NoErrorNewResourceName("valid"),
+						Rename:   NoErrorNewResourceName("valid.shared"),
+						Devices:  ReplicatedDevices{Count: 2},
+						Replicas: 2,
+					},
+				},
+			},
+		},
 	}
 
 	for i, tc := range testCases {
@@ -464,3 +674,121 @@ func TestUnmarshalReplicatedResources(t *testing.T) {
 		})
 	}
 }
+
+func TestReplicaMemoryLimit(t *testing.T) {
+	testCases := []struct {
+		limit       *ReplicaMemoryLimit
+		totalMemory uint64
+		expected    uint64
+		json        string
+	}{
+		{
+			limit:       nil,
+			totalMemory: 1000,
+			expected:    0,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Percent: 25},
+			totalMemory: 4000,
+			expected:    1000,
+			json:        `"25%"`,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Percent: 25},
+			totalMemory: 0,
+			expected:    0,
+			json:        `"25%"`,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Percent: 30},
+			totalMemory: 199,
+			expected:    59,
+			json:        `"30%"`,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Bytes: 2048},
+			totalMemory: 4096,
+			expected:    2048,
+			json:        `"2Ki"`,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Bytes: 8192},
+			totalMemory: 4096,
+			expected:    4096,
+			json:        `"8Ki"`,
+		},
+		{
+			limit:       &ReplicaMemoryLimit{Bytes: 8192},
+			totalMemory: 0,
+			expected:    8192,
+			json:        `"8Ki"`,
+		},
+	}
+
+	for i, tc := range testCases {
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			require.Equal(t, tc.expected, tc.limit.ForDevice(tc.totalMemory))
+			if tc.limit == nil {
+				return
+			}
+
+			output, err := json.Marshal(tc.limit)
+			require.NoError(t, err)
+			require.JSONEq(t, tc.json, string(output))
+
+			var roundtrip ReplicaMemoryLimit
+			require.NoError(t, json.Unmarshal(output, &roundtrip))
+			require.Equal(t, *tc.limit, roundtrip)
+		})
+	}
+}
+
+func TestReplicasFor(t *testing.T) {
+	r := ReplicatedResource{
+		Name:     NoErrorNewResourceName("valid"),
+		Devices:  ReplicatedDevices{All: true},
+		Replicas: 4,
+		DeviceReplicas: []DeviceReplicas{
+			{
+				Devices:  ReplicatedDevices{List: []ReplicatedDeviceRef{"0"}},
+				Replicas: 2,
+			},
+			{
+				Devices:  ReplicatedDevices{List: []ReplicatedDeviceRef{"GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"}},
+				Replicas: 7,
+			},
+		},
+	}
+
+	testCases := []struct {
+		description string
+		refs        []string
+		expected    int
+	}{
+		{
+			description: "no refs",
+			expected:    4,
+		},
+		{
+			description: "matching index",
+			refs:        []string{"0", "GPU-9a4c8a3e-6d2f-4b5c-8e3a-2f1d0c9b8a7e"},
+			expected:    2,
+		},
+		{
+			description: "matching UUID",
+			refs:        []string{"1", "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"},
+			expected:    7,
+		},
+		{
+			description: "no matching override",
+			refs:        []string{"2", ""},
+			expected:    4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, r.ReplicasFor(tc.refs...))
+		})
+	}
+}
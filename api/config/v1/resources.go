@@ -102,10 +102,10 @@ func (r *Resource) UnmarshalJSON(b []byte) error {
 	pattern, patternExists := res["pattern"]
 	name, nameExists := res["name"]
 	if !patternExists {
-		return fmt.Errorf("resources must have a 'pattern' field set")
+		return requiredFieldError("pattern", fmt.Errorf("resources must have a 'pattern' field set"))
 	}
 	if !nameExists {
-		return fmt.Errorf("resources must have a 'name' field set")
+		return requiredFieldError("name", fmt.Errorf("resources must have a 'name' field set"))
 	}
 
 	// Set r.Pattern from the resource JSON
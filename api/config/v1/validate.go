@@ -0,0 +1,550 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/yaml"
+)
+
+// fieldError associates an error returned by a custom unmarshaler with a
+// field of the struct being unmarshalled. This allows the path of the field
+// to be reported when validating a config.
+type fieldError struct {
+	field     string
+	errorType field.ErrorType
+	err       error
+}
+
+// requiredFieldError indicates that the specified field is missing.
+func requiredFieldError(name string, err error) error {
+	return &fieldError{field: name, errorType: field.ErrorTypeRequired, err: err}
+}
+
+// invalidFieldError indicates that the specified field has an invalid value.
+func invalidFieldError(name string, err error) error {
+	return &fieldError{field: name, errorType: field.ErrorTypeInvalid, err: err}
+}
+
+func (e *fieldError) Error() string {
+	return e.err.Error()
+}
+
+func (e *fieldError) Unwrap() error {
+	return e.err
+}
+
+// ValidationResult holds the errors and warnings found when validating a config.
+type ValidationResult struct {
+	// Errors are the problems that cause the config to be rejected.
+	Errors field.ErrorList
+	// Warnings are the fields that are accepted but ignored.
+	Warnings field.ErrorList
+}
+
+// ValidateConfig validates the contents of a v1 config file. In contrast to
+// parsing a config, all errors are reported along with the path of the field
+// they relate to. Fields that are specified but ignored -- including the
+// specified ignoredFields (e.g. flags.gfd) -- are reported as warnings.
+// An error is returned if the contents cannot be parsed as YAML or JSON.
+func ValidateConfig(contents []byte, ignoredFields ...string) (*ValidationResult, error) {
+	data, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	v := newValidator(ignoredFields, func(path string) string { return path })
+	if string(data) != "null" && !v.validateObject(nil, data, reflect.TypeFor[Config]()) {
+		return &v.result, nil
+	}
+
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+	v.validateConfig(&config)
+	return &v.result, nil
+}
+
+// ValidateVersionedConfig validates the contents of a config file of another
+// version as ValidateConfig does. The contents are validated against the
+// types of the specified (defaulted) config and are then checked as a v1
+// config after converting them. The paths of the fields of the v1 config are
+// mapped back to the version of the config file.
+func ValidateVersionedConfig(contents []byte, config VersionedConfig, ignoredFields ...string) (*ValidationResult, error) {
+	data, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+
+	v := newValidator(ignoredFields, config.FieldPath)
+	if string(data) != "null" && !v.validateObject(nil, data, reflect.TypeOf(config).Elem()) {
+		return &v.result, nil
+	}
+
+	if err := json.Unmarshal(data, config); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+	converted, err := config.ToV1()
+	if err != nil {
+		v.addError(field.Invalid(rootPath(nil), field.OmitValueType{}, err.Error()))
+		return &v.result, nil
+	}
+
+	// The converted config is validated as a v1 config file so that the
+	// checks performed when unmarshalling a v1 config also apply.
+	convertedData, err := json.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("marshal error: %v", err)
+	}
+	c := &validator{}
+	if c.validateObject(nil, convertedData, reflect.TypeFor[Config]()) {
+		var config Config
+		if err := json.Unmarshal(convertedData, &config); err != nil {
+			return nil, fmt.Errorf("unmarshal error: %v", err)
+		}
+		c.validateConfig(&config)
+	}
+	for _, e := range c.result.Errors {
+		e.Field = config.FieldPath(e.Field)
+		v.addError(e)
+	}
+	for _, e := range c.result.Warnings {
+		e.Field = config.FieldPath(e.Field)
+		v.addWarning(e)
+	}
+
+	return &v.result, nil
+}
+
+// newValidator creates a validator that reports the specified v1 fields as
+// ignored using the paths returned by fieldPath.
+func newValidator(ignoredFields []string, fieldPath func(string) string) *validator {
+	v := &validator{
+		ignored: make(map[string]bool),
+	}
+	for _, f := range ignoredFields {
+		v.ignored[fieldPath(f)] = true
+	}
+	return v
+}
+
+// validator walks a config document alongside the Go types it is unmarshalled
+// into to associate each error with the path of the field it relates to.
+type validator struct {
+	ignored map[string]bool
+	result  ValidationResult
+}
+
+// validateValue validates the raw value of a field with the specified type.
+// Objects and lists are validated element-wise so that errors in different
+// elements are all reported.
+func (v *validator) validateValue(path *field.Path, raw json.RawMessage, t reflect.Type) bool {
+	if string(raw) == "null" {
+		return true
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case isObject(t):
+		return v.validateObject(path, raw, t)
+	case t.Kind() == reflect.Slice && !isUnmarshaler(t):
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			v.addError(field.TypeInvalid(path, badValue(raw), "must be a list"))
+			return false
+		}
+		valid := true
+		for i, item := range items {
+			valid = v.validateValue(path.Index(i), item, t.Elem()) && valid
+		}
+		return valid
+	}
+	return v.unmarshal(path, raw, t)
+}
+
+// validateObject validates the fields of an object against the fields of the
+// specified struct type. Unknown fields are reported as errors. If all fields
+// are valid, the custom unmarshaler of the struct (if any) is used to perform
+// the checks that involve multiple fields.
+func (v *validator) validateObject(path *field.Path, raw json.RawMessage, t reflect.Type) bool {
+	var object map[string]json.RawMessage
+	if err := json.Unmarshal(raw, &object); err != nil {
+		v.addError(field.TypeInvalid(rootPath(path), badValue(raw), "must be an object"))
+		return false
+	}
+
+	fields := jsonFields(t)
+	valid := true
+	for _, key := range slices.Sorted(maps.Keys(object)) {
+		child := childPath(path, key)
+		fieldType, ok := fields[key]
+		if !ok {
+			v.addError(field.Forbidden(child, "unknown field"))
+			valid = false
+			continue
+		}
+		if v.ignored[child.String()] {
+			v.addWarning(field.Forbidden(child, "ignored by this component"))
+		}
+		valid = v.validateValue(child, object[key], fieldType) && valid
+	}
+
+	if !valid || !isUnmarshaler(t) {
+		return valid
+	}
+	return v.unmarshal(path, raw, t)
+}
+
+// unmarshal unmarshals the raw value into the specified type and records the
+// resulting error, if any.
+func (v *validator) unmarshal(path *field.Path, raw json.RawMessage, t reflect.Type) bool {
+	err := json.Unmarshal(raw, reflect.New(t).Interface())
+	if err == nil {
+		return true
+	}
+
+	var fe *fieldError
+	var te *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &fe):
+		child := path
+		for _, element := range strings.Split(fe.field, ".") {
+			name, index := splitIndex(element)
+			child = childPath(child, name)
+			if index >= 0 {
+				child = child.Index(index)
+			}
+		}
+		v.addError(&field.Error{
+			Type:     fe.errorType,
+			Field:    child.String(),
+			BadValue: badValue(lookup(raw, fe.field)),
+			Detail:   fe.Error(),
+		})
+	case errors.As(err, &te):
+		v.addError(field.TypeInvalid(rootPath(path), badValue(raw), fmt.Sprintf("must be of type %v", te.Type)))
+	default:
+		v.addError(field.Invalid(rootPath(path), badValue(raw), err.Error()))
+	}
+	return false
+}
+
+// validateConfig performs the checks that are applied to a config that was
+// successfully unmarshalled. These are the checks that are otherwise only
+// performed as the individual components start.
+func (v *validator) validateConfig(config *Config) {
+	if version := config.Version; version != "" && version != Version {
+		v.addError(field.NotSupported(field.NewPath("version"), version, []string{Version}))
+	}
+
+	flags := field.NewPath("flags")
+	v.validateOneOf(flags.Child("migStrategy"), config.Flags.MigStrategy, MigStrategyNone, MigStrategySingle, MigStrategyMixed)
+	if plugin := config.Flags.Plugin; plugin != nil {
+		path := flags.Child("plugin")
+		if plugin.DeviceListStrategy != nil {
+			if _, err := NewDeviceListStrategies(*plugin.DeviceListStrategy); err != nil {
+				v.addError(field.Invalid(path.Child("deviceListStrategy"), []string(*plugin.DeviceListStrategy), err.Error()))
+			}
+		}
+		v.validateOneOf(path.Child("deviceIDStrategy"), plugin.DeviceIDStrategy, DeviceIDStrategyUUID, DeviceIDStrategyIndex)
+		v.validateOneOf(path.Child("sharedDevicesAllocationPolicy"), plugin.SharedDevicesAllocationPolicy, AllocationPolicyDistributed, AllocationPolicyPacked)
+		v.validateOneOf(path.Child("healthRecoveryPolicy"), plugin.HealthRecoveryPolicy, HealthRecoveryPolicyNone, HealthRecoveryPolicyQuietPeriod, HealthRecoveryPolicyNVMLProbe)
+		v.validateOneOf(path.Child("ccMode"), plugin.CCMode, CCModeAny, CCModeOn, CCModeOff, CCModeDevtools)
+		if d := plugin.HealthRecoveryQuietPeriod; d != nil && *d < 0 {
+			v.addError(field.Invalid(path.Child("healthRecoveryQuietPeriod"), d.String(), "must be >= 0"))
+		}
+	}
+
+	if gfd := config.Flags.GFD; gfd != nil {
+		v.validateOneOf(flags.Child("gfd", "deviceLabels"), gfd.DeviceLabels, DeviceLabelsNone, DeviceLabelsIndex, DeviceLabelsUUID)
+		if d := gfd.MigPollInterval; d != nil && *d < 0 {
+			v.addError(field.Invalid(flags.Child("gfd", "migPollInterval"), d.String(), "must be >= 0"))
+		}
+	}
+
+	if err := AssertChannelIDsValid(config.Imex.ChannelIDs); err != nil {
+		v.addError(field.Invalid(field.NewPath("imex", "channelIDs"), config.Imex.ChannelIDs, err.Error()))
+	}
+
+	v.validateSharing(config)
+}
+
+// ValidateSharing performs the checks on the sharing settings of a config that
+// are not covered by unmarshalling it. The components that apply a config
+// perform these checks as they start and ValidateConfig includes them.
+func ValidateSharing(config *Config) *ValidationResult {
+	v := &validator{}
+	v.validateSharing(config)
+	return &v.result
+}
+
+// validateSharing checks that the devices and per-replica limits of the shared
+// resources are supported by the sharing strategy and can be applied as
+// specified.
+func (v *validator) validateSharing(config *Config) {
+	s := &config.Sharing
+	sharing := field.NewPath("sharing")
+	for i, r := range s.TimeSlicing.Resources {
+		path := sharing.Child("timeSlicing", "resources").Index(i)
+		if v.validateSharedDevices(path, config, r) {
+			v.validateRename(path, s.TimeSlicing.RenameByDefault, r)
+		}
+		v.validateMemoryLimit(path.Child("memoryLimit"), r.MemoryLimit)
+		if r.ActiveThreadPercentage != nil {
+			v.addError(field.Forbidden(path.Child("activeThreadPercentage"), "only supported for MPS"))
+		}
+		if r.PinnedMemoryLimit != nil {
+			v.addError(field.Forbidden(path.Child("pinnedMemoryLimit"), "only supported for MPS; use memoryLimit for time-slicing"))
+		}
+	}
+	if s.MPS != nil {
+		for i, r := range s.MPS.Resources {
+			path := sharing.Child("mps", "resources").Index(i)
+			if v.validateSharedDevices(path, config, r) {
+				v.validateRename(path, s.MPS.RenameByDefault, r)
+			}
+			v.validateMemoryLimit(path.Child("pinnedMemoryLimit"), r.PinnedMemoryLimit)
+			if p := r.ActiveThreadPercentage; p != nil && *p*maxReplicas(r) > 100 {
+				v.addError(field.Invalid(path.Child("activeThreadPercentage"), *p, fmt.Sprintf("the replicas of a device must not use more than 100%% of its SMs, but %d replicas are configured", maxReplicas(r))))
+			}
+			if r.MemoryLimit != nil {
+				v.addError(field.Forbidden(path.Child("memoryLimit"), "not supported for MPS; use pinnedMemoryLimit instead"))
+			}
+		}
+		if len(s.TimeSlicing.Resources) > 0 {
+			v.addWarning(field.Forbidden(sharing.Child("timeSlicing"), "ignored since sharing.mps is specified"))
+		}
+	}
+}
+
+// validateMemoryLimit checks that an absolute per-replica memory limit can be
+// passed on without losing precision. Limits are passed on in MiB, so a limit
+// is rounded down to a whole number of MiB.
+func (v *validator) validateMemoryLimit(path *field.Path, limit *ReplicaMemoryLimit) {
+	if limit == nil || limit.Percent > 0 {
+		return
+	}
+	const mib = 1024 * 1024
+	if limit.Bytes < mib {
+		v.addError(field.Invalid(path, limit.Bytes, "must be at least 1Mi"))
+		return
+	}
+	if limit.Bytes%mib != 0 {
+		v.addWarning(field.Invalid(path, limit.Bytes, fmt.Sprintf("is rounded down to %dMi", limit.Bytes/mib)))
+	}
+}
+
+// maxReplicas returns the highest number of replicas of any device of the
+// specified resource.
+func maxReplicas(r ReplicatedResource) int {
+	replicas := r.Replicas
+	for _, dr := range r.DeviceReplicas {
+		replicas = max(replicas, dr.Replicas)
+	}
+	return replicas
+}
+
+// validateSharedDevices checks that a subset of devices is only shared for
+// full GPUs. The labels of a MIG resource cannot express that only some of its
+// MIG devices are shared.
+func (v *validator) validateSharedDevices(path *field.Path, config *Config, r ReplicatedResource) bool {
+	if r.Devices.All {
+		return true
+	}
+	if !isMigResource(config, r.Name) && !slices.ContainsFunc(r.Devices.List, isMigDeviceRef) {
+		return true
+	}
+	v.addError(field.Forbidden(path.Child("devices"), "sharing only some of the MIG devices of a resource is not supported"))
+	return false
+}
+
+// validateRename reports the name under which the shared devices of a resource
+// are advertised if only some of its devices are shared. Unless a rename is
+// set, the default shared name is used for these.
+func (v *validator) validateRename(path *field.Path, renameByDefault bool, r ReplicatedResource) {
+	if r.Devices.All || renameByDefault || r.Rename != r.Name.DefaultSharedRename() {
+		return
+	}
+	v.addWarning(field.Invalid(path.Child("rename"), string(r.Rename), "not all devices of the resource are shared; the shared devices are advertised under this name"))
+}
+
+// isMigResource returns whether the specified resource consists of MIG devices
+// under the MIG strategy of the config. With the single strategy, MIG devices
+// are advertised as nvidia.com/gpu; with the mixed strategy, they are
+// advertised as nvidia.com/mig-<profile>. In both cases, MIG devices may also
+// be advertised under the names set in resources.mig. If no strategy is set,
+// as is the case for a config file that is validated on its own, the names of
+// the mixed strategy are checked.
+func isMigResource(config *Config, name ResourceName) bool {
+	strategy := MigStrategyMixed
+	if config.Flags.MigStrategy != nil {
+		strategy = *config.Flags.MigStrategy
+	}
+	switch strategy {
+	case MigStrategyNone:
+		return false
+	case MigStrategySingle:
+		if name == ResourceName(ResourceNamePrefix+"/gpu") {
+			return true
+		}
+	default:
+		if _, n := name.Split(); strings.HasPrefix(n, "mig-") {
+			return true
+		}
+	}
+	return slices.ContainsFunc(config.Resources.MIGs, func(r Resource) bool {
+		return r.Name == name
+	})
+}
+
+func isMigDeviceRef(d ReplicatedDeviceRef) bool {
+	return d.IsMigIndex() || d.IsMigUUID()
+}
+
+// validateOneOf checks that an optional string value is one of the supported values.
+func (v *validator) validateOneOf(path *field.Path, value *string, supported ...string) {
+	if value == nil || slices.Contains(supported, *value) {
+		return
+	}
+	v.addError(field.NotSupported(path, *value, supported))
+}
+
+func (v *validator) addError(err *field.Error) {
+	v.result.Errors = append(v.result.Errors, err)
+}
+
+func (v *validator) addWarning(err *field.Error) {
+	v.result.Warnings = append(v.result.Warnings, err)
+}
+
+var unmarshalerType = reflect.TypeFor[json.Unmarshaler]()
+
+// isUnmarshaler checks whether the specified type has a custom unmarshaler.
+func isUnmarshaler(t reflect.Type) bool {
+	return reflect.PointerTo(t).Implements(unmarshalerType)
+}
+
+// isObject checks whether the specified type is unmarshalled from an object
+// with named fields. Structs such as ReplicatedDevices that represent a union
+// of values are not objects.
+func isObject(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && len(jsonFields(t)) > 0
+}
+
+// jsonFields returns the types of the fields of a struct keyed by their JSON
+// names. The fields of embedded structs are included.
+func jsonFields(t reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag, ok := f.Tag.Lookup("json")
+		if !ok {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				maps.Copy(fields, jsonFields(f.Type))
+			}
+			continue
+		}
+		name, _, _ := strings.Cut(tag, ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields[name] = f.Type
+	}
+	return fields
+}
+
+// lookup returns the raw value at the specified dot-separated path of an
+// object or nil if this does not exist.
+func lookup(raw json.RawMessage, path string) json.RawMessage {
+	for _, element := range strings.Split(path, ".") {
+		name, index := splitIndex(element)
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &object); err != nil {
+			return nil
+		}
+		raw = object[name]
+		if index < 0 {
+			continue
+		}
+		var list []json.RawMessage
+		if err := json.Unmarshal(raw, &list); err != nil || index >= len(list) {
+			return nil
+		}
+		raw = list[index]
+	}
+	return raw
+}
+
+// splitIndex splits an element of a field path of the form name[index] into
+// its name and index. The index is -1 if the element does not refer to an
+// item of a list.
+func splitIndex(element string) (string, int) {
+	name, index, found := strings.Cut(strings.TrimSuffix(element, "]"), "[")
+	if !found {
+		return element, -1
+	}
+	i, err := strconv.Atoi(index)
+	if err != nil {
+		return element, -1
+	}
+	return name, i
+}
+
+// badValue returns the value to report for a field error. Only scalar values
+// are included.
+func badValue(raw json.RawMessage) interface{} {
+	var value interface{}
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return field.OmitValueType{}
+	}
+	switch value.(type) {
+	case map[string]interface{}, []interface{}, nil:
+		return field.OmitValueType{}
+	}
+	return value
+}
+
+// childPath returns the path of the named child of the specified path, where
+// a nil path represents the root of the config.
+func childPath(path *field.Path, name string) *field.Path {
+	if path == nil {
+		return field.NewPath(name)
+	}
+	return path.Child(name)
+}
+
+// rootPath returns the specified path or a placeholder for the root of the config.
+func rootPath(path *field.Path) *field.Path {
+	if path == nil {
+		return field.NewPath("<root>")
+	}
+	return path
+}
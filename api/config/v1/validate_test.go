@@ -0,0 +1,341 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v1
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+func TestValidateConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        string
+		ignoredFields []string
+		errors        []string
+		warnings      []string
+	}{
+		{
+			description: "empty config",
+			config:      ``,
+		},
+		{
+			description: "valid config",
+			config: `
+version: v1
+flags:
+  migStrategy: mixed
+  plugin:
+    deviceListStrategy: [envvar, cdi-cri]
+sharing:
+  mps:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 4
+      activeThreadPercentage: 25
+healthChecks:
+  disabledXIDs: [13, 31]
+`,
+		},
+		{
+			description: "errors are reported with their field paths",
+			config: `
+version: v1
+flags:
+  migStrategy: all
+  plugin:
+    deviceIDStrategy: serial
+sharing:
+  mps:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 2
+    - name: nvidia.com/gpu
+      replicas: 1
+    - name: "invalid name!"
+      replicas: 2
+      devices: "some"
+`,
+			errors: []string{
+				"sharing.mps.resources[1].replicas",
+				"sharing.mps.resources[2].devices",
+				"sharing.mps.resources[2].name",
+			},
+		},
+		{
+			description: "semantic errors are reported once the config parses",
+			config: `
+flags:
+  migStrategy: all
+  plugin:
+    deviceIDStrategy: serial
+    deviceListStrategy: unknown
+imex:
+  channelIDs: [1]
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 2
+      activeThreadPercentage: 50
+`,
+			errors: []string{
+				"flags.migStrategy",
+				"flags.plugin.deviceListStrategy",
+				"flags.plugin.deviceIDStrategy",
+				"imex.channelIDs",
+				"sharing.timeSlicing.resources[0].activeThreadPercentage",
+			},
+		},
+		{
+			description: "missing fields are reported as required",
+			config: `
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+    - replicas: 2
+`,
+			errors: []string{
+				"sharing.timeSlicing.resources[0].replicas",
+				"sharing.timeSlicing.resources[1].name",
+			},
+		},
+		{
+			description: "unknown fields and invalid types are errors",
+			config: `
+flags:
+  failOnInitError: "yes"
+  migstrategy: none
+healthChecks:
+  rules:
+    temperature:
+      maxCelcius: 90
+`,
+			errors: []string{
+				"flags.failOnInitError",
+				"flags.migstrategy",
+				"healthChecks.rules.temperature.maxCelcius",
+			},
+		},
+		{
+			description: "invalid rules are reported with their field paths",
+			config: `
+healthChecks:
+  rules:
+    pollInterval: 0s
+`,
+			errors: []string{
+				"healthChecks.rules.pollInterval",
+			},
+		},
+		{
+			description: "unsupported version",
+			config:      `version: v2`,
+			errors: []string{
+				"version",
+			},
+		},
+		{
+			description: "ignored fields are warnings",
+			config: `
+flags:
+  gfd:
+    oneshot: true
+resources:
+  gpus:
+  - pattern: "*"
+    name: gpu
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      rename: shared-gpu
+      devices: [0]
+      replicas: 2
+`,
+			ignoredFields: []string{"flags.gfd"},
+			warnings: []string{
+				"flags.gfd",
+			},
+		},
+		{
+			description: "sharing a subset of MIG devices is not supported",
+			config: `
+resources:
+  mig:
+  - pattern: "1g.10gb"
+    name: small-mig
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/mig-1g.5gb
+      devices: 2
+      replicas: 2
+    - name: nvidia.com/gpu
+      devices: ["1:0"]
+      replicas: 2
+    - name: nvidia.com/mig-2g.10gb
+      replicas: 2
+  mps:
+    resources:
+    - name: nvidia.com/small-mig
+      devices: [0]
+      replicas: 2
+`,
+			errors: []string{
+				"sharing.timeSlicing.resources[0].devices",
+				"sharing.timeSlicing.resources[1].devices",
+				"sharing.mps.resources[0].devices",
+			},
+			warnings: []string{
+				"sharing.timeSlicing",
+			},
+		},
+		{
+			description: "sharing a subset of MIG devices depends on the MIG strategy",
+			config: `
+flags:
+  migStrategy: single
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      devices: [0]
+      replicas: 2
+`,
+			errors: []string{
+				"sharing.timeSlicing.resources[0].devices",
+			},
+		},
+		{
+			description: "sharing a subset of devices without MIG",
+			config: `
+flags:
+  migStrategy: none
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/mig-1g.5gb
+      devices: [0]
+      replicas: 2
+`,
+			warnings: []string{
+				"sharing.timeSlicing.resources[0].rename",
+			},
+		},
+		{
+			description: "per-replica limits are checked against the number of replicas",
+			config: `
+sharing:
+  mps:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 4
+      activeThreadPercentage: 25
+      pinnedMemoryLimit: 1536Ki
+    - name: nvidia.com/mig-1g.5gb
+      replicas: 2
+      deviceReplicas:
+      - devices: [0]
+        replicas: 3
+      activeThreadPercentage: 50
+      pinnedMemoryLimit: 512Ki
+`,
+			errors: []string{
+				"sharing.mps.resources[1].pinnedMemoryLimit",
+				"sharing.mps.resources[1].activeThreadPercentage",
+			},
+			warnings: []string{
+				"sharing.mps.resources[0].pinnedMemoryLimit",
+			},
+		},
+		{
+			description: "sharing a subset of devices without a rename",
+			config: `
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      devices: 2
+      replicas: 2
+`,
+			warnings: []string{
+				"sharing.timeSlicing.resources[0].rename",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result, err := ValidateConfig([]byte(tc.config), tc.ignoredFields...)
+			require.NoError(t, err)
+			require.Equal(t, tc.errors, fieldPaths(result.Errors), result.Errors)
+			require.Equal(t, tc.warnings, fieldPaths(result.Warnings), result.Warnings)
+		})
+	}
+}
+
+func TestValidateConfigMessages(t *testing.T) {
+	result, err := ValidateConfig([]byte(`
+sharing:
+  mps:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 1
+`))
+	require.NoError(t, err)
+	require.Len(t, result.Errors, 1)
+	require.EqualError(t, result.Errors[0], "sharing.mps.resources[0].replicas: Invalid value: 1: number of replicas must be >= 2")
+
+	_, err = ValidateConfig([]byte(`flags: [`))
+	require.Error(t, err)
+}
+
+func TestValidateSharing(t *testing.T) {
+	config, err := newConfigForTest(t, `
+version: v1
+sharing:
+  mps:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 4
+      activeThreadPercentage: 30
+      pinnedMemoryLimit: 512Ki
+`)
+	require.NoError(t, err)
+
+	result := ValidateSharing(config)
+	require.Equal(t,
+		[]string{
+			"sharing.mps.resources[0].pinnedMemoryLimit",
+			"sharing.mps.resources[0].activeThreadPercentage",
+		},
+		fieldPaths(result.Errors),
+	)
+	require.Empty(t, result.Warnings)
+}
+
+func fieldPaths(errs field.ErrorList) []string {
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	return paths
+}
@@ -0,0 +1,157 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+import (
+	"fmt"
+	"time"
+
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// Version indicates the version of the 'Config' struct used to hold configuration information.
+const Version = "v2"
+
+// Constants for the defaults of GPU Feature Discovery.
+const (
+	DefaultSleepInterval   = 60 * time.Second
+	DefaultOutputFile      = "/etc/kubernetes/node-feature-discovery/features.d/gfd"
+	DefaultMachineTypeFile = "/sys/class/dmi/id/product_name"
+)
+
+// Config is a versioned struct used to hold configuration information.
+//
+// In contrast to v1, the options of each component are grouped in a separate
+// section and options that are not specified take their default value
+// instead of being left unset.
+type Config struct {
+	Version          string           `json:"version"                    yaml:"version"`
+	Common           Common           `json:"common,omitempty"           yaml:"common,omitempty"`
+	DevicePlugin     DevicePlugin     `json:"devicePlugin,omitempty"     yaml:"devicePlugin,omitempty"`
+	FeatureDiscovery FeatureDiscovery `json:"featureDiscovery,omitempty" yaml:"featureDiscovery,omitempty"`
+}
+
+// Common holds the options that are shared by all components.
+type Common struct {
+	MigStrategy             string `json:"migStrategy"             yaml:"migStrategy"`
+	FailOnInitError         bool   `json:"failOnInitError"         yaml:"failOnInitError"`
+	DeviceDiscoveryStrategy string `json:"deviceDiscoveryStrategy" yaml:"deviceDiscoveryStrategy"`
+	NvidiaDriverRoot        string `json:"nvidiaDriverRoot"        yaml:"nvidiaDriverRoot"`
+	// NvidiaDevRoot defaults to the NvidiaDriverRoot if it is not specified.
+	NvidiaDevRoot       string    `json:"nvidiaDevRoot,omitempty" yaml:"nvidiaDevRoot,omitempty"`
+	ContainerDriverRoot string    `json:"containerDriverRoot"     yaml:"containerDriverRoot"`
+	Resources           Resources `json:"resources"               yaml:"resources"`
+	Sharing             Sharing   `json:"sharing"                 yaml:"sharing"`
+}
+
+// DevicePlugin holds the options that are specific to the device plugin.
+type DevicePlugin struct {
+	PassDeviceSpecs               bool                      `json:"passDeviceSpecs"               yaml:"passDeviceSpecs"`
+	DeviceListStrategy            v1.DeviceListStrategyFlag `json:"deviceListStrategy"            yaml:"deviceListStrategy"`
+	DeviceIDStrategy              string                    `json:"deviceIDStrategy"              yaml:"deviceIDStrategy"`
+	CDIAnnotationPrefix           string                    `json:"cdiAnnotationPrefix"           yaml:"cdiAnnotationPrefix"`
+	NvidiaCTKPath                 string                    `json:"nvidiaCTKPath"                 yaml:"nvidiaCTKPath"`
+	SharedDevicesAllocationPolicy string                    `json:"sharedDevicesAllocationPolicy" yaml:"sharedDevicesAllocationPolicy"`
+	HealthRecoveryPolicy          string                    `json:"healthRecoveryPolicy"          yaml:"healthRecoveryPolicy"`
+	HealthRecoveryQuietPeriod     v1.Duration               `json:"healthRecoveryQuietPeriod"     yaml:"healthRecoveryQuietPeriod"`
+	CCMode                        string                    `json:"ccMode"                        yaml:"ccMode"`
+	GDRCopyEnabled                bool                      `json:"gdrcopyEnabled"                yaml:"gdrcopyEnabled"`
+	GDSEnabled                    bool                      `json:"gdsEnabled"                    yaml:"gdsEnabled"`
+	MOFEDEnabled                  bool                      `json:"mofedEnabled"                  yaml:"mofedEnabled"`
+	// MPSRoot is the path on the host where MPS-specific mounts and files are
+	// created by the MPS control daemon.
+	MPSRoot      string       `json:"mpsRoot,omitempty" yaml:"mpsRoot,omitempty"`
+	Imex         Imex         `json:"imex"              yaml:"imex"`
+	HealthChecks HealthChecks `json:"healthChecks"      yaml:"healthChecks"`
+}
+
+// FeatureDiscovery holds the options that are specific to GPU Feature Discovery.
+type FeatureDiscovery struct {
+	Oneshot           bool        `json:"oneshot"           yaml:"oneshot"`
+	NoTimestamp       bool        `json:"noTimestamp"       yaml:"noTimestamp"`
+	SleepInterval     v1.Duration `json:"sleepInterval"     yaml:"sleepInterval"`
+	OutputFile        string      `json:"outputFile"        yaml:"outputFile"`
+	MachineTypeFile   string      `json:"machineTypeFile"   yaml:"machineTypeFile"`
+	UseNodeFeatureAPI bool        `json:"useNodeFeatureAPI" yaml:"useNodeFeatureAPI"`
+	DeviceLabels      string      `json:"deviceLabels"      yaml:"deviceLabels"`
+	MigPollInterval   v1.Duration `json:"migPollInterval"   yaml:"migPollInterval"`
+	TriggerSocket     string      `json:"triggerSocket"     yaml:"triggerSocket"`
+}
+
+// Default returns a config with the default value for each option.
+func Default() *Config {
+	return &Config{
+		Version:          Version,
+		Common:           DefaultCommon(),
+		DevicePlugin:     DefaultDevicePlugin(),
+		FeatureDiscovery: DefaultFeatureDiscovery(),
+	}
+}
+
+// DefaultCommon returns the default options that are shared by all components.
+func DefaultCommon() Common {
+	return Common{
+		MigStrategy:             v1.MigStrategyNone,
+		FailOnInitError:         true,
+		DeviceDiscoveryStrategy: "auto",
+		NvidiaDriverRoot:        "/",
+		ContainerDriverRoot:     v1.DefaultContainerDriverRoot,
+		Sharing:                 DefaultSharing(),
+	}
+}
+
+// DefaultDevicePlugin returns the default options of the device plugin.
+func DefaultDevicePlugin() DevicePlugin {
+	return DevicePlugin{
+		DeviceListStrategy:            v1.DeviceListStrategyFlag{v1.DeviceListStrategyEnvVar},
+		DeviceIDStrategy:              v1.DeviceIDStrategyUUID,
+		CDIAnnotationPrefix:           v1.DefaultCDIAnnotationPrefix,
+		NvidiaCTKPath:                 v1.DefaultNvidiaCTKPath,
+		SharedDevicesAllocationPolicy: v1.AllocationPolicyDistributed,
+		HealthRecoveryPolicy:          v1.HealthRecoveryPolicyNone,
+		HealthRecoveryQuietPeriod:     v1.Duration(v1.DefaultHealthRecoveryQuietPeriod),
+		CCMode:                        v1.CCModeAny,
+		HealthChecks:                  DefaultHealthChecks(),
+	}
+}
+
+// DefaultFeatureDiscovery returns the default options of GPU Feature Discovery.
+func DefaultFeatureDiscovery() FeatureDiscovery {
+	return FeatureDiscovery{
+		SleepInterval:     v1.Duration(DefaultSleepInterval),
+		OutputFile:        DefaultOutputFile,
+		MachineTypeFile:   DefaultMachineTypeFile,
+		UseNodeFeatureAPI: true,
+		DeviceLabels:      v1.DeviceLabelsNone,
+		MigPollInterval:   v1.Duration(v1.DefaultMigPollInterval),
+	}
+}
+
+// Parse parses the contents of a v2 config file as either YAML or JSON. The
+// options that are not specified take their default values.
+func Parse(contents []byte) (*Config, error) {
+	config := Default()
+	if err := yaml.Unmarshal(contents, config); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %v", err)
+	}
+	if config.Version != Version {
+		return nil, fmt.Errorf("unknown version: %v", config.Version)
+	}
+	return config, nil
+}
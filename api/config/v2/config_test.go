@@ -0,0 +1,260 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         string
+		expectedError  bool
+		expectedConfig *Config
+	}{
+		{
+			description:    "unspecified options are defaulted",
+			config:         `version: v2`,
+			expectedConfig: Default(),
+		},
+		{
+			description: "specified options override the defaults",
+			config: `
+version: v2
+common:
+  migStrategy: mixed
+  failOnInitError: false
+devicePlugin:
+  deviceListStrategy: [cdi-cri]
+featureDiscovery:
+  oneshot: true
+  sleepInterval: 10s
+`,
+			expectedConfig: func() *Config {
+				c := Default()
+				c.Common.MigStrategy = v1.MigStrategyMixed
+				c.Common.FailOnInitError = false
+				c.DevicePlugin.DeviceListStrategy = v1.DeviceListStrategyFlag{v1.DeviceListStrategyCDICRI}
+				c.FeatureDiscovery.Oneshot = true
+				c.FeatureDiscovery.SleepInterval = v1.Duration(10 * time.Second)
+				return c
+			}(),
+		},
+		{
+			description: "a single device list strategy is accepted",
+			config: `
+version: v2
+devicePlugin:
+  deviceListStrategy: volume-mounts
+`,
+			expectedConfig: func() *Config {
+				c := Default()
+				c.DevicePlugin.DeviceListStrategy = v1.DeviceListStrategyFlag{v1.DeviceListStrategyVolumeMounts}
+				return c
+			}(),
+		},
+		{
+			description:   "other versions are rejected",
+			config:        `version: v1`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config, err := Parse([]byte(tc.config))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func TestConvertFromV1(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         *v1.Config
+		expectedError  bool
+		expectedConfig *Config
+	}{
+		{
+			description:    "empty config",
+			config:         &v1.Config{},
+			expectedConfig: Default(),
+		},
+		{
+			description: "options are moved to their sections",
+			config: &v1.Config{
+				Version: v1.Version,
+				Flags: v1.Flags{
+					CommandLineFlags: v1.CommandLineFlags{
+						MigStrategy:       ptr.To(v1.MigStrategySingle),
+						MpsRoot:           ptr.To("/run/nvidia/mps"),
+						UseNodeFeatureAPI: ptr.To(false),
+						Plugin: &v1.PluginCommandLineFlags{
+							DeviceListStrategy:  ptr.To(v1.DeviceListStrategyFlag{v1.DeviceListStrategyVolumeMounts}),
+							ContainerDriverRoot: ptr.To("/host"),
+						},
+						GFD: &v1.GFDCommandLineFlags{
+							Oneshot: ptr.To(true),
+						},
+					},
+				},
+				Sharing: v1.Sharing{
+					TimeSlicing: v1.ReplicatedResources{
+						Resources: []v1.ReplicatedResource{
+							{Name: "nvidia.com/gpu", Devices: v1.ReplicatedDevices{All: true}, Replicas: 2},
+						},
+					},
+				},
+				Imex: v1.Imex{
+					ChannelIDs: []int{0},
+				},
+			},
+			expectedConfig: func() *Config {
+				c := Default()
+				c.Common.MigStrategy = v1.MigStrategySingle
+				c.Common.ContainerDriverRoot = "/host"
+				c.Common.Sharing.TimeSlicing.Resources = []SharedResource{
+					{Name: "nvidia.com/gpu", Devices: v1.ReplicatedDevices{All: true}, Replicas: 2},
+				}
+				c.DevicePlugin.MPSRoot = "/run/nvidia/mps"
+				c.DevicePlugin.DeviceListStrategy = v1.DeviceListStrategyFlag{v1.DeviceListStrategyVolumeMounts}
+				c.DevicePlugin.Imex = Imex{ChannelIDs: []int{0}}
+				c.FeatureDiscovery.Oneshot = true
+				c.FeatureDiscovery.UseNodeFeatureAPI = false
+				return c
+			}(),
+		},
+		{
+			description:   "unknown version",
+			config:        &v1.Config{Version: "v0"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config, err := ConvertFromV1(tc.config)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedConfig, config)
+
+			// Converting back preserves all options that were set.
+			converted, err := config.ToV1()
+			require.NoError(t, err)
+			require.Equal(t, tc.config.Sharing.TimeSlicing.Resources, converted.Sharing.TimeSlicing.Resources)
+			require.Equal(t, tc.config.Imex, converted.Imex)
+			if flags := tc.config.Flags.MigStrategy; flags != nil {
+				require.Equal(t, flags, converted.Flags.MigStrategy)
+			}
+			if mpsRoot := tc.config.Flags.MpsRoot; mpsRoot != nil {
+				require.Equal(t, mpsRoot, converted.Flags.MpsRoot)
+			}
+		})
+	}
+}
+
+func TestToV1(t *testing.T) {
+	config, err := Default().ToV1()
+	require.NoError(t, err)
+
+	require.Equal(t, v1.Version, config.Version)
+	require.Nil(t, config.Flags.MpsRoot)
+	require.Nil(t, config.Flags.NvidiaDevRoot)
+	require.Equal(t, ptr.To(v1.DeviceListStrategyFlag{v1.DeviceListStrategyEnvVar}), config.Flags.Plugin.DeviceListStrategy)
+	require.Equal(t, ptr.To(v1.DefaultContainerDriverRoot), config.Flags.Plugin.ContainerDriverRoot)
+	require.Equal(t, ptr.To(v1.Duration(DefaultSleepInterval)), config.Flags.GFD.SleepInterval)
+
+	_, err = (&Config{Version: "v3"}).ToV1()
+	require.Error(t, err)
+}
+
+func TestMarshalConvertedConfig(t *testing.T) {
+	testCases := []struct {
+		description string
+		config      string
+	}{
+		{
+			description: "empty config",
+		},
+		{
+			description: "config with resources, sharing, and health checks",
+			config: `
+version: v1
+flags:
+  plugin:
+    deviceListStrategy: cdi-cri
+resources:
+  gpus:
+  - pattern: "A100-*"
+    name: a100
+  mig:
+  - pattern: "1g.5gb"
+    name: mig-small
+sharing:
+  timeSlicing:
+    renameByDefault: true
+    resources:
+    - name: nvidia.com/gpu
+      devices: [0, "GPU-8dcd427f-e4f5-1d5e-4d3c-d6e2f7b8c4a1"]
+      replicas: 4
+      deviceReplicas:
+      - devices: [0]
+        replicas: 2
+      memoryLimit: 10Gi
+    - name: nvidia.com/a100
+      devices: 2
+      replicas: 2
+healthChecks:
+  disabledXIDs: [13, 31]
+  enabledXIDs: all
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			var v1Config v1.Config
+			require.NoError(t, yaml.Unmarshal([]byte(tc.config), &v1Config))
+
+			config, err := ConvertFromV1(&v1Config)
+			require.NoError(t, err)
+
+			contents, err := yaml.Marshal(config)
+			require.NoError(t, err)
+
+			parsed, err := Parse(contents)
+			require.NoError(t, err)
+			require.Equal(t, config, parsed)
+		})
+	}
+}
@@ -0,0 +1,353 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// ToV1 converts the config to a v1 config. Since all options of a v2 config
+// are set, the options of the v1 config are only overridden by command line
+// flags that are set explicitly. Empty strings are converted to unset options
+// so that the defaults of the individual components apply.
+//
+// The sections of the config are converted as is. These are only checked and
+// defaulted (e.g. the renaming of shared resources) once the converted config
+// is parsed as a v1 config.
+func (c *Config) ToV1() (*v1.Config, error) {
+	if c.Version != Version {
+		return nil, fmt.Errorf("unknown version: %v", c.Version)
+	}
+
+	deviceListStrategy := c.DevicePlugin.DeviceListStrategy
+	config := &v1.Config{
+		Version: v1.Version,
+		Flags: v1.Flags{
+			CommandLineFlags: v1.CommandLineFlags{
+				MigStrategy:             &c.Common.MigStrategy,
+				FailOnInitError:         &c.Common.FailOnInitError,
+				MpsRoot:                 optional(c.DevicePlugin.MPSRoot),
+				NvidiaDriverRoot:        &c.Common.NvidiaDriverRoot,
+				NvidiaDevRoot:           optional(c.Common.NvidiaDevRoot),
+				GDRCopyEnabled:          &c.DevicePlugin.GDRCopyEnabled,
+				GDSEnabled:              &c.DevicePlugin.GDSEnabled,
+				MOFEDEnabled:            &c.DevicePlugin.MOFEDEnabled,
+				UseNodeFeatureAPI:       &c.FeatureDiscovery.UseNodeFeatureAPI,
+				DeviceDiscoveryStrategy: &c.Common.DeviceDiscoveryStrategy,
+				Plugin: &v1.PluginCommandLineFlags{
+					PassDeviceSpecs:               &c.DevicePlugin.PassDeviceSpecs,
+					DeviceListStrategy:            &deviceListStrategy,
+					DeviceIDStrategy:              &c.DevicePlugin.DeviceIDStrategy,
+					CDIAnnotationPrefix:           &c.DevicePlugin.CDIAnnotationPrefix,
+					NvidiaCTKPath:                 &c.DevicePlugin.NvidiaCTKPath,
+					ContainerDriverRoot:           &c.Common.ContainerDriverRoot,
+					SharedDevicesAllocationPolicy: &c.DevicePlugin.SharedDevicesAllocationPolicy,
+					HealthRecoveryPolicy:          &c.DevicePlugin.HealthRecoveryPolicy,
+					HealthRecoveryQuietPeriod:     &c.DevicePlugin.HealthRecoveryQuietPeriod,
+					CCMode:                        &c.DevicePlugin.CCMode,
+				},
+				GFD: &v1.GFDCommandLineFlags{
+					Oneshot:         &c.FeatureDiscovery.Oneshot,
+					NoTimestamp:     &c.FeatureDiscovery.NoTimestamp,
+					SleepInterval:   &c.FeatureDiscovery.SleepInterval,
+					OutputFile:      &c.FeatureDiscovery.OutputFile,
+					MachineTypeFile: &c.FeatureDiscovery.MachineTypeFile,
+					DeviceLabels:    &c.FeatureDiscovery.DeviceLabels,
+					MigPollInterval: &c.FeatureDiscovery.MigPollInterval,
+					TriggerSocket:   &c.FeatureDiscovery.TriggerSocket,
+				},
+			},
+		},
+	}
+	config.Resources = c.Common.Resources.toV1()
+	config.Sharing = c.Common.Sharing.toV1()
+	config.Imex = v1.Imex(c.DevicePlugin.Imex)
+	config.HealthChecks = c.DevicePlugin.HealthChecks.toV1()
+	return config, nil
+}
+
+// ConvertFromV1 converts a v1 config to a v2 config. Options that are not set
+// in the v1 config take their default values.
+func ConvertFromV1(config *v1.Config) (*Config, error) {
+	if config.Version != "" && config.Version != v1.Version {
+		return nil, fmt.Errorf("unknown version: %v", config.Version)
+	}
+
+	c := Default()
+
+	flags := config.Flags.CommandLineFlags
+	set(&c.Common.MigStrategy, flags.MigStrategy)
+	set(&c.Common.FailOnInitError, flags.FailOnInitError)
+	set(&c.Common.DeviceDiscoveryStrategy, flags.DeviceDiscoveryStrategy)
+	set(&c.Common.NvidiaDriverRoot, flags.NvidiaDriverRoot)
+	set(&c.Common.NvidiaDevRoot, flags.NvidiaDevRoot)
+	set(&c.DevicePlugin.MPSRoot, flags.MpsRoot)
+	set(&c.DevicePlugin.GDRCopyEnabled, flags.GDRCopyEnabled)
+	set(&c.DevicePlugin.GDSEnabled, flags.GDSEnabled)
+	set(&c.DevicePlugin.MOFEDEnabled, flags.MOFEDEnabled)
+	set(&c.FeatureDiscovery.UseNodeFeatureAPI, flags.UseNodeFeatureAPI)
+
+	if plugin := flags.Plugin; plugin != nil {
+		set(&c.DevicePlugin.PassDeviceSpecs, plugin.PassDeviceSpecs)
+		if plugin.DeviceListStrategy != nil {
+			c.DevicePlugin.DeviceListStrategy = *plugin.DeviceListStrategy
+		}
+		set(&c.DevicePlugin.DeviceIDStrategy, plugin.DeviceIDStrategy)
+		set(&c.DevicePlugin.CDIAnnotationPrefix, plugin.CDIAnnotationPrefix)
+		set(&c.DevicePlugin.NvidiaCTKPath, plugin.NvidiaCTKPath)
+		set(&c.Common.ContainerDriverRoot, plugin.ContainerDriverRoot)
+		set(&c.DevicePlugin.SharedDevicesAllocationPolicy, plugin.SharedDevicesAllocationPolicy)
+		set(&c.DevicePlugin.HealthRecoveryPolicy, plugin.HealthRecoveryPolicy)
+		set(&c.DevicePlugin.HealthRecoveryQuietPeriod, plugin.HealthRecoveryQuietPeriod)
+		set(&c.DevicePlugin.CCMode, plugin.CCMode)
+	}
+
+	if gfd := flags.GFD; gfd != nil {
+		set(&c.FeatureDiscovery.Oneshot, gfd.Oneshot)
+		set(&c.FeatureDiscovery.NoTimestamp, gfd.NoTimestamp)
+		set(&c.FeatureDiscovery.SleepInterval, gfd.SleepInterval)
+		set(&c.FeatureDiscovery.OutputFile, gfd.OutputFile)
+		set(&c.FeatureDiscovery.MachineTypeFile, gfd.MachineTypeFile)
+		set(&c.FeatureDiscovery.DeviceLabels, gfd.DeviceLabels)
+		set(&c.FeatureDiscovery.MigPollInterval, gfd.MigPollInterval)
+		set(&c.FeatureDiscovery.TriggerSocket, gfd.TriggerSocket)
+	}
+
+	c.Common.Resources = convertResources(config.Resources)
+	c.Common.Sharing.TimeSlicing.convertFrom(&config.Sharing.TimeSlicing)
+	c.Common.Sharing.MPS.convertFrom(config.Sharing.MPS)
+	c.DevicePlugin.Imex = Imex(config.Imex)
+	c.DevicePlugin.HealthChecks.convertFrom(config.HealthChecks)
+
+	return c, nil
+}
+
+// set sets the option at dst to the value of the specified v1 option if this
+// is set.
+func set[T any](dst *T, value *T) {
+	if value != nil {
+		*dst = *value
+	}
+}
+
+// optional returns a reference to the specified value or nil if it is the
+// zero value.
+func optional[T any](value T) *T {
+	if reflect.ValueOf(value).IsZero() {
+		return nil
+	}
+	return &value
+}
+
+func (r Resources) toV1() v1.Resources {
+	var resources v1.Resources
+	for _, gpu := range r.GPUs {
+		resources.GPUs = append(resources.GPUs, v1.Resource{Pattern: v1.ResourcePattern(gpu.Pattern), Name: v1.ResourceName(gpu.Name)})
+	}
+	for _, mig := range r.MIGs {
+		resources.MIGs = append(resources.MIGs, v1.Resource{Pattern: v1.ResourcePattern(mig.Pattern), Name: v1.ResourceName(mig.Name)})
+	}
+	return resources
+}
+
+func convertResources(resources v1.Resources) Resources {
+	var r Resources
+	for _, gpu := range resources.GPUs {
+		r.GPUs = append(r.GPUs, Resource{Pattern: string(gpu.Pattern), Name: string(gpu.Name)})
+	}
+	for _, mig := range resources.MIGs {
+		r.MIGs = append(r.MIGs, Resource{Pattern: string(mig.Pattern), Name: string(mig.Name)})
+	}
+	return r
+}
+
+// toV1 converts the sharing options. A v1 config only includes the MPS
+// section if resources are shared using MPS.
+func (s Sharing) toV1() v1.Sharing {
+	var sharing v1.Sharing
+	if len(s.TimeSlicing.Resources) > 0 {
+		sharing.TimeSlicing = s.TimeSlicing.toV1()
+	}
+	if len(s.MPS.Resources) > 0 {
+		mps := s.MPS.toV1()
+		sharing.MPS = &mps
+	}
+	return sharing
+}
+
+func (s SharedResources) toV1() v1.ReplicatedResources {
+	resources := v1.ReplicatedResources{
+		RenameByDefault:            s.RenameByDefault,
+		FailRequestsGreaterThanOne: &s.FailRequestsGreaterThanOne,
+	}
+	for _, r := range s.Resources {
+		resource := v1.ReplicatedResource{
+			Name:              v1.ResourceName(r.Name),
+			Rename:            v1.ResourceName(r.Rename),
+			Devices:           r.Devices,
+			Replicas:          r.Replicas,
+			MemoryLimit:       optional(r.MemoryLimit),
+			PinnedMemoryLimit: optional(r.PinnedMemoryLimit),
+		}
+		if optional(r.Devices) == nil {
+			resource.Devices = v1.ReplicatedDevices{All: true}
+		}
+		if r.ActiveThreadPercentage != 0 {
+			resource.ActiveThreadPercentage = &r.ActiveThreadPercentage
+		}
+		for _, dr := range r.DeviceReplicas {
+			resource.DeviceReplicas = append(resource.DeviceReplicas, v1.DeviceReplicas(dr))
+		}
+		resources.Resources = append(resources.Resources, resource)
+	}
+	return resources
+}
+
+// convertFrom sets the shared resources from the specified v1 section if
+// this is set. An unset v1 option keeps its default value.
+func (s *SharedResources) convertFrom(resources *v1.ReplicatedResources) {
+	if resources == nil {
+		return
+	}
+	s.RenameByDefault = resources.RenameByDefault
+	set(&s.FailRequestsGreaterThanOne, resources.FailRequestsGreaterThanOne)
+	for _, rr := range resources.Resources {
+		r := SharedResource{
+			Name:     string(rr.Name),
+			Rename:   string(rr.Rename),
+			Devices:  rr.Devices,
+			Replicas: rr.Replicas,
+		}
+		set(&r.MemoryLimit, rr.MemoryLimit)
+		set(&r.ActiveThreadPercentage, rr.ActiveThreadPercentage)
+		set(&r.PinnedMemoryLimit, rr.PinnedMemoryLimit)
+		for _, dr := range rr.DeviceReplicas {
+			r.DeviceReplicas = append(r.DeviceReplicas, DeviceReplicas(dr))
+		}
+		s.Resources = append(s.Resources, r)
+	}
+}
+
+// toV1 converts the health checks. Since the v1 health rules are only
+// evaluated if they are specified, these are omitted if no rule is set.
+func (h HealthChecks) toV1() v1.HealthChecks {
+	healthChecks := v1.HealthChecks{
+		DisabledXIDs: optional(h.DisabledXIDs),
+		EnabledXIDs:  optional(h.EnabledXIDs),
+		EventTypes:   h.EventTypes,
+	}
+	if h.Rules != (HealthRules{PollInterval: h.Rules.PollInterval}) {
+		healthChecks.Rules = h.Rules.toV1()
+	}
+	for _, r := range h.Resources {
+		resource := v1.ResourceHealthChecks{
+			Name:         v1.ResourceName(r.Name),
+			DisabledXIDs: r.DisabledXIDs,
+			EnabledXIDs:  r.EnabledXIDs,
+			EventTypes:   r.EventTypes,
+		}
+		if r.Rules != nil {
+			resource.Rules = r.Rules.toV1()
+		}
+		healthChecks.Resources = append(healthChecks.Resources, resource)
+	}
+	return healthChecks
+}
+
+// convertFrom sets the health checks from the specified v1 section. Options
+// that are not set keep their default values.
+func (h *HealthChecks) convertFrom(healthChecks v1.HealthChecks) {
+	set(&h.DisabledXIDs, healthChecks.DisabledXIDs)
+	set(&h.EnabledXIDs, healthChecks.EnabledXIDs)
+	if len(healthChecks.EventTypes) > 0 {
+		h.EventTypes = healthChecks.EventTypes
+	}
+	if healthChecks.Rules != nil {
+		h.Rules.convertFrom(healthChecks.Rules)
+	}
+	for _, r := range healthChecks.Resources {
+		resource := ResourceHealthChecks{
+			Name:         string(r.Name),
+			DisabledXIDs: r.DisabledXIDs,
+			EnabledXIDs:  r.EnabledXIDs,
+			EventTypes:   r.EventTypes,
+		}
+		if r.Rules != nil {
+			resource.Rules = &HealthRules{}
+			resource.Rules.convertFrom(r.Rules)
+		}
+		h.Resources = append(h.Resources, resource)
+	}
+}
+
+func (r HealthRules) toV1() *v1.HealthRules {
+	return &v1.HealthRules{
+		PollInterval:  optional(r.PollInterval),
+		DoubleBitECC:  r.DoubleBitECC,
+		RetiredPages:  r.RetiredPages,
+		RemappedRows:  r.RemappedRows,
+		Temperature:   r.Temperature,
+		Power:         r.Power,
+		ClockThrottle: r.ClockThrottle,
+	}
+}
+
+func (r *HealthRules) convertFrom(rules *v1.HealthRules) {
+	set(&r.PollInterval, rules.PollInterval)
+	r.DoubleBitECC = rules.DoubleBitECC
+	r.RetiredPages = rules.RetiredPages
+	r.RemappedRows = rules.RemappedRows
+	r.Temperature = rules.Temperature
+	r.Power = rules.Power
+	r.ClockThrottle = rules.ClockThrottle
+}
+
+// fieldPaths maps the paths of the fields of a v1 config to the paths of the
+// corresponding fields of a v2 config. The first matching prefix applies.
+var fieldPaths = []struct {
+	v1 string
+	v2 string
+}{
+	{"flags.plugin.containerDriverRoot", "common.containerDriverRoot"},
+	{"flags.plugin", "devicePlugin"},
+	{"flags.gfd", "featureDiscovery"},
+	{"flags.mpsRoot", "devicePlugin.mpsRoot"},
+	{"flags.gdrcopyEnabled", "devicePlugin.gdrcopyEnabled"},
+	{"flags.gdsEnabled", "devicePlugin.gdsEnabled"},
+	{"flags.mofedEnabled", "devicePlugin.mofedEnabled"},
+	{"flags.useNodeFeatureAPI", "featureDiscovery.useNodeFeatureAPI"},
+	{"flags", "common"},
+	{"resources", "common.resources"},
+	{"sharing", "common.sharing"},
+	{"imex", "devicePlugin.imex"},
+	{"healthChecks", "devicePlugin.healthChecks"},
+}
+
+// FieldPath returns the path of the field of a v2 config that corresponds to
+// the specified field of a v1 config.
+func (c *Config) FieldPath(v1Path string) string {
+	for _, p := range fieldPaths {
+		if rest, found := strings.CutPrefix(v1Path, p.v1); found && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+			return p.v2 + rest
+		}
+	}
+	return v1Path
+}
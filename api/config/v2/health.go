@@ -0,0 +1,67 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+import (
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// HealthChecks holds the health checks that are performed on the devices.
+type HealthChecks struct {
+	// DisabledXIDs are ignored in addition to the application XIDs that are
+	// always ignored.
+	DisabledXIDs v1.XIDs `json:"disabledXIDs,omitzero" yaml:"disabledXIDs,omitzero"`
+	// EnabledXIDs take precedence over the disabled XIDs.
+	EnabledXIDs v1.XIDs              `json:"enabledXIDs,omitzero" yaml:"enabledXIDs,omitzero"`
+	EventTypes  []v1.HealthEventType `json:"eventTypes"           yaml:"eventTypes"`
+	Rules       HealthRules          `json:"rules"                yaml:"rules"`
+	// Resources holds per-resource overrides of the health checks.
+	Resources []ResourceHealthChecks `json:"resources,omitempty" yaml:"resources,omitempty"`
+}
+
+// ResourceHealthChecks overrides the health checks for a specific resource.
+// Options that are not set are inherited from the top-level health checks.
+type ResourceHealthChecks struct {
+	Name         string               `json:"name"                   yaml:"name"`
+	DisabledXIDs *v1.XIDs             `json:"disabledXIDs,omitempty" yaml:"disabledXIDs,omitempty"`
+	EnabledXIDs  *v1.XIDs             `json:"enabledXIDs,omitempty"  yaml:"enabledXIDs,omitempty"`
+	EventTypes   []v1.HealthEventType `json:"eventTypes,omitempty"   yaml:"eventTypes,omitempty"`
+	Rules        *HealthRules         `json:"rules,omitempty"        yaml:"rules,omitempty"`
+}
+
+// HealthRules holds the health rules that are evaluated by polling the state
+// of each device. A rule is only evaluated if it is specified.
+type HealthRules struct {
+	PollInterval  v1.Duration           `json:"pollInterval,omitzero"   yaml:"pollInterval,omitzero"`
+	DoubleBitECC  *v1.DoubleBitECCRule  `json:"doubleBitECC,omitempty"  yaml:"doubleBitECC,omitempty"`
+	RetiredPages  *v1.RetiredPagesRule  `json:"retiredPages,omitempty"  yaml:"retiredPages,omitempty"`
+	RemappedRows  *v1.RemappedRowsRule  `json:"remappedRows,omitempty"  yaml:"remappedRows,omitempty"`
+	Temperature   *v1.TemperatureRule   `json:"temperature,omitempty"   yaml:"temperature,omitempty"`
+	Power         *v1.PowerRule         `json:"power,omitempty"         yaml:"power,omitempty"`
+	ClockThrottle *v1.ClockThrottleRule `json:"clockThrottle,omitempty" yaml:"clockThrottle,omitempty"`
+}
+
+// DefaultHealthChecks returns the default health checks, which watch all
+// supported event types.
+func DefaultHealthChecks() HealthChecks {
+	return HealthChecks{
+		EventTypes: v1.DefaultHealthEventTypes(),
+		Rules: HealthRules{
+			PollInterval: v1.Duration(v1.DefaultHealthRulesPollInterval),
+		},
+	}
+}
@@ -0,0 +1,31 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+// Resources holds the names under which full GPUs and MIG devices are
+// advertised.
+type Resources struct {
+	GPUs []Resource `json:"gpus,omitempty" yaml:"gpus,omitempty"`
+	MIGs []Resource `json:"mig,omitempty"  yaml:"mig,omitempty"`
+}
+
+// Resource advertises the devices whose product name matches the pattern
+// under the specified resource name.
+type Resource struct {
+	Pattern string `json:"pattern" yaml:"pattern"`
+	Name    string `json:"name"    yaml:"name"`
+}
@@ -0,0 +1,67 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package v2
+
+import (
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// Sharing holds the resources that are shared using time-slicing or MPS. If
+// any resources are shared using MPS, the time-slicing resources are ignored.
+type Sharing struct {
+	TimeSlicing SharedResources `json:"timeSlicing" yaml:"timeSlicing"`
+	MPS         SharedResources `json:"mps"         yaml:"mps"`
+}
+
+// SharedResources holds the resources that are shared using one of the
+// sharing strategies.
+type SharedResources struct {
+	RenameByDefault            bool             `json:"renameByDefault"            yaml:"renameByDefault"`
+	FailRequestsGreaterThanOne bool             `json:"failRequestsGreaterThanOne" yaml:"failRequestsGreaterThanOne"`
+	Resources                  []SharedResource `json:"resources,omitempty"        yaml:"resources,omitempty"`
+}
+
+// SharedResource defines the number of replicas that are advertised for each
+// device of a resource. If no devices are specified, all devices of the
+// resource are shared.
+type SharedResource struct {
+	Name                   string                `json:"name"                             yaml:"name"`
+	Rename                 string                `json:"rename,omitempty"                 yaml:"rename,omitempty"`
+	Devices                v1.ReplicatedDevices  `json:"devices,omitzero"                 yaml:"devices,omitzero"`
+	Replicas               int                   `json:"replicas"                         yaml:"replicas"`
+	DeviceReplicas         []DeviceReplicas      `json:"deviceReplicas,omitempty"         yaml:"deviceReplicas,omitempty"`
+	MemoryLimit            v1.ReplicaMemoryLimit `json:"memoryLimit,omitzero"             yaml:"memoryLimit,omitzero"`
+	ActiveThreadPercentage int                   `json:"activeThreadPercentage,omitempty" yaml:"activeThreadPercentage,omitempty"`
+	PinnedMemoryLimit      v1.ReplicaMemoryLimit `json:"pinnedMemoryLimit,omitzero"       yaml:"pinnedMemoryLimit,omitzero"`
+}
+
+// DeviceReplicas overrides the number of replicas for a list of devices.
+type DeviceReplicas struct {
+	Devices  v1.ReplicatedDevices `json:"devices"  yaml:"devices"`
+	Replicas int                  `json:"replicas" yaml:"replicas"`
+}
+
+// DefaultSharing returns the default sharing options. Requests for more than
+// one MPS replica fail by default since a container cannot use more than one
+// replica of a device shared using MPS.
+func DefaultSharing() Sharing {
+	return Sharing{
+		MPS: SharedResources{
+			FailRequestsGreaterThanOne: true,
+		},
+	}
+}
@@ -0,0 +1,99 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package versioned loads config files of all supported versions. Since the
+// components are configured through a v1 config, config files of other
+// versions are converted to a v1 config.
+package versioned
+
+import (
+	"fmt"
+
+	cli "github.com/urfave/cli/v2"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	v2 "github.com/NVIDIA/k8s-device-plugin/api/config/v2"
+)
+
+// NewConfig builds out a v1 config from a config file of any supported
+// version (or command line flags) as v1.NewConfig does.
+func NewConfig(c *cli.Context, flags []cli.Flag) (*v1.Config, error) {
+	config, _, err := NewConfigWithContents(c, flags)
+	return config, err
+}
+
+// NewConfigWithContents builds out a v1 config as NewConfig does and also
+// returns the contents of the config file as v1.NewConfigWithContents does.
+func NewConfigWithContents(c *cli.Context, flags []cli.Flag) (*v1.Config, []byte, error) {
+	return v1.NewConfigWithParser(c, flags, Parse)
+}
+
+// Parse parses the contents of a config file of any supported version into a
+// v1 config. A config of another version is converted and then parsed as a
+// v1 config so that the same checks and defaults apply.
+func Parse(contents []byte) (*v1.Config, error) {
+	switch version := getVersion(contents); version {
+	case "", v1.Version:
+		return v1.Parse(contents)
+	case v2.Version:
+		config, err := v2.Parse(contents)
+		if err != nil {
+			return nil, err
+		}
+		return parseConverted(config)
+	default:
+		return nil, fmt.Errorf("unknown version: %v", version)
+	}
+}
+
+// ValidateConfig validates the contents of a config file of any supported
+// version as v1.ValidateConfig does. The errors in a config file of another
+// version are reported with the paths of the fields of that version.
+func ValidateConfig(contents []byte, ignoredFields ...string) (*v1.ValidationResult, error) {
+	switch getVersion(contents) {
+	case v2.Version:
+		return v1.ValidateVersionedConfig(contents, v2.Default(), ignoredFields...)
+	default:
+		return v1.ValidateConfig(contents, ignoredFields...)
+	}
+}
+
+// parseConverted converts the specified config to a v1 config and parses the
+// result.
+func parseConverted(config v1.VersionedConfig) (*v1.Config, error) {
+	converted, err := config.ToV1()
+	if err != nil {
+		return nil, err
+	}
+	contents, err := yaml.Marshal(converted)
+	if err != nil {
+		return nil, fmt.Errorf("marshal error: %v", err)
+	}
+	return v1.Parse(contents)
+}
+
+// getVersion returns the version of the specified config. An empty string is
+// returned if the version is not set or cannot be determined.
+func getVersion(contents []byte) string {
+	var versioned struct {
+		Version string `json:"version"`
+	}
+	if err := yaml.Unmarshal(contents, &versioned); err != nil {
+		return ""
+	}
+	return versioned.Version
+}
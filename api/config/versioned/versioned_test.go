@@ -0,0 +1,259 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package versioned
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v2"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		description    string
+		config         string
+		expectedError  bool
+		expectedConfig func(*v1.Config)
+	}{
+		{
+			description: "v1 config",
+			config: `
+version: v1
+flags:
+  migStrategy: mixed
+`,
+			expectedConfig: func(c *v1.Config) {
+				require.Equal(t, ptr.To(v1.MigStrategyMixed), c.Flags.MigStrategy)
+			},
+		},
+		{
+			description: "v2 config is converted",
+			config: `
+version: v2
+common:
+  migStrategy: single
+  sharing:
+    mps:
+      resources:
+      - name: gpu
+        devices: [0]
+        replicas: 2
+`,
+			expectedConfig: func(c *v1.Config) {
+				require.Equal(t, v1.Version, c.Version)
+				require.Equal(t, ptr.To(v1.MigStrategySingle), c.Flags.MigStrategy)
+				require.NotNil(t, c.Sharing.MPS)
+				require.Equal(t, ptr.To(true), c.Sharing.MPS.FailRequestsGreaterThanOne)
+				// The shared resources are checked and defaulted as in a v1 config.
+				require.Equal(t, v1.ResourceName("nvidia.com/gpu"), c.Sharing.MPS.Resources[0].Name)
+				require.Equal(t, v1.ResourceName("nvidia.com/gpu.shared"), c.Sharing.MPS.Resources[0].Rename)
+			},
+		},
+		{
+			description: "v2 config is checked as a v1 config",
+			config: `
+version: v2
+common:
+  sharing:
+    timeSlicing:
+      resources:
+      - name: nvidia.com/gpu
+        replicas: 1
+`,
+			expectedError: true,
+		},
+		{
+			description:   "unknown version",
+			config:        `version: v3`,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config, err := Parse([]byte(tc.config))
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			tc.expectedConfig(config)
+		})
+	}
+}
+
+func TestNewConfigFromV2File(t *testing.T) {
+	configFile := filepath.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(configFile, []byte(`
+version: v2
+common:
+  migStrategy: single
+devicePlugin:
+  passDeviceSpecs: true
+`), 0600)
+	require.NoError(t, err)
+
+	var flags []cli.Flag
+	for _, name := range []string{"mig-strategy", "fail-on-init-error", "pass-device-specs", "config-file"} {
+		flags = append(flags, &cli.StringFlag{Name: name})
+	}
+
+	app := cli.NewApp()
+	app.Flags = flags
+	app.Action = func(c *cli.Context) error {
+		config, err := NewConfig(c, flags)
+		require.NoError(t, err)
+		require.Equal(t, ptr.To(v1.MigStrategySingle), config.Flags.MigStrategy)
+		require.Equal(t, ptr.To(true), config.Flags.FailOnInitError)
+		require.Equal(t, ptr.To(true), config.Flags.Plugin.PassDeviceSpecs)
+
+		// A v1 config does not accept other versions.
+		_, err = v1.NewConfig(c, flags)
+		require.Error(t, err)
+		return nil
+	}
+	require.NoError(t, app.Run([]string{"app", "--config-file", configFile}))
+}
+
+func TestValidateConfig(t *testing.T) {
+	testCases := []struct {
+		description   string
+		config        string
+		ignoredFields []string
+		errors        []string
+		warnings      []string
+	}{
+		{
+			description: "valid config",
+			config: `
+version: v2
+common:
+  migStrategy: mixed
+  sharing:
+    timeSlicing:
+      resources:
+      - name: nvidia.com/gpu
+        replicas: 2
+devicePlugin:
+  deviceListStrategy: [envvar, cdi-cri]
+`,
+		},
+		{
+			description: "errors are reported with the paths of the v2 fields",
+			config: `
+version: v2
+common:
+  migStrategy: all
+  sharing:
+    timeSlicing:
+      resources:
+      - name: nvidia.com/gpu
+        replicas: 2
+        activeThreadPercentage: 50
+devicePlugin:
+  deviceIDStrategy: serial
+  imex:
+    channelIDs: [1]
+`,
+			errors: []string{
+				"common.migStrategy",
+				"devicePlugin.deviceIDStrategy",
+				"devicePlugin.imex.channelIDs",
+				"common.sharing.timeSlicing.resources[0].activeThreadPercentage",
+			},
+		},
+		{
+			description: "the checks of the v1 sections are reported with the paths of the v2 fields",
+			config: `
+version: v2
+common:
+  sharing:
+    timeSlicing:
+      resources:
+      - name: nvidia.com/gpu
+        replicas: 1
+devicePlugin:
+  healthChecks:
+    rules:
+      power:
+        maxPercentOfLimit: 0
+`,
+			errors: []string{
+				"devicePlugin.healthChecks.rules.power.maxPercentOfLimit",
+				"common.sharing.timeSlicing.resources[0].replicas",
+			},
+		},
+		{
+			description: "v1 fields are unknown",
+			config: `
+version: v2
+flags:
+  migStrategy: none
+`,
+			errors: []string{
+				"flags",
+			},
+		},
+		{
+			description: "ignored fields are mapped to their v2 paths",
+			config: `
+version: v2
+featureDiscovery:
+  oneshot: true
+`,
+			ignoredFields: []string{"flags.gfd"},
+			warnings: []string{
+				"featureDiscovery",
+			},
+		},
+		{
+			description: "v1 config",
+			config: `
+version: v1
+flags:
+  migStrategy: all
+`,
+			errors: []string{
+				"flags.migStrategy",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			result, err := ValidateConfig([]byte(tc.config), tc.ignoredFields...)
+			require.NoError(t, err)
+			require.Equal(t, tc.errors, errorPaths(result.Errors), result.Errors)
+			require.Equal(t, tc.warnings, errorPaths(result.Warnings), result.Warnings)
+		})
+	}
+}
+
+func errorPaths(errs []*field.Error) []string {
+	var paths []string
+	for _, err := range errs {
+		paths = append(paths, err.Field)
+	}
+	return paths
+}
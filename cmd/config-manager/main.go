@@ -17,22 +17,35 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
+	"path"
 	"path/filepath"
+	"slices"
 	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/prometheus/procfs"
 	cli "github.com/urfave/cli/v2"
 
 	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/yaml"
+
+	"github.com/NVIDIA/k8s-device-plugin/api/config/versioned"
+	"github.com/NVIDIA/k8s-device-plugin/internal/status"
 )
 
 const (
@@ -47,13 +60,40 @@ const (
 	DefaultSignal          = int(syscall.SIGHUP)
 	DefaultProcessToSignal = "nvidia-device-plugin"
 	DefaultConfigLabel     = "nvidia.com/device-plugin.config"
+	DefaultProductLabel    = "nvidia.com/gpu.product"
+	DefaultProductConfigs  = "product-configs"
+	DefaultStatusTimeout   = 2 * time.Minute
+)
+
+// These constants represent the annotations through which the state of the
+// config is reported on the node. They are appended to the
+// <state-annotation-prefix>.
+const (
+	StateAnnotationRequested = "requested"
+	StateAnnotationApplied   = "applied"
+	StateAnnotationState     = "state"
+	StateAnnotationError     = "error"
+	StateAnnotationTimestamp = "timestamp"
+)
+
+// These constants represent the states of the config reported on the node.
+const (
+	ConfigStateSuccess = "success"
+	ConfigStateFailed  = "failed"
 )
 
+// statusPollInterval is the interval at which the status file is checked
+// while waiting for a config to be applied.
+var statusPollInterval = time.Second
+
 // These constants represent the various FallbackStrategies that are possible
 const (
 	FallbackStrategyNamedConfig  = "named"
 	FallbackStrategySingleConfig = "single"
 	FallbackStrategyEmptyConfig  = "empty"
+	// FallbackStrategyProductConfig selects a config by the GPU product
+	// of the node through the mapping in <product-configs>.
+	FallbackStrategyProductConfig = "product"
 )
 
 // NamedConfigFallback is the name of the config to look for when applying FallbackStrategyNamedConfig
@@ -65,13 +105,24 @@ type Flags struct {
 	Kubeconfig         string
 	NodeName           string
 	NodeLabel          string
+	ProductLabel       string
+	ProductConfigs     string
 	ConfigFileSrcdir   string
 	ConfigFileDst      string
+	ConfigMapName      string
+	ConfigMapNamespace string
 	DefaultConfig      string
 	FallbackStrategies cli.StringSlice
 	SendSignal         bool
 	Signal             int
 	ProcessToSignal    string
+	SignalTargets      cli.StringSlice
+	PodName            string
+	PodNamespace       string
+	StatusFile         string
+	StatusTimeout      time.Duration
+
+	StateAnnotationPrefix string
 }
 
 // SyncableConfig is used to synchronize on changes to a configuration value
@@ -85,6 +136,83 @@ type SyncableConfig struct {
 	lastRead *string
 }
 
+// ProductConfig maps the GPU products matching a glob pattern to the name of
+// a config.
+type ProductConfig struct {
+	Product string `json:"product"`
+	Config  string `json:"config"`
+}
+
+// NodeFacts holds the facts about the node that are used by the fallback
+// strategies.
+type NodeFacts struct {
+	sync.Mutex
+	product string
+}
+
+// SetProduct sets the GPU product of the node and returns whether it changed.
+func (n *NodeFacts) SetProduct(product string) bool {
+	n.Lock()
+	defer n.Unlock()
+	changed := n.product != product
+	n.product = product
+	return changed
+}
+
+// Product returns the GPU product of the node.
+func (n *NodeFacts) Product() string {
+	n.Lock()
+	defer n.Unlock()
+	return n.product
+}
+
+// sharedProductSuffix is appended to the product label by
+// gpu-feature-discovery if the devices of a resource are shared without
+// renaming the resource.
+const sharedProductSuffix = "-SHARED"
+
+// getNodeProduct returns the GPU product of the node from the specified node
+// labels. If the default product label is used but not set, the product label
+// of another full GPU resource is used instead, since gpu-feature-discovery
+// labels the product of each resource under the name of the resource (e.g.
+// if the GPUs are advertised under a custom or renamed resource). The suffix
+// that marks a shared resource is removed, since it depends on the config
+// selected for the product.
+func getNodeProduct(labels map[string]string, f *Flags) string {
+	product, exists := labels[f.ProductLabel]
+	if !exists && f.ProductLabel == DefaultProductLabel {
+		var keys []string
+		for key := range labels {
+			name, found := strings.CutPrefix(key, "nvidia.com/")
+			if !found || !strings.HasSuffix(name, ".product") || strings.HasPrefix(name, "mig-") {
+				continue
+			}
+			keys = append(keys, key)
+		}
+		if len(keys) > 0 {
+			slices.Sort(keys)
+			product = labels[keys[0]]
+		}
+	}
+	return strings.TrimSuffix(product, sharedProductSuffix)
+}
+
+// rejectedConfigError is returned if a config was not applied, but the
+// previous config is still in place. The config manager keeps syncing after
+// such an error, since a later change to the label or the configs may resolve
+// it.
+type rejectedConfigError struct {
+	err error
+}
+
+func (e *rejectedConfigError) Error() string {
+	return e.err.Error()
+}
+
+func (e *rejectedConfigError) Unwrap() error {
+	return e.err
+}
+
 // NewSyncableConfig creates a new SyncableConfig
 func NewSyncableConfig(f *Flags) *SyncableConfig {
 	var m SyncableConfig
@@ -114,6 +242,16 @@ func (m *SyncableConfig) Get() string {
 	return *m.lastRead
 }
 
+// Resync unblocks all callers of Get() and causes the next call to Get() to
+// return the current value, even though it has not changed. This is used to
+// reapply a config whose contents have changed.
+func (m *SyncableConfig) Resync() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.lastRead = nil
+	m.cond.Broadcast()
+}
+
 func main() {
 	flags := Flags{}
 
@@ -154,13 +292,41 @@ func main() {
 			Destination: &flags.NodeLabel,
 			EnvVars:     []string{"NODE_LABEL"},
 		},
+		&cli.StringFlag{
+			Name:        "product-label",
+			Value:       DefaultProductLabel,
+			Usage:       "the name of the node label holding the GPU product used by the 'product' fallback strategy",
+			Destination: &flags.ProductLabel,
+			EnvVars:     []string{"PRODUCT_LABEL"},
+		},
+		&cli.StringFlag{
+			Name:        "product-configs",
+			Value:       DefaultProductConfigs,
+			Usage:       "the name of the file among the available configs that maps GPU products to configs for the 'product' fallback strategy; this file is not a config itself",
+			Destination: &flags.ProductConfigs,
+			EnvVars:     []string{"PRODUCT_CONFIGS"},
+		},
 		&cli.StringFlag{
 			Name:        "config-file-srcdir",
 			Value:       "",
-			Usage:       "the path to the directory containing available device configuration files",
+			Usage:       "the path to the directory containing available device configuration files; ignored if <config-map-name> is set",
 			Destination: &flags.ConfigFileSrcdir,
 			EnvVars:     []string{"CONFIG_FILE_SRCDIR"},
 		},
+		&cli.StringFlag{
+			Name:        "config-map-name",
+			Value:       "",
+			Usage:       "the name of a ConfigMap to watch for the available device configurations; each key holds a configuration that is written to <config-file-dst> when selected",
+			Destination: &flags.ConfigMapName,
+			EnvVars:     []string{"CONFIG_MAP_NAME"},
+		},
+		&cli.StringFlag{
+			Name:        "config-map-namespace",
+			Value:       "",
+			Usage:       "the namespace of the ConfigMap set in <config-map-name>",
+			Destination: &flags.ConfigMapNamespace,
+			EnvVars:     []string{"CONFIG_MAP_NAMESPACE"},
+		},
 		&cli.StringFlag{
 			Name:        "config-file-dst",
 			Value:       "",
@@ -177,7 +343,7 @@ func main() {
 		},
 		&cli.StringSliceFlag{
 			Name:        "fallback-strategies",
-			Usage:       "ordered list of fallback strategies to use to set a default config when none is provided",
+			Usage:       "ordered list of fallback strategies to use to set a default config when none is provided; 'product' must be followed by 'named' or 'empty'",
 			Destination: &flags.FallbackStrategies,
 			EnvVars:     []string{"FALLBACK_STRATEGIES"},
 		},
@@ -202,6 +368,46 @@ func main() {
 			Destination: &flags.ProcessToSignal,
 			EnvVars:     []string{"PROCESS_TO_SIGNAL"},
 		},
+		&cli.StringSliceFlag{
+			Name:        "signal-target",
+			Usage:       "a target to signal if <send-signal> is set, of the form <kind>:<signal>:<value>; <kind> is one of 'process' (argv[0] equals <value>), 'pidfile' (pid read from the file <value>), 'cmdline' (full command line matches the regular expression <value>), or 'container' (main process of the container named <value> in this pod); an empty <signal> defaults to <signal>; if set, <process-to-signal> is ignored",
+			Destination: &flags.SignalTargets,
+			EnvVars:     []string{"SIGNAL_TARGETS"},
+		},
+		&cli.StringFlag{
+			Name:        "pod-name",
+			Value:       "",
+			Usage:       "the name of the pod of the config manager; required for 'container' signal targets",
+			Destination: &flags.PodName,
+			EnvVars:     []string{"POD_NAME"},
+		},
+		&cli.StringFlag{
+			Name:        "pod-namespace",
+			Value:       "",
+			Usage:       "the namespace of the pod of the config manager; required for 'container' signal targets",
+			Destination: &flags.PodNamespace,
+			EnvVars:     []string{"POD_NAMESPACE"},
+		},
+		&cli.StringFlag{
+			Name:        "status-file",
+			Value:       "",
+			Usage:       "the path to the status file of <process-to-signal>; if set, a config change is rolled back unless the process reports that it applied the config within <status-timeout>",
+			Destination: &flags.StatusFile,
+			EnvVars:     []string{"STATUS_FILE"},
+		},
+		&cli.DurationFlag{
+			Name:        "status-timeout",
+			Value:       DefaultStatusTimeout,
+			Usage:       "the time to wait for <process-to-signal> to report that it applied a config if <status-file> is set",
+			Destination: &flags.StatusTimeout,
+			EnvVars:     []string{"STATUS_TIMEOUT"},
+		},
+		&cli.StringFlag{
+			Name:        "state-annotation-prefix",
+			Usage:       "the prefix of the node annotations through which the names of the requested and the applied config, the state of the update, the error, if any, and the time of the update are reported; this requires permission to patch the node; if empty, the state is not reported",
+			Destination: &flags.StateAnnotationPrefix,
+			EnvVars:     []string{"STATE_ANNOTATION_PREFIX"},
+		},
 	}
 
 	err := c.Run(os.Args)
@@ -218,12 +424,42 @@ func validateFlags(c *cli.Context, f *Flags) error {
 	if f.NodeLabel == "" {
 		return fmt.Errorf("invalid <node-label>: must not be empty string")
 	}
-	if f.ConfigFileSrcdir == "" {
-		return fmt.Errorf("invalid <config-file-srcdir>: must not be empty string")
+	if f.ConfigMapName == "" && f.ConfigFileSrcdir == "" {
+		return fmt.Errorf("invalid <config-file-srcdir>: must not be empty string if <config-map-name> is not set")
+	}
+	if f.ConfigMapName != "" && f.ConfigMapNamespace == "" {
+		return fmt.Errorf("invalid <config-map-namespace>: must not be empty string if <config-map-name> is set")
 	}
 	if f.ConfigFileDst == "" {
 		return fmt.Errorf("invalid <config-file-dst>: must not be empty string")
 	}
+	if _, err := getSignalTargets(f); err != nil {
+		return fmt.Errorf("invalid <signal-target>: %v", err)
+	}
+	if err := validateFallbackStrategies(f.FallbackStrategies.Value()); err != nil {
+		return fmt.Errorf("invalid <fallback-strategies>: %v", err)
+	}
+	return nil
+}
+
+// validateFallbackStrategies checks the order of the fallback strategies. The
+// product strategy relies on the product label set by gpu-feature-discovery,
+// which may not be set yet when the config manager starts. The strategy that
+// follows it selects the config until then, so it must be one that selects a
+// well-known config.
+func validateFallbackStrategies(strategies []string) error {
+	for i, strategy := range strategies {
+		if strategy != FallbackStrategyProductConfig {
+			continue
+		}
+		if i+1 < len(strategies) {
+			switch strategies[i+1] {
+			case FallbackStrategyNamedConfig, FallbackStrategyEmptyConfig:
+				continue
+			}
+		}
+		return fmt.Errorf("'%v' must be followed by '%v' or '%v'", FallbackStrategyProductConfig, FallbackStrategyNamedConfig, FallbackStrategyEmptyConfig)
+	}
 	return nil
 }
 
@@ -238,23 +474,55 @@ func start(c *cli.Context, f *Flags) error {
 		return fmt.Errorf("error building kubernetes clientset from config: %s", err)
 	}
 
+	procFS, err := procfs.NewDefaultFS()
+	if err != nil {
+		return fmt.Errorf("error opening procfs: %v", err)
+	}
+	finder := &processFinder{fs: procFS}
+	if f.PodName != "" && f.PodNamespace != "" {
+		finder.containerID = podContainerID(clientset, f.PodNamespace, f.PodName)
+	}
+
 	config := NewSyncableConfig(f)
+	facts := &NodeFacts{}
 
-	stop := continuouslySyncConfigChanges(clientset, config, f)
+	stop, nodeSynced := continuouslySyncConfigChanges(clientset, config, facts, f)
 	defer close(stop)
 
+	var source configSource = &directorySource{f: f}
+	synced := []cache.InformerSynced{nodeSynced}
+	if f.ConfigMapName != "" {
+		configMap := &configMapSource{f: f}
+		synced = append(synced, continuouslySyncConfigMapChanges(clientset, configMap, config, f, stop))
+		source = configMap
+	}
+	if !cache.WaitForCacheSync(stop, synced...) {
+		return fmt.Errorf("error waiting for informer caches to sync")
+	}
+
 	for {
 		klog.Infof("Waiting for change to '%s' label", f.NodeLabel)
 		config := config.Get()
 		klog.Infof("Label change detected: %s=%s", f.NodeLabel, config)
-		err := updateConfig(config, f)
-		if f.Oneshot || err != nil {
+		applied, err := updateConfig(config, source, facts, finder, f)
+		if err := reportState(c.Context, clientset, f, applied, err); err != nil {
+			klog.Warningf("Failed to report config state on node: %v", err)
+		}
+		if f.Oneshot {
+			return err
+		}
+		var rejected *rejectedConfigError
+		if errors.As(err, &rejected) {
+			klog.Errorf("Config not applied: %v", err)
+			continue
+		}
+		if err != nil {
 			return err
 		}
 	}
 }
 
-func continuouslySyncConfigChanges(clientset *kubernetes.Clientset, config *SyncableConfig, f *Flags) chan struct{} {
+func continuouslySyncConfigChanges(clientset *kubernetes.Clientset, config *SyncableConfig, facts *NodeFacts, f *Flags) (chan struct{}, cache.InformerSynced) {
 	listWatch := cache.NewListWatchFromClient(
 		clientset.CoreV1().RESTClient(),
 		ResourceNodes,
@@ -268,16 +536,24 @@ func continuouslySyncConfigChanges(clientset *kubernetes.Clientset, config *Sync
 			ObjectType:    &v1.Node{},
 			Handler: cache.ResourceEventHandlerFuncs{
 				AddFunc: func(obj interface{}) {
+					facts.SetProduct(getNodeProduct(obj.(*v1.Node).Labels, f))
 					config.Set(obj.(*v1.Node).Labels[f.NodeLabel])
 				},
 				UpdateFunc: func(oldObj, newObj interface{}) {
+					// The product only affects the config selected by the
+					// fallback strategies, so it is resynced on change.
+					productChanged := facts.SetProduct(getNodeProduct(newObj.(*v1.Node).Labels, f))
 					oldLabel := oldObj.(*v1.Node).Labels[f.NodeLabel]
 					newLabel := newObj.(*v1.Node).Labels[f.NodeLabel]
-					if oldLabel != newLabel {
+					switch {
+					case oldLabel != newLabel:
 						config.Set(newLabel)
+					case productChanged && newLabel == "":
+						config.Resync()
 					}
 				},
 				DeleteFunc: func(obj interface{}) {
+					facts.SetProduct("")
 					oldLabel := obj.(*v1.Node).Labels[f.NodeLabel]
 					if oldLabel != "" {
 						config.Set("")
@@ -290,13 +566,16 @@ func continuouslySyncConfigChanges(clientset *kubernetes.Clientset, config *Sync
 
 	stop := make(chan struct{})
 	go controller.Run(stop)
-	return stop
+	return stop, controller.HasSynced
 }
 
-func updateConfig(config string, f *Flags) error {
-	config, err := updateConfigName(config, f)
+// updateConfig updates the config to the config selected by the specified
+// label value and returns the name of this config. If the name cannot be
+// resolved, the label value is returned instead.
+func updateConfig(label string, source configSource, facts *NodeFacts, finder *processFinder, f *Flags) (string, error) {
+	config, err := updateConfigName(label, source, facts, f)
 	if err != nil {
-		return err
+		return label, err
 	}
 
 	if config == "" {
@@ -305,13 +584,18 @@ func updateConfig(config string, f *Flags) error {
 		klog.Infof("Updating to config: %s", config)
 	}
 
-	updated, err := updateSymlink(config, f)
+	err = validateConfig(config, source)
 	if err != nil {
-		return err
+		return config, &rejectedConfigError{fmt.Errorf("refusing to update to invalid config %v: %v", config, err)}
+	}
+
+	updated, restore, err := source.Install(config)
+	if err != nil {
+		return config, err
 	}
 	if !updated {
 		klog.Infof("Already configured. Skipping update...")
-		return nil
+		return config, nil
 	}
 
 	if config == "" {
@@ -320,25 +604,133 @@ func updateConfig(config string, f *Flags) error {
 		klog.Infof("Successfully updated to config: %s", config)
 	}
 
+	err = applyConfig(finder, f)
+	if err == nil {
+		return config, nil
+	}
+
+	klog.Errorf("Failed to apply config: %v", err)
+	klog.Infof("Restoring previous config")
+	if err := restore(); err != nil {
+		return config, fmt.Errorf("error restoring previous config: %v", err)
+	}
+	if err := applyConfig(finder, f); err != nil {
+		return config, fmt.Errorf("error applying previous config: %v", err)
+	}
+	klog.Infof("Successfully restored previous config")
+	return config, &rejectedConfigError{fmt.Errorf("failed to apply config %v: %v", config, err)}
+}
+
+// reportState reports the result of updating to the specified config through
+// annotations on the node. The applied config is only updated once a config
+// was applied successfully, which also removes the error annotation. If the
+// update failed, the applied config annotation keeps the config that was last
+// applied.
+func reportState(ctx context.Context, clientset kubernetes.Interface, f *Flags, config string, updateErr error) error {
+	if f.StateAnnotationPrefix == "" {
+		return nil
+	}
+
+	annotations := map[string]*string{
+		f.StateAnnotationPrefix + StateAnnotationRequested: &config,
+		f.StateAnnotationPrefix + StateAnnotationApplied:   &config,
+		f.StateAnnotationPrefix + StateAnnotationTimestamp: ptr.To(time.Now().UTC().Format(time.RFC3339)),
+		f.StateAnnotationPrefix + StateAnnotationState:     ptr.To(ConfigStateSuccess),
+		f.StateAnnotationPrefix + StateAnnotationError:     nil,
+	}
+	if updateErr != nil {
+		delete(annotations, f.StateAnnotationPrefix+StateAnnotationApplied)
+		annotations[f.StateAnnotationPrefix+StateAnnotationState] = ptr.To(ConfigStateFailed)
+		annotations[f.StateAnnotationPrefix+StateAnnotationError] = ptr.To(updateErr.Error())
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": annotations,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("error creating patch: %v", err)
+	}
+
+	_, err = clientset.CoreV1().Nodes().Patch(ctx, f.NodeName, types.MergePatchType, patch, metav1.PatchOptions{})
+	if err != nil {
+		return fmt.Errorf("error patching node %v: %v", f.NodeName, err)
+	}
+	return nil
+}
+
+// validateConfig checks that the specified config can be loaded. The empty
+// config is always valid.
+func validateConfig(config string, source configSource) error {
+	if config == "" {
+		return nil
+	}
+
+	contents, err := source.Read(config)
+	if err != nil {
+		return fmt.Errorf("error reading config: %v", err)
+	}
+
+	result, err := versioned.ValidateConfig(contents)
+	if err != nil {
+		return err
+	}
+	return result.Errors.ToAggregate()
+}
+
+// applyConfig signals the processes, if required, and waits for them to
+// report that they applied the current config if a status file is configured.
+// In oneshot mode the processes are not yet running, so their status is not
+// waited for. Processes that cannot be signaled are reported but are not an
+// error, since a process that is not running yet reads the config once it is
+// started.
+func applyConfig(finder *processFinder, f *Flags) error {
 	if f.SendSignal {
-		klog.Infof("Sending signal '%s' to '%s'", syscall.Signal(f.Signal), f.ProcessToSignal)
-		err := signalProcess(f)
+		targets, err := getSignalTargets(f)
 		if err != nil {
 			return err
 		}
-		klog.Infof("Successfully sent signal")
+		delivered := false
+		for _, result := range signalTargets(targets, finder) {
+			if result.err != nil {
+				klog.Warningf("Failed to send signal '%s' to %v: %v", result.target.signal, result.target, result.err)
+				continue
+			}
+			klog.Infof("Successfully sent signal '%s' to %v: pids %v", result.target.signal, result.target, result.pids)
+			delivered = true
+		}
+		if !delivered {
+			klog.Warningf("No process was signaled; the config is applied once the processes are started")
+			return nil
+		}
 	}
 
+	if f.StatusFile == "" || f.Oneshot {
+		return nil
+	}
+
+	klog.Infof("Waiting for '%s' to apply the config", f.ProcessToSignal)
+	ctx, cancel := context.WithTimeout(context.Background(), f.StatusTimeout)
+	defer cancel()
+	if err := status.Wait(ctx, f.StatusFile, f.ConfigFileDst, statusPollInterval); err != nil {
+		return err
+	}
+	klog.Infof("Config successfully applied")
 	return nil
 }
 
-func updateConfigName(config string, f *Flags) (string, error) {
+func updateConfigName(config string, source configSource, facts *NodeFacts, f *Flags) (string, error) {
 	// Get a lists of the available config file names
-	files, err := getConfigFileNameMap(f)
+	files, err := source.Names()
 	if err != nil {
 		return "", fmt.Errorf("error getting list of configuration files: %v", err)
 	}
 
+	// The mapping of products to configs is not a config itself.
+	hasProductConfigs := files[f.ProductConfigs]
+	delete(files, f.ProductConfigs)
+
 	if len(files) == 0 {
 		return "", fmt.Errorf("no configuration files available")
 	}
@@ -351,7 +743,7 @@ func updateConfigName(config string, f *Flags) (string, error) {
 	// If an explicit config was passed in, check to see if it is available.
 	if config != "" {
 		if !files[config] {
-			return "", fmt.Errorf("specified config %v does not exist", config)
+			return "", &rejectedConfigError{fmt.Errorf("specified config %v does not exist", config)}
 		}
 		return config, nil
 	}
@@ -360,7 +752,7 @@ func updateConfigName(config string, f *Flags) (string, error) {
 	if f.DefaultConfig != "" {
 		klog.Infof("No value set. Selecting default name: %v", f.DefaultConfig)
 		if !files[f.DefaultConfig] {
-			return "", fmt.Errorf("specified config %v does not exist", config)
+			return "", &rejectedConfigError{fmt.Errorf("specified config %v does not exist", f.DefaultConfig)}
 		}
 		return f.DefaultConfig, nil
 	}
@@ -381,6 +773,30 @@ func updateConfigName(config string, f *Flags) (string, error) {
 				return filenames[0], nil
 			}
 			klog.Infof("More than one configuration was found: %v", filenames)
+		case FallbackStrategyProductConfig:
+			klog.Infof("Attempting to find config for GPU product...")
+			if !hasProductConfigs {
+				klog.Infof("No product mapping named '%v' was found", f.ProductConfigs)
+				continue
+			}
+			product := facts.Product()
+			if product == "" {
+				klog.Infof("No GPU product set in '%v' label", f.ProductLabel)
+				continue
+			}
+			config, err := getProductConfig(product, source, f)
+			if err != nil {
+				return "", &rejectedConfigError{err}
+			}
+			if config == "" {
+				klog.Infof("No configuration mapped to GPU product '%v'", product)
+				continue
+			}
+			klog.Infof("Selecting config %v for GPU product '%v'", config, product)
+			if !files[config] {
+				return "", &rejectedConfigError{fmt.Errorf("config %v mapped to GPU product %v does not exist", config, product)}
+			}
+			return config, nil
 		case FallbackStrategyEmptyConfig:
 			klog.Infof("Falling back to an empty configuration")
 			return "", nil
@@ -389,7 +805,34 @@ func updateConfigName(config string, f *Flags) (string, error) {
 		}
 	}
 
-	return "", fmt.Errorf("no config was set, no default was provided, and all fallbacks failed")
+	return "", &rejectedConfigError{fmt.Errorf("no config was set, no default was provided, and all fallbacks failed")}
+}
+
+// getProductConfig returns the name of the config that the mapping in
+// <product-configs> selects for the specified product. The patterns of the
+// mapping are matched in order. If no pattern matches, an empty string is
+// returned.
+func getProductConfig(product string, source configSource, f *Flags) (string, error) {
+	contents, err := source.Read(f.ProductConfigs)
+	if err != nil {
+		return "", fmt.Errorf("error reading product mapping: %v", err)
+	}
+
+	var mapping []ProductConfig
+	if err := yaml.UnmarshalStrict(contents, &mapping); err != nil {
+		return "", fmt.Errorf("error parsing product mapping: %v", err)
+	}
+
+	for _, m := range mapping {
+		matched, err := path.Match(m.Product, product)
+		if err != nil {
+			return "", fmt.Errorf("invalid product pattern %q: %v", m.Product, err)
+		}
+		if matched {
+			return m.Config, nil
+		}
+	}
+	return "", nil
 }
 
 func updateSymlink(config string, f *Flags) (bool, error) {
@@ -431,36 +874,35 @@ func updateSymlink(config string, f *Flags) (bool, error) {
 	return true, nil
 }
 
-func signalProcess(f *Flags) error {
-	pid, err := findPidToSignal(f)
-	if err != nil {
-		return fmt.Errorf("error finding pid: %v", err)
+// readSymlink returns the target of the destination config file or an empty
+// string if it does not exist.
+func readSymlink(dst string) (string, error) {
+	target, err := os.Readlink(dst)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
 	}
-	err = syscall.Kill(pid, syscall.Signal(f.Signal))
 	if err != nil {
-		return fmt.Errorf("error sending signal: %v", err)
+		return "", fmt.Errorf("error reading symlink '%s': %v", dst, err)
 	}
-	return nil
+	return target, nil
 }
 
-func findPidToSignal(f *Flags) (int, error) {
-	procs, err := procfs.AllProcs()
-	if err != nil {
-		return -1, fmt.Errorf("error getting list of all procs: %v", err)
+// restoreSymlink points the destination config file to the specified
+// previous target again. If there was no previous target, the destination
+// config file is removed.
+func restoreSymlink(previous string, f *Flags) error {
+	err := os.Remove(f.ConfigFileDst)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("error removing config: %v", err)
 	}
-	for _, p := range procs {
-		cmdline, err := p.CmdLine()
-		if err != nil {
-			return -1, fmt.Errorf("error getting cmdline: %v", err)
-		}
-		if len(cmdline) == 0 {
-			continue
-		}
-		if cmdline[0] == f.ProcessToSignal {
-			return p.PID, nil
-		}
+	if previous == "" {
+		return nil
 	}
-	return -1, fmt.Errorf("no process found")
+	err = os.Symlink(previous, f.ConfigFileDst)
+	if err != nil {
+		return fmt.Errorf("error creating symlink: %v", err)
+	}
+	return nil
 }
 
 func fileExists(filename string) (bool, error) {
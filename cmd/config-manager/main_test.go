@@ -0,0 +1,470 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	cli "github.com/urfave/cli/v2"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/status"
+)
+
+const (
+	validConfig = `
+version: v1
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 2
+`
+	invalidConfig = `
+version: v1
+sharing:
+  timeSlicing:
+    resources:
+    - name: nvidia.com/gpu
+      replicas: 1
+`
+)
+
+func TestUpdateConfig(t *testing.T) {
+	statusPollInterval = 10 * time.Millisecond
+
+	testCases := []struct {
+		description    string
+		config         string
+		failingConfigs []string
+		expectedError  bool
+		expectedConfig string
+	}{
+		{
+			description:    "valid config is applied",
+			config:         "valid",
+			expectedConfig: "valid",
+		},
+		{
+			description:    "invalid config is refused",
+			config:         "invalid",
+			expectedError:  true,
+			expectedConfig: "previous",
+		},
+		{
+			description:    "config that is not applied is rolled back",
+			config:         "valid",
+			failingConfigs: []string{"valid"},
+			expectedError:  true,
+			expectedConfig: "previous",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			srcdir := t.TempDir()
+			dstdir := t.TempDir()
+			for name, contents := range map[string]string{
+				"previous": validConfig,
+				"valid":    validConfig + "      rename: shared-gpu\n      devices: all\n",
+				"invalid":  invalidConfig,
+			} {
+				require.NoError(t, os.WriteFile(filepath.Join(srcdir, name), []byte(contents), 0600))
+			}
+
+			f := &Flags{
+				ConfigFileSrcdir: srcdir,
+				ConfigFileDst:    filepath.Join(dstdir, "config.yaml"),
+				StatusFile:       filepath.Join(dstdir, "status.json"),
+				StatusTimeout:    time.Second,
+			}
+			require.NoError(t, os.Symlink(filepath.Join(srcdir, "previous"), f.ConfigFileDst))
+
+			stop := make(chan struct{})
+			defer close(stop)
+			go fakeProcess(stop, f, srcdir, tc.failingConfigs...)
+
+			applied, err := updateConfig(tc.config, &directorySource{f: f}, &NodeFacts{}, nil, f)
+			require.Equal(t, tc.config, applied)
+			if tc.expectedError {
+				// The previous config is still in place, so the config
+				// manager keeps syncing.
+				var rejected *rejectedConfigError
+				require.ErrorAs(t, err, &rejected)
+			} else {
+				require.NoError(t, err)
+			}
+
+			target, err := os.Readlink(f.ConfigFileDst)
+			require.NoError(t, err)
+			require.Equal(t, filepath.Join(srcdir, tc.expectedConfig), target)
+		})
+	}
+}
+
+// fakeProcess reports the status of the config the destination config file
+// points to until it is stopped. The specified configs fail to be applied.
+func fakeProcess(stop <-chan struct{}, f *Flags, srcdir string, failingConfigs ...string) {
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(5 * time.Millisecond):
+		}
+
+		target, err := os.Readlink(f.ConfigFileDst)
+		if err != nil {
+			continue
+		}
+		var applyErr error
+		for _, config := range failingConfigs {
+			if target == filepath.Join(srcdir, config) {
+				applyErr = errors.New("unable to load config")
+			}
+		}
+		hash, err := status.Hash(f.ConfigFileDst)
+		if err != nil {
+			continue
+		}
+		_ = status.Write(f.StatusFile, hash, applyErr)
+	}
+}
+
+func TestUpdateConfigFromConfigMap(t *testing.T) {
+	dstdir := t.TempDir()
+	f := &Flags{
+		ConfigFileDst:      filepath.Join(dstdir, "config.yaml"),
+		ConfigMapName:      "configs",
+		ConfigMapNamespace: "default",
+		FallbackStrategies: *cli.NewStringSlice(FallbackStrategyNamedConfig),
+	}
+	source := &configMapSource{f: f}
+
+	_, err := updateConfig("valid", source, &NodeFacts{}, nil, f)
+	require.EqualError(t, err, "error getting list of configuration files: ConfigMap default/configs not found")
+
+	source.set(map[string]string{
+		"default": validConfig,
+		"valid":   validConfig + "      rename: shared-gpu\n",
+		"invalid": invalidConfig,
+	})
+
+	applied, err := updateConfig("", source, &NodeFacts{}, nil, f)
+	require.NoError(t, err)
+	require.Equal(t, "default", applied)
+	contents, err := os.ReadFile(f.ConfigFileDst)
+	require.NoError(t, err)
+	require.Equal(t, validConfig, string(contents))
+
+	_, err = updateConfig("invalid", source, &NodeFacts{}, nil, f)
+	var rejected *rejectedConfigError
+	require.ErrorAs(t, err, &rejected)
+	contents, err = os.ReadFile(f.ConfigFileDst)
+	require.NoError(t, err)
+	require.Equal(t, validConfig, string(contents))
+
+	// A change to the contents of the selected config is written to the
+	// destination config file.
+	source.set(map[string]string{
+		"default": validConfig + "      rename: shared-gpu\n",
+	})
+	updated, _, err := source.Install("default")
+	require.NoError(t, err)
+	require.True(t, updated)
+	contents, err = os.ReadFile(f.ConfigFileDst)
+	require.NoError(t, err)
+	require.Equal(t, validConfig+"      rename: shared-gpu\n", string(contents))
+
+	updated, _, err = source.Install("default")
+	require.NoError(t, err)
+	require.False(t, updated)
+
+	updated, restore, err := source.Install("")
+	require.NoError(t, err)
+	require.True(t, updated)
+	contents, err = os.ReadFile(f.ConfigFileDst)
+	require.NoError(t, err)
+	require.Empty(t, contents)
+
+	require.NoError(t, restore())
+	contents, err = os.ReadFile(f.ConfigFileDst)
+	require.NoError(t, err)
+	require.Equal(t, validConfig+"      rename: shared-gpu\n", string(contents))
+}
+
+func TestUpdateConfigNameByProduct(t *testing.T) {
+	productConfigs := `
+- product: "NVIDIA-A100-*"
+  config: a100
+- product: "Tesla-T4"
+  config: missing
+- product: "NVIDIA-H100-*"
+  config: ""
+`
+	testCases := []struct {
+		description    string
+		product        string
+		productConfigs string
+		expectedError  bool
+		expectedConfig string
+	}{
+		{
+			description:    "product matches pattern",
+			product:        "NVIDIA-A100-SXM4-40GB",
+			productConfigs: productConfigs,
+			expectedConfig: "a100",
+		},
+		{
+			description:    "unmatched product falls back to next strategy",
+			product:        "NVIDIA-L4",
+			productConfigs: productConfigs,
+			expectedConfig: "default",
+		},
+		{
+			description:    "product mapped to empty config falls back to next strategy",
+			product:        "NVIDIA-H100-80GB-HBM3",
+			productConfigs: productConfigs,
+			expectedConfig: "default",
+		},
+		{
+			description:    "no product falls back to next strategy",
+			productConfigs: productConfigs,
+			expectedConfig: "default",
+		},
+		{
+			description:    "no product mapping falls back to next strategy",
+			product:        "NVIDIA-A100-SXM4-40GB",
+			expectedConfig: "default",
+		},
+		{
+			description:    "product mapped to missing config",
+			product:        "Tesla-T4",
+			productConfigs: productConfigs,
+			expectedError:  true,
+		},
+		{
+			description:    "invalid product mapping",
+			product:        "Tesla-T4",
+			productConfigs: "product: Tesla-T4",
+			expectedError:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			f := &Flags{
+				ProductConfigs:     DefaultProductConfigs,
+				FallbackStrategies: *cli.NewStringSlice(FallbackStrategyProductConfig, FallbackStrategyNamedConfig),
+			}
+			data := map[string]string{
+				"default": validConfig,
+				"a100":    validConfig,
+			}
+			if tc.productConfigs != "" {
+				data[DefaultProductConfigs] = tc.productConfigs
+			}
+			source := &configMapSource{f: f, data: data}
+			facts := &NodeFacts{}
+			facts.SetProduct(tc.product)
+
+			config, err := updateConfigName("", source, facts, f)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedConfig, config)
+		})
+	}
+}
+
+func TestValidateFallbackStrategies(t *testing.T) {
+	testCases := []struct {
+		strategies    []string
+		expectedError bool
+	}{
+		{strategies: []string{"named", "single"}},
+		{strategies: []string{"product", "named"}},
+		{strategies: []string{"single", "product", "empty"}},
+		{strategies: []string{"product"}, expectedError: true},
+		{strategies: []string{"product", "single"}, expectedError: true},
+	}
+
+	for _, tc := range testCases {
+		err := validateFallbackStrategies(tc.strategies)
+		if tc.expectedError {
+			require.Error(t, err, "%v", tc.strategies)
+		} else {
+			require.NoError(t, err, "%v", tc.strategies)
+		}
+	}
+}
+
+func TestProductConfigsIsNotAConfig(t *testing.T) {
+	f := &Flags{
+		ProductConfigs:     DefaultProductConfigs,
+		FallbackStrategies: *cli.NewStringSlice(FallbackStrategySingleConfig),
+	}
+	source := &configMapSource{f: f, data: map[string]string{
+		"default":             validConfig,
+		DefaultProductConfigs: "[]",
+	}}
+
+	config, err := updateConfigName("", source, &NodeFacts{}, f)
+	require.NoError(t, err)
+	require.Equal(t, "default", config)
+
+	_, err = updateConfigName(DefaultProductConfigs, source, &NodeFacts{}, f)
+	require.Error(t, err)
+}
+
+func TestGetNodeProduct(t *testing.T) {
+	testCases := []struct {
+		description     string
+		productLabel    string
+		labels          map[string]string
+		expectedProduct string
+	}{
+		{
+			description:     "product label",
+			labels:          map[string]string{"nvidia.com/gpu.product": "Tesla-T4"},
+			expectedProduct: "Tesla-T4",
+		},
+		{
+			description:     "shared suffix is removed",
+			labels:          map[string]string{"nvidia.com/gpu.product": "Tesla-T4-SHARED"},
+			expectedProduct: "Tesla-T4",
+		},
+		{
+			description: "product label of a custom resource",
+			labels: map[string]string{
+				"nvidia.com/mig-1g.5gb.product": "NVIDIA-A100-SXM4-40GB-MIG-1g.5gb",
+				"nvidia.com/t4.product":         "Tesla-T4-SHARED",
+			},
+			expectedProduct: "Tesla-T4",
+		},
+		{
+			description:  "custom product label is not replaced",
+			productLabel: "example.com/product",
+			labels: map[string]string{
+				"nvidia.com/gpu.product": "Tesla-T4",
+			},
+			expectedProduct: "",
+		},
+		{
+			description: "no product label",
+			labels: map[string]string{
+				"nvidia.com/gpu.count": "1",
+			},
+			expectedProduct: "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			f := &Flags{ProductLabel: DefaultProductLabel}
+			if tc.productLabel != "" {
+				f.ProductLabel = tc.productLabel
+			}
+			require.Equal(t, tc.expectedProduct, getNodeProduct(tc.labels, f))
+		})
+	}
+}
+
+func TestSyncableConfigResync(t *testing.T) {
+	config := NewSyncableConfig(&Flags{})
+	config.Set("foo")
+	require.Equal(t, "foo", config.Get())
+
+	got := make(chan string)
+	go func() {
+		got <- config.Get()
+	}()
+	config.Resync()
+	select {
+	case value := <-got:
+		require.Equal(t, "foo", value)
+	case <-time.After(time.Second):
+		t.Fatal("Get() was not unblocked by Resync()")
+	}
+}
+
+func TestReportState(t *testing.T) {
+	f := &Flags{
+		NodeName:              "node",
+		StateAnnotationPrefix: "nvidia.com/device-plugin.config.",
+	}
+	clientset := fake.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "node",
+			Annotations: map[string]string{
+				"other": "value",
+			},
+		},
+	})
+
+	getAnnotations := func() map[string]string {
+		node, err := clientset.CoreV1().Nodes().Get(context.Background(), "node", metav1.GetOptions{})
+		require.NoError(t, err)
+		annotations := node.Annotations
+		require.NotEmpty(t, annotations["nvidia.com/device-plugin.config.timestamp"])
+		delete(annotations, "nvidia.com/device-plugin.config.timestamp")
+		return annotations
+	}
+
+	err := reportState(context.Background(), clientset, f, "foo", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"other": "value",
+		"nvidia.com/device-plugin.config.requested": "foo",
+		"nvidia.com/device-plugin.config.applied":   "foo",
+		"nvidia.com/device-plugin.config.state":     "success",
+	}, getAnnotations())
+
+	// A refused config does not replace the config that was last applied.
+	err = reportState(context.Background(), clientset, f, "bar", errors.New("specified config bar does not exist"))
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"other": "value",
+		"nvidia.com/device-plugin.config.requested": "bar",
+		"nvidia.com/device-plugin.config.applied":   "foo",
+		"nvidia.com/device-plugin.config.state":     "failed",
+		"nvidia.com/device-plugin.config.error":     "specified config bar does not exist",
+	}, getAnnotations())
+
+	err = reportState(context.Background(), clientset, f, "bar", nil)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{
+		"other": "value",
+		"nvidia.com/device-plugin.config.requested": "bar",
+		"nvidia.com/device-plugin.config.applied":   "bar",
+		"nvidia.com/device-plugin.config.state":     "success",
+	}, getAnnotations())
+
+	f.StateAnnotationPrefix = ""
+	clientset.ClearActions()
+	require.NoError(t, reportState(context.Background(), clientset, f, "baz", nil))
+	require.Empty(t, clientset.Actions())
+}
@@ -0,0 +1,304 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/prometheus/procfs"
+	"golang.org/x/sys/unix"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// These constants represent the kinds of processes that can be signaled.
+const (
+	// SignalTargetProcess matches the first process whose argv[0] equals the
+	// value. This is how <process-to-signal> is matched.
+	SignalTargetProcess = "process"
+	// SignalTargetPidFile matches the process whose pid is stored in the file
+	// at the path given as value.
+	SignalTargetPidFile = "pidfile"
+	// SignalTargetCmdline matches all processes whose full command line,
+	// joined by spaces, matches the regular expression given as value.
+	SignalTargetCmdline = "cmdline"
+	// SignalTargetContainer matches the main process of the container with
+	// the given name in the pod of the config manager. This requires the pod
+	// to share its process namespace.
+	SignalTargetContainer = "container"
+)
+
+// signalTarget describes a set of processes to signal and the signal to send.
+type signalTarget struct {
+	kind    string
+	value   string
+	signal  syscall.Signal
+	cmdline *regexp.Regexp
+}
+
+// signalResult is the result of signaling a target.
+type signalResult struct {
+	target *signalTarget
+	pids   []int
+	err    error
+}
+
+// processFinder finds the processes matched by a signal target.
+type processFinder struct {
+	fs procfs.FS
+	// containerID returns the ID of the container with the specified name.
+	containerID func(name string) (string, error)
+}
+
+// parseSignalTarget parses a target of the form <kind>:<signal>:<value>. The
+// signal is either a name such as SIGHUP or HUP or a number. If it is empty,
+// the specified default signal is used.
+func parseSignalTarget(spec string, defaultSignal syscall.Signal) (*signalTarget, error) {
+	parts := strings.SplitN(spec, ":", 3)
+	if len(parts) != 3 || parts[2] == "" {
+		return nil, fmt.Errorf("invalid signal target %q: must be of the form <kind>:<signal>:<value>", spec)
+	}
+
+	t := &signalTarget{
+		kind:   parts[0],
+		value:  parts[2],
+		signal: defaultSignal,
+	}
+	switch t.kind {
+	case SignalTargetProcess, SignalTargetPidFile, SignalTargetContainer:
+	case SignalTargetCmdline:
+		cmdline, err := regexp.Compile(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid signal target %q: %v", spec, err)
+		}
+		t.cmdline = cmdline
+	default:
+		return nil, fmt.Errorf("invalid signal target %q: unknown kind %q", spec, t.kind)
+	}
+
+	if parts[1] != "" {
+		signal, err := parseSignal(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid signal target %q: %v", spec, err)
+		}
+		t.signal = signal
+	}
+	return t, nil
+}
+
+// parseSignal parses a signal name, with or without the SIG prefix, or number.
+func parseSignal(s string) (syscall.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return syscall.Signal(n), nil
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	if signal := unix.SignalNum(name); signal != 0 {
+		return signal, nil
+	}
+	return 0, fmt.Errorf("unknown signal %q", s)
+}
+
+func (t *signalTarget) String() string {
+	return fmt.Sprintf("%s %q", t.kind, t.value)
+}
+
+// getSignalTargets returns the targets to signal. If no <signal-target> is
+// set, <process-to-signal> is signaled with <signal>.
+func getSignalTargets(f *Flags) ([]*signalTarget, error) {
+	if len(f.SignalTargets.Value()) == 0 {
+		return []*signalTarget{
+			{
+				kind:   SignalTargetProcess,
+				value:  f.ProcessToSignal,
+				signal: syscall.Signal(f.Signal),
+			},
+		}, nil
+	}
+
+	var targets []*signalTarget
+	for _, spec := range f.SignalTargets.Value() {
+		t, err := parseSignalTarget(spec, syscall.Signal(f.Signal))
+		if err != nil {
+			return nil, err
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// signalTargets sends the signal of each target to the processes it matches.
+// Each target is signaled independently, so a target whose processes cannot
+// be found or signaled does not prevent others from being signaled.
+func signalTargets(targets []*signalTarget, finder *processFinder) []signalResult {
+	var results []signalResult
+	for _, t := range targets {
+		result := signalResult{target: t}
+		result.pids, result.err = finder.find(t)
+		if result.err == nil && len(result.pids) == 0 {
+			result.err = fmt.Errorf("no process found")
+		}
+		for _, pid := range result.pids {
+			if err := syscall.Kill(pid, t.signal); err != nil {
+				result.err = fmt.Errorf("error sending signal to pid %d: %v", pid, err)
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// find returns the pids of the processes matched by the specified target.
+func (p *processFinder) find(t *signalTarget) ([]int, error) {
+	switch t.kind {
+	case SignalTargetPidFile:
+		return p.findByPidFile(t.value)
+	case SignalTargetContainer:
+		return p.findByContainer(t.value)
+	}
+
+	procs, err := p.fs.AllProcs()
+	if err != nil {
+		return nil, fmt.Errorf("error getting list of all procs: %v", err)
+	}
+
+	var pids []int
+	for _, proc := range procs {
+		// The command line of the config manager includes its targets, so
+		// it could otherwise match itself.
+		if proc.PID == os.Getpid() {
+			continue
+		}
+		cmdline, err := proc.CmdLine()
+		if err != nil {
+			// The process may have exited in the meantime.
+			continue
+		}
+		if len(cmdline) == 0 {
+			continue
+		}
+		switch t.kind {
+		case SignalTargetProcess:
+			if cmdline[0] == t.value {
+				return []int{proc.PID}, nil
+			}
+		case SignalTargetCmdline:
+			if t.cmdline.MatchString(strings.Join(cmdline, " ")) {
+				pids = append(pids, proc.PID)
+			}
+		}
+	}
+	return pids, nil
+}
+
+func (p *processFinder) findByPidFile(pidFile string) ([]int, error) {
+	contents, err := os.ReadFile(pidFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading pid file: %v", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid pid file %v: %v", pidFile, err)
+	}
+	if _, err := p.fs.Proc(pid); err != nil {
+		return nil, fmt.Errorf("process %d from pid file %v not found: %v", pid, pidFile, err)
+	}
+	return []int{pid}, nil
+}
+
+// findByContainer returns the main process of the named container. This is
+// the process in the cgroup of the container whose parent is not.
+func (p *processFinder) findByContainer(name string) ([]int, error) {
+	if p.containerID == nil {
+		return nil, fmt.Errorf("containers cannot be signaled without <pod-name> and <pod-namespace>")
+	}
+	id, err := p.containerID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	procs, err := p.fs.AllProcs()
+	if err != nil {
+		return nil, fmt.Errorf("error getting list of all procs: %v", err)
+	}
+
+	parents := make(map[int]int)
+	for _, proc := range procs {
+		cgroups, err := proc.Cgroups()
+		if err != nil {
+			continue
+		}
+		if !inContainer(cgroups, id) {
+			continue
+		}
+		stat, err := proc.Stat()
+		if err != nil {
+			continue
+		}
+		parents[proc.PID] = stat.PPID
+	}
+
+	var pids []int
+	for pid, ppid := range parents {
+		if _, exists := parents[ppid]; !exists {
+			pids = append(pids, pid)
+		}
+	}
+	return pids, nil
+}
+
+func inContainer(cgroups []procfs.Cgroup, id string) bool {
+	for _, cgroup := range cgroups {
+		if strings.Contains(cgroup.Path, id) {
+			return true
+		}
+	}
+	return false
+}
+
+// podContainerID returns a function that looks up the ID of a container in
+// the status of the specified pod. The pod is read on each lookup, since the
+// ID changes whenever the container is restarted.
+func podContainerID(clientset kubernetes.Interface, namespace string, name string) func(string) (string, error) {
+	return func(container string) (string, error) {
+		pod, err := clientset.CoreV1().Pods(namespace).Get(context.Background(), name, metav1.GetOptions{})
+		if err != nil {
+			return "", fmt.Errorf("error getting pod %s/%s: %v", namespace, name, err)
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name != container {
+				continue
+			}
+			// The container ID is of the form <runtime>://<id>.
+			_, id, found := strings.Cut(status.ContainerID, "://")
+			if !found || id == "" {
+				return "", fmt.Errorf("container %v is not running", container)
+			}
+			return id, nil
+		}
+		return "", fmt.Errorf("container %v not found in pod %s/%s", container, namespace, name)
+	}
+}
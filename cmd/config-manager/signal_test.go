@@ -0,0 +1,235 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/prometheus/procfs"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSignalTarget(t *testing.T) {
+	testCases := []struct {
+		spec           string
+		expectedError  bool
+		expectedKind   string
+		expectedValue  string
+		expectedSignal syscall.Signal
+	}{
+		{
+			spec:           "process::nvidia-device-plugin",
+			expectedKind:   SignalTargetProcess,
+			expectedValue:  "nvidia-device-plugin",
+			expectedSignal: syscall.SIGHUP,
+		},
+		{
+			spec:           "cmdline:SIGUSR1:^/usr/bin/gpu-feature-discovery( |$)",
+			expectedKind:   SignalTargetCmdline,
+			expectedValue:  "^/usr/bin/gpu-feature-discovery( |$)",
+			expectedSignal: syscall.SIGUSR1,
+		},
+		{
+			spec:           "pidfile:term:/run/mps.pid",
+			expectedKind:   SignalTargetPidFile,
+			expectedValue:  "/run/mps.pid",
+			expectedSignal: syscall.SIGTERM,
+		},
+		{
+			spec:           "container:10:gpu-feature-discovery",
+			expectedKind:   SignalTargetContainer,
+			expectedValue:  "gpu-feature-discovery",
+			expectedSignal: syscall.Signal(10),
+		},
+		{
+			spec:          "nvidia-device-plugin",
+			expectedError: true,
+		},
+		{
+			spec:          "process::",
+			expectedError: true,
+		},
+		{
+			spec:          "unknown::value",
+			expectedError: true,
+		},
+		{
+			spec:          "process:SIGFOO:nvidia-device-plugin",
+			expectedError: true,
+		},
+		{
+			spec:          "cmdline::(",
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			target, err := parseSignalTarget(tc.spec, syscall.SIGHUP)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedKind, target.kind)
+			require.Equal(t, tc.expectedValue, target.value)
+			require.Equal(t, tc.expectedSignal, target.signal)
+		})
+	}
+}
+
+type fakeProc struct {
+	ppid    int
+	cmdline []string
+	cgroup  string
+}
+
+// newFakeProcFS creates a procfs with the specified processes.
+func newFakeProcFS(t *testing.T, procs map[int]fakeProc) procfs.FS {
+	root := t.TempDir()
+	for pid, p := range procs {
+		dir := filepath.Join(root, strconv.Itoa(pid))
+		require.NoError(t, os.Mkdir(dir, 0755))
+
+		cmdline := strings.Join(p.cmdline, "\x00") + "\x00"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cmdline"), []byte(cmdline), 0644))
+
+		cgroup := "0::" + p.cgroup + "\n"
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "cgroup"), []byte(cgroup), 0644))
+
+		stat := fmt.Sprintf("%d (%s) S %d%s\n", pid, filepath.Base(p.cmdline[0]), p.ppid, strings.Repeat(" 0", 50))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "stat"), []byte(stat), 0644))
+	}
+	fs, err := procfs.NewFS(root)
+	require.NoError(t, err)
+	return fs
+}
+
+func TestProcessFinder(t *testing.T) {
+	const podCgroup = "/kubepods/besteffort/pod1234"
+	fs := newFakeProcFS(t, map[int]fakeProc{
+		1:  {cmdline: []string{"/pause"}, cgroup: podCgroup + "/aaaa"},
+		10: {ppid: 0, cmdline: []string{"/bin/sh", "-c", "/usr/bin/gpu-feature-discovery"}, cgroup: podCgroup + "/bbbb"},
+		11: {ppid: 10, cmdline: []string{"/usr/bin/gpu-feature-discovery", "--oneshot=false"}, cgroup: podCgroup + "/bbbb"},
+		20: {ppid: 0, cmdline: []string{"nvidia-device-plugin"}, cgroup: podCgroup + "/cccc"},
+		21: {ppid: 0, cmdline: []string{"mps-control-daemon"}, cgroup: podCgroup + "/dddd"},
+	})
+
+	pidFile := filepath.Join(t.TempDir(), "mps.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte("21\n"), 0644))
+	stalePidFile := filepath.Join(t.TempDir(), "stale.pid")
+	require.NoError(t, os.WriteFile(stalePidFile, []byte("99\n"), 0644))
+
+	finder := &processFinder{
+		fs: fs,
+		containerID: func(name string) (string, error) {
+			containers := map[string]string{
+				"gpu-feature-discovery": "bbbb",
+				"nvidia-device-plugin":  "cccc",
+			}
+			id, exists := containers[name]
+			if !exists {
+				return "", fmt.Errorf("container %v not found", name)
+			}
+			return id, nil
+		},
+	}
+
+	testCases := []struct {
+		spec          string
+		expectedPids  []int
+		expectedError bool
+	}{
+		{
+			spec:         "process::nvidia-device-plugin",
+			expectedPids: []int{20},
+		},
+		{
+			spec:         "process::gpu-feature-discovery",
+			expectedPids: nil,
+		},
+		{
+			spec:         "cmdline::gpu-feature-discovery",
+			expectedPids: []int{10, 11},
+		},
+		{
+			spec:         "cmdline::^/usr/bin/gpu-feature-discovery( |$)",
+			expectedPids: []int{11},
+		},
+		{
+			spec:         "container::gpu-feature-discovery",
+			expectedPids: []int{10},
+		},
+		{
+			spec:          "container::missing",
+			expectedError: true,
+		},
+		{
+			spec:         "pidfile::" + pidFile,
+			expectedPids: []int{21},
+		},
+		{
+			spec:          "pidfile::" + stalePidFile,
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.spec, func(t *testing.T) {
+			target, err := parseSignalTarget(tc.spec, syscall.SIGHUP)
+			require.NoError(t, err)
+
+			pids, err := finder.find(target)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			slices.Sort(pids)
+			require.Equal(t, tc.expectedPids, pids)
+		})
+	}
+}
+
+func TestSignalTargetsReportsEachTarget(t *testing.T) {
+	pid := os.Getpid()
+	fs := newFakeProcFS(t, map[int]fakeProc{
+		pid: {cmdline: []string{"config-manager"}},
+	})
+	pidFile := filepath.Join(t.TempDir(), "self.pid")
+	require.NoError(t, os.WriteFile(pidFile, []byte(strconv.Itoa(pid)), 0644))
+
+	var targets []*signalTarget
+	for _, spec := range []string{"process:0:missing", "pidfile:0:" + pidFile} {
+		target, err := parseSignalTarget(spec, syscall.SIGHUP)
+		require.NoError(t, err)
+		targets = append(targets, target)
+	}
+
+	results := signalTargets(targets, &processFinder{fs: fs})
+	require.Len(t, results, 2)
+	require.EqualError(t, results[0].err, "no process found")
+	require.NoError(t, results[1].err)
+	require.Equal(t, []int{pid}, results[1].pids)
+}
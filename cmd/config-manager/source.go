@@ -0,0 +1,224 @@
+/*
+ * Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ResourceConfigMaps represents the name the K8s resource 'configmaps'
+	ResourceConfigMaps = "configmaps"
+)
+
+// configSource provides the available configs and installs the selected
+// config as the destination config file.
+type configSource interface {
+	// Names returns the names of the available configs.
+	Names() (map[string]bool, error)
+	// Read returns the contents of the named config.
+	Read(config string) ([]byte, error)
+	// Install makes the named config the destination config file. An empty
+	// name installs an empty config. It returns whether the destination
+	// config file changed and a function that restores the previous one.
+	Install(config string) (bool, func() error, error)
+}
+
+// directorySource provides the configs in <config-file-srcdir>. These are
+// installed by pointing the destination config file to them.
+type directorySource struct {
+	f *Flags
+}
+
+var _ configSource = (*directorySource)(nil)
+
+func (s *directorySource) Names() (map[string]bool, error) {
+	return getConfigFileNameMap(s.f)
+}
+
+func (s *directorySource) Read(config string) ([]byte, error) {
+	return os.ReadFile(filepath.Join(s.f.ConfigFileSrcdir, config))
+}
+
+func (s *directorySource) Install(config string) (bool, func() error, error) {
+	previous, err := readSymlink(s.f.ConfigFileDst)
+	if err != nil {
+		return false, nil, err
+	}
+
+	updated, err := updateSymlink(config, s.f)
+	if err != nil {
+		return false, nil, err
+	}
+
+	restore := func() error {
+		return restoreSymlink(previous, s.f)
+	}
+	return updated, restore, nil
+}
+
+// configMapSource provides the configs stored under the keys of a ConfigMap
+// that is watched through an informer. A config is installed by writing its
+// contents to the destination config file, so changes to the ConfigMap take
+// effect without waiting for the kubelet to update a mounted volume.
+type configMapSource struct {
+	sync.Mutex
+	f    *Flags
+	data map[string]string
+}
+
+var _ configSource = (*configMapSource)(nil)
+
+func (s *configMapSource) set(data map[string]string) {
+	s.Lock()
+	defer s.Unlock()
+	s.data = data
+}
+
+func (s *configMapSource) Names() (map[string]bool, error) {
+	s.Lock()
+	defer s.Unlock()
+	if s.data == nil {
+		return nil, fmt.Errorf("ConfigMap %s/%s not found", s.f.ConfigMapNamespace, s.f.ConfigMapName)
+	}
+	names := make(map[string]bool)
+	for name := range s.data {
+		names[name] = true
+	}
+	return names, nil
+}
+
+func (s *configMapSource) Read(config string) ([]byte, error) {
+	s.Lock()
+	defer s.Unlock()
+	contents, exists := s.data[config]
+	if !exists {
+		return nil, fmt.Errorf("key %v not found in ConfigMap %s/%s", config, s.f.ConfigMapNamespace, s.f.ConfigMapName)
+	}
+	return []byte(contents), nil
+}
+
+func (s *configMapSource) Install(config string) (bool, func() error, error) {
+	var contents []byte
+	if config != "" {
+		var err error
+		contents, err = s.Read(config)
+		if err != nil {
+			return false, nil, err
+		}
+	}
+
+	previous, err := os.ReadFile(s.f.ConfigFileDst)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, nil, fmt.Errorf("error reading existing config: %v", err)
+	}
+	existed := err == nil
+
+	restore := func() error {
+		if !existed {
+			err := os.Remove(s.f.ConfigFileDst)
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("error removing config: %v", err)
+			}
+			return nil
+		}
+		return writeFileAtomically(s.f.ConfigFileDst, previous)
+	}
+
+	if existed && bytes.Equal(previous, contents) {
+		return false, restore, nil
+	}
+	if err := writeFileAtomically(s.f.ConfigFileDst, contents); err != nil {
+		return false, nil, err
+	}
+	return true, restore, nil
+}
+
+// writeFileAtomically replaces the specified file with a file with the
+// specified contents so that readers never observe a partially written file.
+// If the file is a symlink, the symlink itself is replaced.
+func writeFileAtomically(filename string, contents []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename))
+	if err != nil {
+		return fmt.Errorf("error creating temporary file: %v", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error writing temporary file: %v", err)
+	}
+	if err := tmp.Chmod(0644); err != nil {
+		tmp.Close()
+		return fmt.Errorf("error setting permissions of temporary file: %v", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("error writing temporary file: %v", err)
+	}
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return fmt.Errorf("error replacing config: %v", err)
+	}
+	return nil
+}
+
+// continuouslySyncConfigMapChanges watches the ConfigMap holding the configs
+// and updates the source with its contents. Since the contents of the selected
+// config may have changed, the config is resynced on each change.
+func continuouslySyncConfigMapChanges(clientset kubernetes.Interface, source *configMapSource, config *SyncableConfig, f *Flags, stop <-chan struct{}) cache.InformerSynced {
+	listWatch := cache.NewListWatchFromClient(
+		clientset.CoreV1().RESTClient(),
+		ResourceConfigMaps,
+		f.ConfigMapNamespace,
+		fields.OneTermEqualSelector("metadata.name", f.ConfigMapName),
+	)
+
+	_, controller := cache.NewInformerWithOptions(
+		cache.InformerOptions{
+			ListerWatcher: listWatch,
+			ObjectType:    &v1.ConfigMap{},
+			Handler: cache.ResourceEventHandlerFuncs{
+				AddFunc: func(obj interface{}) {
+					source.set(obj.(*v1.ConfigMap).Data)
+					config.Resync()
+				},
+				UpdateFunc: func(oldObj, newObj interface{}) {
+					source.set(newObj.(*v1.ConfigMap).Data)
+					config.Resync()
+				},
+				DeleteFunc: func(obj interface{}) {
+					klog.Warningf("ConfigMap %s/%s deleted; keeping the current config", f.ConfigMapNamespace, f.ConfigMapName)
+					source.set(nil)
+				},
+			},
+			ResyncPeriod: 0,
+		},
+	)
+
+	go controller.Run(stop)
+	return controller.HasSynced
+}
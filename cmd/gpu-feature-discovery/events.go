@@ -0,0 +1,108 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+
+	"k8s.io/klog/v2"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// getMigLayout returns a string that describes which GPUs have MIG enabled
+// and which MIG devices they are partitioned into. Comparing the layout from
+// two points in time is a cheap way to detect a MIG reconfiguration without
+// generating all labels.
+func getMigLayout(manager resource.Manager) (string, error) {
+	if err := manager.Init(); err != nil {
+		return "", fmt.Errorf("failed to initialize resource manager: %w", err)
+	}
+	defer func() {
+		_ = manager.Shutdown()
+	}()
+
+	devices, err := manager.GetDevices()
+	if err != nil {
+		return "", fmt.Errorf("error getting devices: %w", err)
+	}
+
+	var layout []string
+	for i, d := range devices {
+		enabled, err := d.IsMigEnabled()
+		if err != nil {
+			return "", fmt.Errorf("error checking if MIG is enabled on device %d: %w", i, err)
+		}
+		if !enabled {
+			layout = append(layout, fmt.Sprintf("%d:-", i))
+			continue
+		}
+		migs, err := d.GetMigDevices()
+		if err != nil {
+			return "", fmt.Errorf("error getting MIG devices of device %d: %w", i, err)
+		}
+		var names []string
+		for _, mig := range migs {
+			name, err := mig.GetName()
+			if err != nil {
+				return "", fmt.Errorf("error getting name of MIG device on device %d: %w", i, err)
+			}
+			names = append(names, name)
+		}
+		layout = append(layout, fmt.Sprintf("%d:[%s]", i, strings.Join(names, ",")))
+	}
+	return strings.Join(layout, ";"), nil
+}
+
+// listenTriggerSocket creates a unix socket at the specified path. A value is
+// sent on the returned channel for each connection to the socket. Triggers
+// that arrive while one is already pending are coalesced. The socket is
+// removed when the returned listener is closed.
+func listenTriggerSocket(path string) (net.Listener, <-chan struct{}, error) {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return nil, nil, fmt.Errorf("failed to remove stale trigger socket: %w", err)
+	}
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to listen on trigger socket: %w", err)
+	}
+
+	triggers := make(chan struct{}, 1)
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if errors.Is(err, net.ErrClosed) {
+				return
+			}
+			if err != nil {
+				klog.Warningf("Failed to accept connection on trigger socket: %v", err)
+				continue
+			}
+			_ = conn.Close()
+			select {
+			case triggers <- struct{}{}:
+			default:
+			}
+		}
+	}()
+
+	return listener, triggers, nil
+}
@@ -0,0 +1,210 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+// channelOutputer sends the labels that are output on a channel.
+type channelOutputer chan lm.Labels
+
+func (c channelOutputer) Output(labels lm.Labels) error {
+	c <- labels
+	return nil
+}
+
+func (c channelOutputer) next(t *testing.T) lm.Labels {
+	t.Helper()
+	select {
+	case labels := <-c:
+		return labels
+	case <-time.After(5 * time.Second):
+		t.Fatal("Timeout waiting for labels to be output")
+		return nil
+	}
+}
+
+func TestGetMigLayout(t *testing.T) {
+	manager := rt.NewManagerMockWithDevices(
+		rt.NewFullGPU(),
+		rt.NewMigEnabledDevice(
+			rt.NewMigDevice(3, 0, 20),
+			rt.NewMigDevice(1, 0, 5),
+		),
+		rt.NewMigEnabledDevice(),
+	)
+
+	layout, err := getMigLayout(manager)
+	require.NoError(t, err)
+	require.Equal(t, "0:-;1:[3g.20gb,1g.5gb];2:[]", layout)
+	require.Len(t, manager.InitCalls(), 1)
+	require.Len(t, manager.ShutdownCalls(), 1)
+}
+
+func TestRunRelabelOnTrigger(t *testing.T) {
+	var name atomic.Pointer[string]
+	name.Store(ptr("MOCKMODEL"))
+	device := rt.NewDeviceMock(false)
+	device.GetNameFunc = func() (string, error) { return *name.Load(), nil }
+	manager := rt.NewManagerMockWithDevices(device)
+
+	socket := filepath.Join(t.TempDir(), "trigger.sock")
+	conf := &spec.Config{
+		Flags: spec.Flags{
+			CommandLineFlags: spec.CommandLineFlags{
+				MigStrategy:     ptr("none"),
+				FailOnInitError: ptr(true),
+				GFD: &spec.GFDCommandLineFlags{
+					Oneshot:         ptr(false),
+					SleepInterval:   ptr(spec.Duration(math.MaxInt64)),
+					NoTimestamp:     ptr(true),
+					MachineTypeFile: ptr(testMachineTypeFile),
+					TriggerSocket:   ptr(socket),
+				},
+			},
+		},
+	}
+
+	setupMachineFile(t)
+	defer removeMachineFile(t)
+
+	outputs := make(channelOutputer, 1)
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	var runRestart bool
+	var runError error
+	go func() {
+		defer close(done)
+		d := gfd{
+			manager:       manager,
+			vgpu:          NewTestVGPUMock(),
+			config:        conf,
+			labelOutputer: outputs,
+		}
+		runRestart, runError = d.run(sigs)
+	}()
+
+	labels := outputs.next(t)
+	require.Equal(t, "MOCKMODEL", labels["nvidia.com/gpu.product"])
+
+	trigger := func() {
+		require.Eventually(t, func() bool {
+			conn, err := net.Dial("unix", socket)
+			if err != nil {
+				return false
+			}
+			return conn.Close() == nil
+		}, 5*time.Second, 10*time.Millisecond)
+	}
+
+	// Relabeling with unchanged labels does not output them again.
+	trigger()
+	require.Eventually(t, func() bool {
+		return len(manager.InitCalls()) >= 2
+	}, 5*time.Second, 10*time.Millisecond)
+	select {
+	case <-outputs:
+		t.Fatal("Unchanged labels were output")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	name.Store(ptr("NEWMODEL"))
+	trigger()
+	labels = outputs.next(t)
+	require.Equal(t, "NEWMODEL", labels["nvidia.com/gpu.product"])
+
+	sigs <- syscall.SIGTERM
+	<-done
+	require.NoError(t, runError)
+	require.False(t, runRestart)
+
+	_, err := os.Stat(socket)
+	require.True(t, os.IsNotExist(err), "Trigger socket not removed")
+}
+
+func TestRunRelabelOnMigLayoutChange(t *testing.T) {
+	var getMigDevices atomic.Value
+	setMigDevices := func(migs ...*resource.DeviceMock) {
+		getMigDevices.Store(rt.NewDeviceMock(true).WithMigDevices(migs...).GetMigDevicesFunc)
+	}
+	setMigDevices(rt.NewMigDevice(3, 0, 20))
+	device := rt.NewDeviceMock(true)
+	device.GetMigDevicesFunc = func() ([]resource.Device, error) {
+		return getMigDevices.Load().(func() ([]resource.Device, error))()
+	}
+	manager := rt.NewManagerMockWithDevices(device)
+
+	conf := &spec.Config{
+		Flags: spec.Flags{
+			CommandLineFlags: spec.CommandLineFlags{
+				MigStrategy:     ptr("mixed"),
+				FailOnInitError: ptr(true),
+				GFD: &spec.GFDCommandLineFlags{
+					Oneshot:         ptr(false),
+					SleepInterval:   ptr(spec.Duration(math.MaxInt64)),
+					NoTimestamp:     ptr(true),
+					MachineTypeFile: ptr(testMachineTypeFile),
+					MigPollInterval: ptr(spec.Duration(10 * time.Millisecond)),
+				},
+			},
+		},
+	}
+
+	setupMachineFile(t)
+	defer removeMachineFile(t)
+
+	outputs := make(channelOutputer, 1)
+	sigs := make(chan os.Signal, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		d := gfd{
+			manager:       manager,
+			vgpu:          NewTestVGPUMock(),
+			config:        conf,
+			labelOutputer: outputs,
+		}
+		_, _ = d.run(sigs)
+	}()
+
+	labels := outputs.next(t)
+	require.Equal(t, "1", labels["nvidia.com/mig-3g.20gb.count"])
+	require.NotContains(t, labels, "nvidia.com/mig-1g.5gb.count")
+
+	setMigDevices(rt.NewMigDevice(3, 0, 20), rt.NewMigDevice(1, 0, 5))
+	labels = outputs.next(t)
+	require.Equal(t, "1", labels["nvidia.com/mig-3g.20gb.count"])
+	require.Equal(t, "1", labels["nvidia.com/mig-1g.5gb.count"])
+
+	sigs <- syscall.SIGTERM
+	<-done
+}
@@ -5,11 +5,13 @@ package main
 import (
 	"encoding/json"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
 	"syscall"
 	"time"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/urfave/cli/v2"
 	"k8s.io/klog/v2"
 
@@ -18,10 +20,13 @@ import (
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/api/config/versioned"
+	"github.com/NVIDIA/k8s-device-plugin/internal/convert"
 	"github.com/NVIDIA/k8s-device-plugin/internal/flags"
 	"github.com/NVIDIA/k8s-device-plugin/internal/info"
 	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
 	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	"github.com/NVIDIA/k8s-device-plugin/internal/validate"
 	"github.com/NVIDIA/k8s-device-plugin/internal/vgpu"
 	"github.com/NVIDIA/k8s-device-plugin/internal/watch"
 )
@@ -47,6 +52,10 @@ func main() {
 	c.Action = func(ctx *cli.Context) error {
 		return start(ctx, config)
 	}
+	c.Commands = []*cli.Command{
+		validate.NewCommand("flags.plugin", "imex", "healthChecks"),
+		convert.NewCommand(),
+	}
 
 	config.flags = []cli.Flag{
 		&cli.StringFlag{
@@ -91,6 +100,23 @@ func main() {
 			Usage:   "a path to a file that contains the DMI (SMBIOS) information for the node",
 			EnvVars: []string{"GFD_MACHINE_TYPE_FILE"},
 		},
+		&cli.StringFlag{
+			Name:    "device-labels",
+			Value:   spec.DeviceLabelsNone,
+			Usage:   "whether to also label each individual GPU:\n\t\t[none | index | uuid]",
+			EnvVars: []string{"GFD_DEVICE_LABELS"},
+		},
+		&cli.GenericFlag{
+			Name:    "mig-poll-interval",
+			Value:   spec.NewDurationValue(spec.DefaultMigPollInterval),
+			Usage:   "the interval at which to check for MIG reconfigurations that trigger relabeling. Use 0 to disable",
+			EnvVars: []string{"GFD_MIG_POLL_INTERVAL"},
+		},
+		&cli.StringFlag{
+			Name:    "trigger-socket",
+			Usage:   "the path of a unix socket on which any connection triggers relabeling. No socket is created if this is empty",
+			EnvVars: []string{"GFD_TRIGGER_SOCKET"},
+		},
 		&cli.StringFlag{
 			Name:        "config-file",
 			Usage:       "the path to a config file as an alternative to command line options or environment variables",
@@ -138,12 +164,24 @@ func validateFlags(config *spec.Config) error {
 	default:
 		return fmt.Errorf("invalid --device-discovery-strategy option %v", *config.Flags.DeviceDiscoveryStrategy)
 	}
+	if config.Flags.GFD.DeviceLabels != nil {
+		switch *config.Flags.GFD.DeviceLabels {
+		case spec.DeviceLabelsNone:
+		case spec.DeviceLabelsIndex:
+		case spec.DeviceLabelsUUID:
+		default:
+			return fmt.Errorf("invalid --device-labels option %v", *config.Flags.GFD.DeviceLabels)
+		}
+	}
+	if d := config.Flags.GFD.MigPollInterval; d != nil && *d < 0 {
+		return fmt.Errorf("invalid --mig-poll-interval option %v: must be >= 0", *d)
+	}
 	return nil
 }
 
 // loadConfig loads the config from the spec file.
 func (cfg *Config) loadConfig(c *cli.Context) (*spec.Config, error) {
-	config, err := spec.NewConfig(c, cfg.flags)
+	config, err := versioned.NewConfig(c, cfg.flags)
 	if err != nil {
 		return nil, fmt.Errorf("unable to finalize config: %v", err)
 	}
@@ -160,6 +198,20 @@ func start(c *cli.Context, cfg *Config) error {
 		klog.Info("Exiting")
 	}()
 
+	// The directory of the config file is watched rather than the file itself
+	// since config files are typically replaced instead of being modified.
+	var watcher *fsnotify.Watcher
+	if cfg.configFile != "" {
+		configDir := filepath.Dir(cfg.configFile)
+		klog.Infof("Starting FS watcher for %v", configDir)
+		w, err := watch.Files(configDir)
+		if err != nil {
+			return fmt.Errorf("failed to create FS watcher for %v: %v", configDir, err)
+		}
+		defer w.Close()
+		watcher = w
+	}
+
 	klog.Info("Starting OS watcher.")
 	sigs := watch.Signals(syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
@@ -170,8 +222,6 @@ func start(c *cli.Context, cfg *Config) error {
 		if err != nil {
 			return fmt.Errorf("unable to load config: %v", err)
 		}
-		spec.DisableResourceNamingInConfig(config)
-
 		// Print the config to the output.
 		configJSON, err := json.MarshalIndent(config, "", "  ")
 		if err != nil {
@@ -217,6 +267,8 @@ func start(c *cli.Context, cfg *Config) error {
 			vgpu:          vgpul,
 			config:        config,
 			labelOutputer: labelOutputer,
+			configFile:    cfg.configFile,
+			watcher:       watcher,
 		}
 		restart, err := d.run(sigs)
 		if err != nil {
@@ -235,8 +287,18 @@ type gfd struct {
 	config  *spec.Config
 
 	labelOutputer lm.Outputer
+
+	// configFile is the path of the config file whose changes trigger a
+	// restart. These are detected using the optional watcher.
+	configFile string
+	watcher    *fsnotify.Watcher
 }
 
+// relabelDelay is the time to wait after an event that triggers relabeling.
+// Further events during this time postpone relabeling so that a burst of
+// events, such as a MIG reconfiguration of several GPUs, is handled once.
+const relabelDelay = time.Second
+
 func (d *gfd) run(sigs chan os.Signal) (bool, error) {
 	defer func() {
 		if d.config.Flags.UseNodeFeatureAPI != nil && *d.config.Flags.UseNodeFeatureAPI {
@@ -258,47 +320,96 @@ func (d *gfd) run(sigs chan os.Signal) (bool, error) {
 	}()
 
 	timestampLabeler := lm.NewTimestampLabeler(d.config)
-rerun:
-	loopLabelers, err := lm.NewLabelers(d.manager, d.vgpu, d.config)
-	if err != nil {
-		return false, err
-	}
 
-	labelers := lm.Merge(
-		timestampLabeler,
-		loopLabelers,
-	)
+	var migLayout string
+	var migPoll <-chan time.Time
+	if interval := d.getMigPollInterval(); interval > 0 && !*d.config.Flags.GFD.Oneshot {
+		migLayout = d.getMigLayout()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		migPoll = ticker.C
+	}
 
-	labels, err := labelers.Labels()
+	labels, err := d.label(timestampLabeler, nil)
 	if err != nil {
-		return false, fmt.Errorf("error generating labels: %v", err)
+		return false, err
 	}
 
-	if len(labels) <= 1 {
-		klog.Warning("No labels generated from any source")
+	if *d.config.Flags.GFD.Oneshot {
+		return false, nil
 	}
 
-	klog.Info("Creating Labels")
-	if err := d.labelOutputer.Output(labels); err != nil {
-		return false, err
+	var triggers <-chan struct{}
+	if path := d.config.Flags.GFD.TriggerSocket; path != nil && *path != "" {
+		klog.Infof("Listening for relabel triggers on %v", *path)
+		listener, t, err := listenTriggerSocket(*path)
+		if err != nil {
+			return false, err
+		}
+		defer listener.Close()
+		triggers = t
 	}
 
-	if *d.config.Flags.GFD.Oneshot {
-		return false, nil
+	var fsEvents <-chan fsnotify.Event
+	var fsErrors <-chan error
+	if d.watcher != nil {
+		fsEvents = d.watcher.Events
+		fsErrors = d.watcher.Errors
 	}
 
 	var rerunTimeout <-chan time.Time
+	var relabelTimeout <-chan time.Time
+	var restartTimeout <-chan time.Time
 	if d.config.Flags.GFD.SleepInterval.IsInfinite() {
 		klog.Info("Sleep interval is infinite, sleeping indefinitely")
-	} else {
-		klog.Info("Sleeping for ", *d.config.Flags.GFD.SleepInterval)
-		rerunTimeout = time.After(time.Duration(*d.config.Flags.GFD.SleepInterval))
 	}
 
 	for {
+		if rerunTimeout == nil && !d.config.Flags.GFD.SleepInterval.IsInfinite() {
+			klog.Info("Sleeping for ", *d.config.Flags.GFD.SleepInterval)
+			rerunTimeout = time.After(time.Duration(*d.config.Flags.GFD.SleepInterval))
+		}
+
 		select {
+		// The labels are output periodically even if they are unchanged.
 		case <-rerunTimeout:
-			goto rerun
+			rerunTimeout = nil
+			labels, err = d.label(timestampLabeler, nil)
+			if err != nil {
+				return false, err
+			}
+
+		// Relabeling triggered by an event only outputs changed labels.
+		case <-relabelTimeout:
+			labels, err = d.label(timestampLabeler, labels)
+			if err != nil {
+				return false, err
+			}
+
+		case <-migPoll:
+			if layout := d.getMigLayout(); layout != migLayout {
+				klog.Info("MIG layout changed, relabeling.")
+				migLayout = layout
+				relabelTimeout = time.After(relabelDelay)
+			}
+
+		case <-triggers:
+			klog.Info("Received relabel trigger, relabeling.")
+			relabelTimeout = time.After(relabelDelay)
+
+		// Changes to the config file trigger a restart once no further
+		// changes occur for relabelDelay.
+		case event := <-fsEvents:
+			if watch.IsConfigFileEvent(d.configFile, event) {
+				klog.Infof("inotify: %s changed, restarting.", d.configFile)
+				restartTimeout = time.After(relabelDelay)
+			}
+
+		case err := <-fsErrors:
+			klog.Infof("inotify: %s", err)
+
+		case <-restartTimeout:
+			return true, nil
 
 		// Watch for any signals from the OS. On SIGHUP trigger a reload of the config.
 		// On all other signals, exit the loop and exit the program.
@@ -315,6 +426,60 @@ rerun:
 	}
 }
 
+// label generates the labels for the node and outputs them. If the previous
+// labels are specified, the output is skipped if the labels are unchanged.
+func (d *gfd) label(timestampLabeler lm.Labeler, previous lm.Labels) (lm.Labels, error) {
+	loopLabelers, err := lm.NewLabelers(d.manager, d.vgpu, d.config)
+	if err != nil {
+		return nil, err
+	}
+
+	labelers := lm.Merge(
+		timestampLabeler,
+		loopLabelers,
+	)
+
+	labels, err := labelers.Labels()
+	if err != nil {
+		return nil, fmt.Errorf("error generating labels: %v", err)
+	}
+
+	if len(labels) <= 1 {
+		klog.Warning("No labels generated from any source")
+	}
+
+	if previous != nil && maps.Equal(labels, previous) {
+		klog.Info("Labels unchanged, skipping output")
+		return labels, nil
+	}
+
+	klog.Info("Creating Labels")
+	if err := d.labelOutputer.Output(labels); err != nil {
+		return nil, err
+	}
+	return labels, nil
+}
+
+// getMigPollInterval returns the interval at which the MIG layout is polled
+// for changes. Polling is disabled if this is 0.
+func (d *gfd) getMigPollInterval() time.Duration {
+	if d.config.Flags.GFD.MigPollInterval == nil || d.config.Flags.GFD.MigPollInterval.IsInfinite() {
+		return 0
+	}
+	return time.Duration(*d.config.Flags.GFD.MigPollInterval)
+}
+
+// getMigLayout returns the current MIG layout. Errors are logged and result
+// in an empty layout so that they do not stop GFD.
+func (d *gfd) getMigLayout() string {
+	layout, err := getMigLayout(d.manager)
+	if err != nil {
+		klog.Warningf("Failed to get MIG layout: %v", err)
+		return ""
+	}
+	return layout
+}
+
 func removeOutputFile(path string) error {
 	absPath, err := filepath.Abs(path)
 	if err != nil {
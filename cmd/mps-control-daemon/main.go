@@ -33,11 +33,14 @@ import (
 
 	"github.com/NVIDIA/k8s-device-plugin/cmd/mps-control-daemon/mount"
 	"github.com/NVIDIA/k8s-device-plugin/cmd/mps-control-daemon/mps"
+	"github.com/NVIDIA/k8s-device-plugin/internal/convert"
 	"github.com/NVIDIA/k8s-device-plugin/internal/info"
 	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/validate"
 	"github.com/NVIDIA/k8s-device-plugin/internal/watch"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/api/config/versioned"
 )
 
 // Config represents a collection of config options for the device plugin.
@@ -59,6 +62,8 @@ func main() {
 	}
 	c.Commands = []*cli.Command{
 		mount.NewCommand(),
+		validate.NewCommand("flags.gfd", "flags.plugin", "imex", "healthChecks"),
+		convert.NewCommand(),
 	}
 
 	config.flags = []cli.Flag{
@@ -87,12 +92,19 @@ func main() {
 
 // TODO: This needs to do similar validation to the plugin.
 func validateFlags(config *spec.Config) error {
+	sharing := spec.ValidateSharing(config)
+	for _, w := range sharing.Warnings {
+		klog.Warningf("Sharing config: %v", w)
+	}
+	if err := sharing.Errors.ToAggregate(); err != nil {
+		return fmt.Errorf("invalid sharing config: %w", err)
+	}
 	return nil
 }
 
 // loadConfig loads the config from the spec file.
 func (cfg *Config) loadConfig(c *cli.Context) (*spec.Config, error) {
-	config, err := spec.NewConfig(c, cfg.flags)
+	config, err := versioned.NewConfig(c, cfg.flags)
 	if err != nil {
 		return nil, fmt.Errorf("unable to finalize config: %w", err)
 	}
@@ -168,8 +180,6 @@ func startDaemons(c *cli.Context, cfg *Config) ([]*mps.Daemon, bool, error) {
 	if err != nil {
 		return nil, false, fmt.Errorf("unable to load config: %v", err)
 	}
-	spec.DisableResourceNamingInConfig(config)
-
 	nvmllib := nvml.New()
 	devicelib := device.New(nvmllib)
 	infolib := nvinfo.New(
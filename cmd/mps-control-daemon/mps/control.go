@@ -0,0 +1,185 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package mps
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultControlTimeout is the timeout for sending a command to the MPS
+	// control daemon and receiving its response.
+	defaultControlTimeout = 10 * time.Second
+)
+
+// The commands supported by the MPS control daemon that are used here.
+const (
+	commandQuit                             = "quit"
+	commandGetDefaultActiveThreadPercentage = "get_default_active_thread_percentage"
+	commandSetDefaultActiveThreadPercentage = "set_default_active_thread_percentage"
+	commandSetDefaultDevicePinnedMemLimit   = "set_default_device_pinned_mem_limit"
+)
+
+var errControlResponse = errors.New("unexpected response from MPS control daemon")
+
+// control defines the commands sent to an MPS control daemon.
+type control interface {
+	Quit() error
+	GetDefaultActiveThreadPercentage() (float64, error)
+	SetDefaultActiveThreadPercentage(percentage string) error
+	SetDefaultDevicePinnedMemLimit(index string, limit string) error
+}
+
+// ControlResponse represents the response of the MPS control daemon to a command.
+type ControlResponse struct {
+	Command string
+	// Lines holds the non-empty lines of the response.
+	Lines []string
+}
+
+// ControlClient sends commands to an MPS control daemon by running
+// nvidia-cuda-mps-control, which is the documented interface of the daemon.
+// Each command is written to the stdin of a new nvidia-cuda-mps-control
+// process and the response is read from its stdout.
+type ControlClient struct {
+	timeout time.Duration
+	// controlBin is the nvidia-cuda-mps-control binary.
+	controlBin string
+	// env holds the environment of controlBin, which selects the pipe
+	// directory of the MPS control daemon.
+	env []string
+}
+
+var _ control = (*ControlClient)(nil)
+
+// NewControlClient creates a client for the MPS control daemon with the
+// specified pipe directory.
+func NewControlClient(pipeDir string) *ControlClient {
+	return &ControlClient{
+		timeout:    defaultControlTimeout,
+		controlBin: mpsControlBin,
+		env:        []string{"CUDA_MPS_PIPE_DIRECTORY=" + pipeDir},
+	}
+}
+
+// Send sends the specified command with its arguments to the MPS control
+// daemon by running nvidia-cuda-mps-control and returns the response.
+func (c *ControlClient) Send(command string, args ...string) (*ControlResponse, error) {
+	line := strings.Join(append([]string{command}, args...), " ")
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, c.controlBin)
+	cmd.Env = c.env
+	cmd.Stdin = strings.NewReader(line + "\n")
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to send %q using %v: %w", command, c.controlBin, err)
+	}
+	return readResponse(command, bytes.NewReader(output))
+}
+
+// readResponse reads the response to the specified command.
+func readResponse(command string, r io.Reader) (*ControlResponse, error) {
+	response := &ControlResponse{
+		Command: command,
+	}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if text := strings.TrimSpace(scanner.Text()); text != "" {
+			response.Lines = append(response.Lines, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read response to %q from MPS control daemon: %w", command, err)
+	}
+	return response, nil
+}
+
+// do sends the specified command and checks its response.
+func (c *ControlClient) do(check func(*ControlResponse) error, command string, args ...string) (*ControlResponse, error) {
+	response, err := c.Send(command, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := check(response); err != nil {
+		return nil, err
+	}
+	return response, nil
+}
+
+// checkEmpty checks that the MPS control daemon did not reply to a command.
+// The daemon only replies to commands that change its settings if these are
+// rejected.
+func checkEmpty(response *ControlResponse) error {
+	if len(response.Lines) > 0 {
+		return fmt.Errorf("%w to %q: %v", errControlResponse, response.Command, strings.Join(response.Lines, "; "))
+	}
+	return nil
+}
+
+// checkPercentage checks that the MPS control daemon replied with a single
+// percentage.
+func checkPercentage(response *ControlResponse) error {
+	if len(response.Lines) != 1 {
+		return fmt.Errorf("%w to %q: %v", errControlResponse, response.Command, response.Lines)
+	}
+	if _, err := strconv.ParseFloat(response.Lines[0], 64); err != nil {
+		return fmt.Errorf("%w to %q: %v", errControlResponse, response.Command, response.Lines[0])
+	}
+	return nil
+}
+
+// Quit instructs the MPS control daemon to shut down.
+func (c *ControlClient) Quit() error {
+	_, err := c.do(checkEmpty, commandQuit)
+	return err
+}
+
+// GetDefaultActiveThreadPercentage returns the default active thread
+// percentage of the MPS control daemon.
+func (c *ControlClient) GetDefaultActiveThreadPercentage() (float64, error) {
+	response, err := c.do(checkPercentage, commandGetDefaultActiveThreadPercentage)
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(response.Lines[0], 64)
+}
+
+// SetDefaultActiveThreadPercentage sets the default active thread percentage
+// for the clients of the MPS control daemon.
+func (c *ControlClient) SetDefaultActiveThreadPercentage(percentage string) error {
+	_, err := c.do(checkEmpty, commandSetDefaultActiveThreadPercentage, percentage)
+	return err
+}
+
+// SetDefaultDevicePinnedMemLimit sets the default pinned memory limit of the
+// device with the specified index for the clients of the MPS control daemon.
+func (c *ControlClient) SetDefaultDevicePinnedMemLimit(index string, limit string) error {
+	_, err := c.do(checkEmpty, commandSetDefaultDevicePinnedMemLimit, index, limit)
+	return err
+}
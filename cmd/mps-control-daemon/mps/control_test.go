@@ -0,0 +1,92 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package mps
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeControlBin creates a script that records the commands it reads in the
+// returned file and replies as nvidia-cuda-mps-control would.
+func fakeControlBin(t *testing.T) (string, string) {
+	dir := t.TempDir()
+	commands := filepath.Join(dir, "commands")
+	script := filepath.Join(dir, "nvidia-cuda-mps-control")
+	contents := `#!/bin/sh
+read line
+echo "$line $CUDA_MPS_PIPE_DIRECTORY" >> ` + commands + `
+case "$line" in
+get_default_active_thread_percentage) echo 12.5 ;;
+set_default_active_thread_percentage\ 200) echo "Invalid percentage" ;;
+esac
+`
+	require.NoError(t, os.WriteFile(script, []byte(contents), 0755))
+	return script, commands
+}
+
+func TestControlClient(t *testing.T) {
+	script, commands := fakeControlBin(t)
+	pipeDir := t.TempDir()
+	client := NewControlClient(pipeDir)
+	client.controlBin = script
+
+	percentage, err := client.GetDefaultActiveThreadPercentage()
+	require.NoError(t, err)
+	require.Equal(t, 12.5, percentage)
+
+	require.NoError(t, client.SetDefaultDevicePinnedMemLimit("0", "4096M"))
+	require.NoError(t, client.SetDefaultActiveThreadPercentage("50"))
+	// A reply to a command that changes a setting means it was rejected.
+	require.ErrorIs(t, client.SetDefaultActiveThreadPercentage("200"), errControlResponse)
+	require.NoError(t, client.Quit())
+
+	received, err := os.ReadFile(commands)
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"get_default_active_thread_percentage " + pipeDir,
+		"set_default_device_pinned_mem_limit 0 4096M " + pipeDir,
+		"set_default_active_thread_percentage 50 " + pipeDir,
+		"set_default_active_thread_percentage 200 " + pipeDir,
+		"quit " + pipeDir,
+	}, strings.Split(strings.TrimSpace(string(received)), "\n"))
+}
+
+func TestControlClientUnexpectedResponse(t *testing.T) {
+	dir := t.TempDir()
+	script := filepath.Join(dir, "nvidia-cuda-mps-control")
+	require.NoError(t, os.WriteFile(script, []byte("#!/bin/sh\necho 'Error: not a number'\n"), 0755))
+	client := NewControlClient(dir)
+	client.controlBin = script
+
+	_, err := client.GetDefaultActiveThreadPercentage()
+	require.ErrorIs(t, err, errControlResponse)
+}
+
+func TestControlClientNotRunning(t *testing.T) {
+	client := NewControlClient(t.TempDir())
+	client.controlBin = filepath.Join(t.TempDir(), "nvidia-cuda-mps-control")
+	client.timeout = 100 * time.Millisecond
+
+	_, err := client.Send(commandQuit)
+	require.Error(t, err)
+}
@@ -17,28 +17,27 @@
 package mps
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/opencontainers/selinux/go-selinux"
 	"k8s.io/klog/v2"
 
 	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
 )
 
-type computeMode string
-
 const (
 	mpsControlBin = "nvidia-cuda-mps-control"
 
-	computeModeExclusiveProcess = computeMode("EXCLUSIVE_PROCESS")
-	computeModeDefault          = computeMode("DEFAULT")
-
 	unprivilegedContainerSELinuxLabel = "system_u:object_r:container_file_t:s0"
 )
 
@@ -48,6 +47,23 @@ const (
 // thread limits are set for the devices that the resource makes available.
 type Daemon struct {
 	rm rm.ResourceManager
+	// migUUID is set if the daemon is responsible for a single MIG device of
+	// the resource. MPS requires a separate daemon for each MIG device.
+	migUUID string
+	// nvmllib is used to set the compute mode of the devices.
+	nvmllib nvml.Interface
+	// computeModes counts the started daemons that rely on the compute mode
+	// of each GPU. It is shared by the daemons of a manager since the daemons
+	// for the MIG devices of a GPU all rely on the compute mode of that GPU.
+	// If this is nil, the daemon assumes that it is the only daemon for its
+	// GPUs.
+	computeModes *computeModeRefs
+	// computeModeIndices holds the indices of the GPUs whose compute mode was
+	// set when the daemon was started.
+	computeModeIndices []string
+	// control is used to send commands to the MPS control daemon. If this is
+	// nil, nvidia-cuda-mps-control is run for the pipe directory.
+	control control
 	// root represents the root at which the files and folders controlled by the
 	// daemon are created. These include the log and pipe directories.
 	root Root
@@ -63,9 +79,36 @@ func NewDaemon(rm rm.ResourceManager, root Root) *Daemon {
 	}
 }
 
+// NewMigDaemon creates an MPS daemon instance for the specified MIG device of
+// a resource.
+func NewMigDaemon(rm rm.ResourceManager, root Root, uuid string) *Daemon {
+	return &Daemon{
+		rm:      rm,
+		root:    root,
+		migUUID: uuid,
+	}
+}
+
+// MigUUID returns the UUID of the MIG device that the daemon is responsible
+// for. This is empty if the daemon is responsible for all devices of the
+// resource.
+func (d *Daemon) MigUUID() string {
+	return d.migUUID
+}
+
 // Devices returns the list of devices under the control of this MPS daemon.
 func (d *Daemon) Devices() rm.Devices {
-	return d.rm.Devices()
+	devices := d.rm.Devices()
+	if d.migUUID == "" {
+		return devices
+	}
+	migDevices := make(rm.Devices)
+	for id, device := range devices {
+		if device.GetUUID() == d.migUUID {
+			migDevices[id] = device
+		}
+	}
+	return migDevices
 }
 
 type envvars map[string]string
@@ -80,21 +123,40 @@ func (e envvars) toSlice() []string {
 
 // EnvVars returns the environment variables required for the daemon.
 // These should be passed to clients consuming the device shared using MPS.
-// TODO: Set CUDA_VISIBLE_DEVICES to include only the devices for this resource type.
 func (d *Daemon) EnvVars() envvars {
-	return map[string]string{
+	envs := map[string]string{
 		"CUDA_MPS_PIPE_DIRECTORY": d.PipeDir(),
 		"CUDA_MPS_LOG_DIRECTORY":  d.LogDir(),
 	}
+	// The daemon must only see the devices of its resource since other devices
+	// may not be shared. For a MIG device, this is only the MIG device itself.
+	if uuids := d.visibleUUIDs(); len(uuids) > 0 {
+		envs["CUDA_VISIBLE_DEVICES"] = strings.Join(uuids, ",")
+	}
+	return envs
+}
+
+// visibleUUIDs returns the UUIDs of the devices visible to the daemon in index
+// order. MPS numbers the devices by their position in this list.
+func (d *Daemon) visibleUUIDs() []string {
+	devices := d.Devices()
+	var uuids []string
+	for _, id := range devices.GetIDsInIndexOrder() {
+		uuid := devices[id].GetUUID()
+		if !slices.Contains(uuids, uuid) {
+			uuids = append(uuids, uuid)
+		}
+	}
+	return uuids
 }
 
 // Start starts the MPS deamon as a background process.
 func (d *Daemon) Start() error {
-	if err := d.setComputeMode(computeModeExclusiveProcess); err != nil {
-		return fmt.Errorf("error setting compute mode %v: %w", computeModeExclusiveProcess, err)
+	if err := d.acquireComputeMode(); err != nil {
+		return err
 	}
 
-	klog.InfoS("Staring MPS daemon", "resource", d.rm.Resource())
+	klog.InfoS("Staring MPS daemon", "resource", d.rm.Resource(), "migDevice", d.migUUID)
 
 	pipeDir := d.PipeDir()
 	if err := os.MkdirAll(pipeDir, 0755); err != nil {
@@ -116,17 +178,8 @@ func (d *Daemon) Start() error {
 		return err
 	}
 
-	for index, limit := range d.perDevicePinnedDeviceMemoryLimits() {
-		_, err := d.EchoPipeToControl(fmt.Sprintf("set_default_device_pinned_mem_limit %s %s", index, limit))
-		if err != nil {
-			return fmt.Errorf("error setting pinned memory limit for device %v: %w", index, err)
-		}
-	}
-	if threadPercentage := d.activeThreadPercentage(); threadPercentage != "" {
-		_, err := d.EchoPipeToControl(fmt.Sprintf("set_default_active_thread_percentage %s", threadPercentage))
-		if err != nil {
-			return fmt.Errorf("error setting active thread percentage: %w", err)
-		}
+	if err := d.applyLimits(); err != nil {
+		return err
 	}
 
 	statusFile, err := os.Create(d.startedFile())
@@ -144,6 +197,23 @@ func (d *Daemon) Start() error {
 	return nil
 }
 
+// applyLimits sets the pinned memory limits and the active thread percentage
+// for the clients of the MPS control daemon.
+func (d *Daemon) applyLimits() error {
+	control := d.controlClient()
+	for index, limit := range d.perDevicePinnedDeviceMemoryLimits() {
+		if err := control.SetDefaultDevicePinnedMemLimit(index, limit); err != nil {
+			return fmt.Errorf("error setting pinned memory limit for device %v: %w", index, err)
+		}
+	}
+	if threadPercentage := d.activeThreadPercentage(); threadPercentage != "" {
+		if err := control.SetDefaultActiveThreadPercentage(threadPercentage); err != nil {
+			return fmt.Errorf("error setting active thread percentage: %w", err)
+		}
+	}
+	return nil
+}
+
 func setSELinuxContext(path string, context string) error {
 	_, err := os.Stat("/sys/fs/selinux")
 	if err != nil && errors.Is(err, os.ErrNotExist) {
@@ -159,17 +229,16 @@ func setSELinuxContext(path string, context string) error {
 
 // Stop ensures that the MPS daemon is quit.
 func (d *Daemon) Stop() error {
-	_, err := d.EchoPipeToControl("quit")
-	if err != nil {
+	if err := d.controlClient().Quit(); err != nil {
 		return fmt.Errorf("error sending quit message: %w", err)
 	}
-	klog.InfoS("Stopped MPS control daemon", "resource", d.rm.Resource())
+	klog.InfoS("Stopped MPS control daemon", "resource", d.rm.Resource(), "migDevice", d.migUUID)
 
-	err = d.logTailer.Stop()
+	err := d.logTailer.Stop()
 	klog.InfoS("Stopped log tailer", "resource", d.rm.Resource(), "error", err)
 
-	if err := d.setComputeMode(computeModeDefault); err != nil {
-		return fmt.Errorf("error setting compute mode %v: %w", computeModeDefault, err)
+	if err := d.releaseComputeMode(); err != nil {
+		return err
 	}
 
 	if err := os.Remove(d.startedFile()); err != nil && err != os.ErrNotExist {
@@ -185,10 +254,16 @@ func (d *Daemon) Stop() error {
 }
 
 func (d *Daemon) LogDir() string {
+	if d.migUUID != "" {
+		return d.root.MigLogDir(d.rm.Resource(), d.migUUID)
+	}
 	return d.root.LogDir(d.rm.Resource())
 }
 
 func (d *Daemon) PipeDir() string {
+	if d.migUUID != "" {
+		return d.root.MigPipeDir(d.rm.Resource(), d.migUUID)
+	}
 	return d.root.PipeDir(d.rm.Resource())
 }
 
@@ -197,84 +272,208 @@ func (d *Daemon) ShmDir() string {
 }
 
 func (d *Daemon) startedFile() string {
+	if d.migUUID != "" {
+		return d.root.migStartedFile(d.rm.Resource(), d.migUUID)
+	}
 	return d.root.startedFile(d.rm.Resource())
 }
 
 // AssertHealthy checks that the MPS control daemon is healthy.
+// The daemon is considered healthy if it has completed its startup (as
+// indicated by the .started file) and responds to commands.
 func (d *Daemon) AssertHealthy() error {
-	_, err := d.EchoPipeToControl("get_default_active_thread_percentage")
+	if _, err := os.Stat(d.startedFile()); err != nil {
+		return fmt.Errorf("MPS daemon has not started: %w", err)
+	}
+	_, err := d.controlClient().GetDefaultActiveThreadPercentage()
 	return err
 }
 
-// EchoPipeToControl sends the specified command to the MPS control daemon.
-func (d *Daemon) EchoPipeToControl(command string) (string, error) {
-	var out bytes.Buffer
-	reader, writer := io.Pipe()
-	defer writer.Close()
-	defer reader.Close()
-
-	mpsDaemon := exec.Command(mpsControlBin)
-	mpsDaemon.Env = append(mpsDaemon.Env, d.EnvVars().toSlice()...)
-
-	mpsDaemon.Stdin = reader
-	mpsDaemon.Stdout = &out
-
-	if err := mpsDaemon.Start(); err != nil {
-		return "", fmt.Errorf("failed to start NVIDIA MPS command: %w", err)
+// controlClient returns the client used to send commands to the MPS control
+// daemon.
+func (d *Daemon) controlClient() control {
+	if d.control != nil {
+		return d.control
 	}
+	client := NewControlClient(d.PipeDir())
+	client.env = d.EnvVars().toSlice()
+	return client
+}
 
-	if _, err := writer.Write([]byte(command)); err != nil {
-		return "", fmt.Errorf("failed to write message to pipe: %w", err)
+// acquireComputeMode sets the compute mode of the GPUs of the daemon to
+// EXCLUSIVE_PROCESS. For MIG devices the compute mode is set on the parent GPU.
+func (d *Daemon) acquireComputeMode() error {
+	indices := d.computeModeDeviceIndices()
+	if err := d.setComputeMode(nvml.COMPUTEMODE_EXCLUSIVE_PROCESS, indices); err != nil {
+		return fmt.Errorf("error setting compute mode to exclusive process: %w", err)
 	}
-	_ = writer.Close()
+	d.computeModeIndices = indices
+	d.computeModes.acquire(indices)
+	return nil
+}
 
-	if err := mpsDaemon.Wait(); err != nil {
-		return "", fmt.Errorf("failed to send command to MPS daemon: %w", err)
+// releaseComputeMode resets the compute mode of the GPUs of the daemon to
+// DEFAULT. The compute mode of a GPU is only reset once no other daemon relies
+// on it, as is the case for the daemons of the other MIG devices of the GPU.
+func (d *Daemon) releaseComputeMode() error {
+	unused := d.computeModes.release(d.computeModeIndices)
+	d.computeModeIndices = nil
+	if err := d.setComputeMode(nvml.COMPUTEMODE_DEFAULT, unused); err != nil {
+		return fmt.Errorf("error setting compute mode to default: %w", err)
 	}
-	return out.String(), nil
+	return nil
 }
 
-func (d *Daemon) setComputeMode(mode computeMode) error {
-	for _, uuid := range d.Devices().GetUUIDs() {
-		cmd := exec.Command(
-			"nvidia-smi",
-			"-i", uuid,
-			"-c", string(mode))
-		output, err := cmd.CombinedOutput()
+// setComputeMode sets the compute mode of the GPUs with the specified indices.
+func (d *Daemon) setComputeMode(mode nvml.ComputeMode, indices []string) error {
+	if len(indices) == 0 {
+		return nil
+	}
+	if d.nvmllib == nil {
+		return fmt.Errorf("no NVML library configured")
+	}
+	if ret := d.nvmllib.Init(); ret != nvml.SUCCESS {
+		return fmt.Errorf("failed to initialize NVML: %w", ret)
+	}
+	defer func() {
+		_ = d.nvmllib.Shutdown()
+	}()
+
+	for _, index := range indices {
+		i, err := strconv.Atoi(index)
 		if err != nil {
-			klog.Errorf("\n%v", string(output))
-			return fmt.Errorf("error running nvidia-smi: %w", err)
+			return fmt.Errorf("invalid device index %q: %w", index, err)
+		}
+		device, ret := d.nvmllib.DeviceGetHandleByIndex(i)
+		if ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to get device at index %v: %w", index, ret)
+		}
+		if ret := device.SetComputeMode(mode); ret != nvml.SUCCESS {
+			return fmt.Errorf("failed to set compute mode for device at index %v: %w", index, ret)
 		}
 	}
 	return nil
 }
 
-// perDevicePinnedMemoryLimits returns the pinned memory limits for each device.
+// computeModeDeviceIndices returns the indices of the GPUs whose compute mode
+// is managed by the daemon. Since the compute mode cannot be set for individual
+// MIG devices, the index of the parent GPU is returned for these.
+func (d *Daemon) computeModeDeviceIndices() []string {
+	seen := make(map[string]bool)
+	var indices []string
+	for _, device := range d.Devices() {
+		index := strings.SplitN(device.Index, ":", 2)[0]
+		if seen[index] {
+			continue
+		}
+		seen[index] = true
+		indices = append(indices, index)
+	}
+	sort.Strings(indices)
+	return indices
+}
+
+// perDevicePinnedMemoryLimits returns the pinned memory limits for each device
+// keyed by the ordinal of the device as seen by the MPS control daemon. This is
+// the position of the device in CUDA_VISIBLE_DEVICES and not its index on the
+// node. An explicitly configured limit takes precedence over dividing the
+// memory of a device evenly between its replicas.
 func (m *Daemon) perDevicePinnedDeviceMemoryLimits() map[string]string {
+	ordinals := make(map[string]string)
+	for i, uuid := range m.visibleUUIDs() {
+		ordinals[uuid] = strconv.Itoa(i)
+	}
+
 	totalMemoryInBytesPerDevice := make(map[string]uint64)
+	pinnedMemoryInBytesPerDevice := make(map[string]uint64)
 	replicasPerDevice := make(map[string]uint64)
 	for _, device := range m.Devices() {
-		index := device.Index
-		totalMemoryInBytesPerDevice[index] = device.TotalMemory
-		replicasPerDevice[index] += 1
+		ordinal := ordinals[device.GetUUID()]
+		totalMemoryInBytesPerDevice[ordinal] = device.TotalMemory
+		pinnedMemoryInBytesPerDevice[ordinal] = device.PinnedMemoryLimit
+		replicasPerDevice[ordinal] += 1
 	}
 
 	limits := make(map[string]string)
-	for index, totalMemory := range totalMemoryInBytesPerDevice {
+	for ordinal, totalMemory := range totalMemoryInBytesPerDevice {
+		if limit := pinnedMemoryInBytesPerDevice[ordinal]; limit > 0 {
+			limits[ordinal] = fmt.Sprintf("%vM", limit/1024/1024)
+			continue
+		}
 		if totalMemory == 0 {
 			continue
 		}
-		replicas := replicasPerDevice[index]
-		limits[index] = fmt.Sprintf("%vM", totalMemory/replicas/1024/1024)
+		replicas := replicasPerDevice[ordinal]
+		limits[ordinal] = fmt.Sprintf("%vM", totalMemory/replicas/1024/1024)
 	}
 	return limits
 }
 
+// computeModeRefs counts the started daemons that rely on the compute mode of
+// each GPU.
+type computeModeRefs struct {
+	sync.Mutex
+	counts map[string]int
+}
+
+func newComputeModeRefs() *computeModeRefs {
+	return &computeModeRefs{
+		counts: make(map[string]int),
+	}
+}
+
+// acquire records that a daemon relies on the compute mode of the GPUs with
+// the specified indices.
+func (r *computeModeRefs) acquire(indices []string) {
+	if r == nil {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	for _, index := range indices {
+		r.counts[index]++
+	}
+}
+
+// release records that a daemon no longer relies on the compute mode of the
+// GPUs with the specified indices and returns the indices of the GPUs that no
+// daemon relies on anymore.
+func (r *computeModeRefs) release(indices []string) []string {
+	if r == nil {
+		return indices
+	}
+	r.Lock()
+	defer r.Unlock()
+	var unused []string
+	for _, index := range indices {
+		r.counts[index]--
+		if r.counts[index] > 0 {
+			continue
+		}
+		delete(r.counts, index)
+		unused = append(unused, index)
+	}
+	return unused
+}
+
+// activeThreadPercentage returns the default active thread percentage for the
+// clients of the daemon. Since this applies to all devices, the percentage is
+// derived from the device with the most replicas if it is not set explicitly.
 func (m *Daemon) activeThreadPercentage() string {
 	if len(m.Devices()) == 0 {
 		return ""
 	}
-	replicasPerDevice := len(m.Devices()) / len(m.Devices().GetUUIDs())
+	maxReplicasPerDevice := 0
+	replicasPerDevice := make(map[string]int)
+	for _, device := range m.Devices() {
+		if device.ActiveThreadPercentage > 0 {
+			return fmt.Sprintf("%d", device.ActiveThreadPercentage)
+		}
+		replicasPerDevice[device.Index]++
+		if replicasPerDevice[device.Index] > maxReplicasPerDevice {
+			maxReplicasPerDevice = replicasPerDevice[device.Index]
+		}
+	}
 
-	return fmt.Sprintf("%d", 100/replicasPerDevice)
+	return fmt.Sprintf("%d", 100/maxReplicasPerDevice)
 }
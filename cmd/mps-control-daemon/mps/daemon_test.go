@@ -0,0 +1,335 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package mps
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+func TestDaemonLimits(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+
+	replicated := func(uuid string, index string, replicas int, modifiers ...func(*rm.Device)) rm.Devices {
+		devices := make(rm.Devices)
+		for i := 0; i < replicas; i++ {
+			d := &rm.Device{
+				Index:       index,
+				TotalMemory: 16 * gib,
+				Replicas:    replicas,
+			}
+			d.ID = fmt.Sprintf("%s::%d", uuid, i)
+			for _, modify := range modifiers {
+				modify(d)
+			}
+			devices[d.ID] = d
+		}
+		return devices
+	}
+	merge := func(devices ...rm.Devices) rm.Devices {
+		merged := make(rm.Devices)
+		for _, ds := range devices {
+			for id, d := range ds {
+				merged[id] = d
+			}
+		}
+		return merged
+	}
+
+	testCases := []struct {
+		description                    string
+		devices                        rm.Devices
+		expectedPinnedMemoryLimits     map[string]string
+		expectedActiveThreadPercentage string
+	}{
+		{
+			description:                    "no devices",
+			devices:                        rm.Devices{},
+			expectedPinnedMemoryLimits:     map[string]string{},
+			expectedActiveThreadPercentage: "",
+		},
+		{
+			description: "equal replicas",
+			devices: merge(
+				replicated("GPU-0", "0", 4),
+				replicated("GPU-1", "1", 4),
+			),
+			expectedPinnedMemoryLimits: map[string]string{
+				"0": "4096M",
+				"1": "4096M",
+			},
+			expectedActiveThreadPercentage: "25",
+		},
+		{
+			description: "heterogeneous replicas use the most replicated device for threads",
+			devices: merge(
+				replicated("GPU-0", "0", 2),
+				replicated("GPU-1", "1", 8),
+			),
+			expectedPinnedMemoryLimits: map[string]string{
+				"0": "8192M",
+				"1": "2048M",
+			},
+			expectedActiveThreadPercentage: "12",
+		},
+		{
+			description: "limits are keyed by the position of the device in CUDA_VISIBLE_DEVICES",
+			devices: merge(
+				replicated("GPU-7", "7", 2),
+				replicated("GPU-2", "2", 4),
+			),
+			expectedPinnedMemoryLimits: map[string]string{
+				"0": "4096M",
+				"1": "8192M",
+			},
+			expectedActiveThreadPercentage: "25",
+		},
+		{
+			description: "explicit limits take precedence",
+			devices: merge(
+				replicated("GPU-0", "0", 2, func(d *rm.Device) {
+					d.PinnedMemoryLimit = 3 * gib
+					d.ActiveThreadPercentage = 40
+				}),
+				replicated("GPU-1", "1", 8, func(d *rm.Device) {
+					d.PinnedMemoryLimit = 3 * gib
+					d.ActiveThreadPercentage = 40
+				}),
+			),
+			expectedPinnedMemoryLimits: map[string]string{
+				"0": "3072M",
+				"1": "3072M",
+			},
+			expectedActiveThreadPercentage: "40",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			d := NewDaemon(&rm.ResourceManagerMock{
+				DevicesFunc: func() rm.Devices { return tc.devices },
+			}, "")
+
+			require.EqualValues(t, tc.expectedPinnedMemoryLimits, d.perDevicePinnedDeviceMemoryLimits())
+			require.EqualValues(t, tc.expectedActiveThreadPercentage, d.activeThreadPercentage())
+		})
+	}
+}
+
+func TestDaemonEnvVars(t *testing.T) {
+	devices := rm.Devices{
+		"GPU-a::0":  &rm.Device{Device: pluginapi.Device{ID: "GPU-a::0"}, Index: "10"},
+		"GPU-a::1":  &rm.Device{Device: pluginapi.Device{ID: "GPU-a::1"}, Index: "10"},
+		"GPU-b::0":  &rm.Device{Device: pluginapi.Device{ID: "GPU-b::0"}, Index: "2"},
+		"GPU-b::1":  &rm.Device{Device: pluginapi.Device{ID: "GPU-b::1"}, Index: "2"},
+		"GPU-cc::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-cc::0"}, Index: "3"},
+	}
+	d := NewDaemon(&rm.ResourceManagerMock{
+		DevicesFunc:  func() rm.Devices { return devices },
+		ResourceFunc: func() spec.ResourceName { return "nvidia.com/gpu.shared" },
+	}, "/mps")
+
+	require.Equal(t, envvars{
+		"CUDA_MPS_PIPE_DIRECTORY": "/mps/nvidia.com/gpu.shared/pipe",
+		"CUDA_MPS_LOG_DIRECTORY":  "/mps/nvidia.com/gpu.shared/log",
+		"CUDA_VISIBLE_DEVICES":    "GPU-b,GPU-cc,GPU-a",
+	}, d.EnvVars())
+}
+
+func TestMigDaemon(t *testing.T) {
+	devices := rm.Devices{
+		"MIG-GPU-0/1/0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-0/1/0::0"}, Index: "0:0"},
+		"MIG-GPU-0/1/0::1": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-0/1/0::1"}, Index: "0:0"},
+		"MIG-GPU-0/2/0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-0/2/0::0"}, Index: "0:1"},
+	}
+	d := NewMigDaemon(&rm.ResourceManagerMock{
+		DevicesFunc:  func() rm.Devices { return devices },
+		ResourceFunc: func() spec.ResourceName { return "nvidia.com/gpu" },
+	}, "/mps", "MIG-GPU-0/1/0")
+
+	require.ElementsMatch(t, []string{"MIG-GPU-0/1/0::0", "MIG-GPU-0/1/0::1"}, d.Devices().GetIDs())
+	require.Equal(t, []string{"0"}, d.computeModeDeviceIndices())
+	require.Equal(t, "/mps/nvidia.com/gpu/MIG-GPU-0_1_0/pipe", d.PipeDir())
+	require.Equal(t, "/mps/nvidia.com/gpu/MIG-GPU-0_1_0/log", d.LogDir())
+	require.Equal(t, "/mps/nvidia.com/gpu/MIG-GPU-0_1_0/.started", d.startedFile())
+	require.Equal(t, "MIG-GPU-0/1/0", d.EnvVars()["CUDA_VISIBLE_DEVICES"])
+}
+
+func TestMigDaemonLimits(t *testing.T) {
+	devices := rm.Devices{
+		"MIG-GPU-1/1/0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-1/1/0::0"}, Index: "1:2", TotalMemory: 4 * 1024 * 1024 * 1024},
+		"MIG-GPU-1/1/0::1": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-1/1/0::1"}, Index: "1:2", TotalMemory: 4 * 1024 * 1024 * 1024},
+		"MIG-GPU-1/2/0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-GPU-1/2/0::0"}, Index: "1:3", TotalMemory: 4 * 1024 * 1024 * 1024},
+	}
+	control := &fakeControl{}
+	d := NewMigDaemon(&rm.ResourceManagerMock{
+		DevicesFunc: func() rm.Devices { return devices },
+	}, "", "MIG-GPU-1/1/0")
+	d.control = control
+
+	// The daemon only sees its MIG device, which is therefore device 0.
+	require.NoError(t, d.applyLimits())
+	require.Equal(t, []string{
+		"set_default_device_pinned_mem_limit 0 2048M",
+		"set_default_active_thread_percentage 50",
+	}, control.commands)
+}
+
+type fakeControl struct {
+	commands []string
+	err      error
+}
+
+func (c *fakeControl) Quit() error {
+	c.commands = append(c.commands, commandQuit)
+	return c.err
+}
+
+func (c *fakeControl) GetDefaultActiveThreadPercentage() (float64, error) {
+	c.commands = append(c.commands, commandGetDefaultActiveThreadPercentage)
+	return 100, c.err
+}
+
+func (c *fakeControl) SetDefaultActiveThreadPercentage(percentage string) error {
+	c.commands = append(c.commands, commandSetDefaultActiveThreadPercentage+" "+percentage)
+	return c.err
+}
+
+func (c *fakeControl) SetDefaultDevicePinnedMemLimit(index string, limit string) error {
+	c.commands = append(c.commands, commandSetDefaultDevicePinnedMemLimit+" "+index+" "+limit)
+	return c.err
+}
+
+type fakeComputeModeDevice struct {
+	nvml.Device
+	mode *nvml.ComputeMode
+}
+
+func (d fakeComputeModeDevice) SetComputeMode(mode nvml.ComputeMode) nvml.Return {
+	*d.mode = mode
+	return nvml.SUCCESS
+}
+
+type fakeNVML struct {
+	nvml.Interface
+	modes map[int]*nvml.ComputeMode
+}
+
+func (l *fakeNVML) Init() nvml.Return {
+	return nvml.SUCCESS
+}
+
+func (l *fakeNVML) Shutdown() nvml.Return {
+	return nvml.SUCCESS
+}
+
+func (l *fakeNVML) DeviceGetHandleByIndex(index int) (nvml.Device, nvml.Return) {
+	mode, exists := l.modes[index]
+	if !exists {
+		return nil, nvml.ERROR_NOT_FOUND
+	}
+	return fakeComputeModeDevice{mode: mode}, nvml.SUCCESS
+}
+
+func TestDaemonApplyLimits(t *testing.T) {
+	control := &fakeControl{}
+	d := NewDaemon(&rm.ResourceManagerMock{
+		DevicesFunc: func() rm.Devices {
+			return rm.Devices{
+				"GPU-0::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::0"}, Index: "0", TotalMemory: 4 * 1024 * 1024 * 1024},
+				"GPU-0::1": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::1"}, Index: "0", TotalMemory: 4 * 1024 * 1024 * 1024},
+			}
+		},
+	}, "")
+	d.control = control
+
+	require.NoError(t, d.applyLimits())
+	require.Equal(t, []string{
+		"set_default_device_pinned_mem_limit 0 2048M",
+		"set_default_active_thread_percentage 50",
+	}, control.commands)
+
+	control.err = errors.New("broken pipe")
+	require.Error(t, d.applyLimits())
+}
+
+func TestDaemonSetComputeMode(t *testing.T) {
+	var mode0, mode1 nvml.ComputeMode
+	d := NewMigDaemon(&rm.ResourceManagerMock{
+		DevicesFunc: func() rm.Devices {
+			return rm.Devices{
+				"MIG-0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-0::0"}, Index: "1:0"},
+				"MIG-0::1": &rm.Device{Device: pluginapi.Device{ID: "MIG-0::1"}, Index: "1:0"},
+				"MIG-1::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-1::0"}, Index: "0:0"},
+			}
+		},
+	}, "", "MIG-0")
+	d.nvmllib = &fakeNVML{modes: map[int]*nvml.ComputeMode{0: &mode0, 1: &mode1}}
+
+	require.NoError(t, d.acquireComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_DEFAULT, mode0)
+	require.Equal(t, nvml.COMPUTEMODE_EXCLUSIVE_PROCESS, mode1)
+
+	require.NoError(t, d.releaseComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_DEFAULT, mode1)
+
+	d.nvmllib = &fakeNVML{}
+	require.Error(t, d.acquireComputeMode())
+}
+
+func TestMigDaemonsShareComputeMode(t *testing.T) {
+	var mode0 nvml.ComputeMode
+	nvmllib := &fakeNVML{modes: map[int]*nvml.ComputeMode{0: &mode0}}
+	computeModes := newComputeModeRefs()
+	devices := rm.Devices{
+		"MIG-0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-0::0"}, Index: "0:0"},
+		"MIG-1::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-1::0"}, Index: "0:1"},
+	}
+	newMigDaemon := func(uuid string) *Daemon {
+		d := NewMigDaemon(&rm.ResourceManagerMock{
+			DevicesFunc: func() rm.Devices { return devices },
+		}, "", uuid)
+		d.nvmllib = nvmllib
+		d.computeModes = computeModes
+		return d
+	}
+	d0 := newMigDaemon("MIG-0")
+	d1 := newMigDaemon("MIG-1")
+	unstarted := newMigDaemon("MIG-1")
+
+	require.NoError(t, d0.acquireComputeMode())
+	require.NoError(t, d1.acquireComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_EXCLUSIVE_PROCESS, mode0)
+
+	// Stopping a daemon that was not started does not affect the others.
+	require.NoError(t, unstarted.releaseComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_EXCLUSIVE_PROCESS, mode0)
+
+	require.NoError(t, d0.releaseComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_EXCLUSIVE_PROCESS, mode0)
+
+	require.NoError(t, d1.releaseComputeMode())
+	require.Equal(t, nvml.COMPUTEMODE_DEFAULT, mode0)
+}
@@ -18,6 +18,7 @@ package mps
 
 import (
 	"fmt"
+	"slices"
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
@@ -75,6 +76,8 @@ func (m *manager) Daemons() ([]*Daemon, error) {
 	if err != nil {
 		return nil, err
 	}
+	// The compute mode of a GPU is shared by all daemons for its devices.
+	computeModes := newComputeModeRefs()
 	var daemons []*Daemon
 	for _, resourceManager := range resourceManagers {
 		// We don't create daemons if there are no devices associated with the resource manager.
@@ -88,17 +91,29 @@ func (m *manager) Daemons() ([]*Daemon, error) {
 			klog.InfoS("Resource is not shared", "resource", "resource", resourceManager.Resource())
 			continue
 		}
-		// Check if MIG devices are included.
+		var migUUIDs []string
 		for _, rmDevice := range resourceManager.Devices() {
-			if rmDevice.IsMigDevice() {
-				klog.Warning("MPS sharing is not supported for MIG devices; skipping daemon creation")
-				continue
-			}
 			if err := (*mpsDevice)(rmDevice).assertReplicas(); err != nil {
 				return nil, fmt.Errorf("invalid MPS configuration: %w", err)
 			}
+			if rmDevice.IsMigDevice() && !slices.Contains(migUUIDs, rmDevice.GetUUID()) {
+				migUUIDs = append(migUUIDs, rmDevice.GetUUID())
+			}
+		}
+		// A separate daemon is required for each MIG device.
+		if len(migUUIDs) > 0 {
+			slices.Sort(migUUIDs)
+			for _, uuid := range migUUIDs {
+				daemon := NewMigDaemon(resourceManager, ContainerRoot, uuid)
+				daemon.nvmllib = m.nvmllib
+				daemon.computeModes = computeModes
+				daemons = append(daemons, daemon)
+			}
+			continue
 		}
 		daemon := NewDaemon(resourceManager, ContainerRoot)
+		daemon.nvmllib = m.nvmllib
+		daemon.computeModes = computeModes
 		daemons = append(daemons, daemon)
 	}
 
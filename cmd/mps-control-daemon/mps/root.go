@@ -18,6 +18,7 @@ package mps
 
 import (
 	"path/filepath"
+	"strings"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
 )
@@ -41,6 +42,18 @@ func (r Root) PipeDir(resourceName spec.ResourceName) string {
 	return r.Path(string(resourceName), "pipe")
 }
 
+// MigLogDir returns the log dir for the daemon of the specified MIG device of
+// a resource.
+func (r Root) MigLogDir(resourceName spec.ResourceName, uuid string) string {
+	return r.Path(string(resourceName), migDeviceDir(uuid), "log")
+}
+
+// MigPipeDir returns the pipe dir for the daemon of the specified MIG device of
+// a resource.
+func (r Root) MigPipeDir(resourceName spec.ResourceName, uuid string) string {
+	return r.Path(string(resourceName), migDeviceDir(uuid), "pipe")
+}
+
 // ShmDir returns the shm dir associated with the root.
 // Note that the shm dir is the same for all resources.
 func (r Root) ShmDir(resourceName spec.ResourceName) string {
@@ -52,6 +65,18 @@ func (r Root) startedFile(resourceName spec.ResourceName) string {
 	return r.Path(string(resourceName), ".started")
 }
 
+// migStartedFile returns the .started file name for the daemon of the
+// specified MIG device of a resource.
+func (r Root) migStartedFile(resourceName spec.ResourceName, uuid string) string {
+	return r.Path(string(resourceName), migDeviceDir(uuid), ".started")
+}
+
+// migDeviceDir returns the directory name used for a MIG device. MIG UUIDs of
+// the form MIG-GPU-<uuid>/<gi>/<ci> contain path separators which are replaced.
+func migDeviceDir(uuid string) string {
+	return strings.ReplaceAll(uuid, "/", "_")
+}
+
 // Path returns a path relative to the MPS root.
 func (r Root) Path(parts ...string) string {
 	pathparts := append([]string{string(r)}, parts...)
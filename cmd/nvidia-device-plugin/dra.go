@@ -0,0 +1,121 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
+	"github.com/NVIDIA/go-nvlib/pkg/nvlib/info"
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/cdi"
+	"github.com/NVIDIA/k8s-device-plugin/internal/dra"
+	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/plugin"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+const (
+	// modeDevicePlugin serves the devices through the device plugin API.
+	modeDevicePlugin = "device-plugin"
+	// modeDRA serves the devices as a DRA kubelet plugin.
+	modeDRA = "dra"
+)
+
+// validateDRAFlags checks whether the specified config can be used in the
+// DRA mode.
+func validateDRAFlags(config *spec.Config, o *options) error {
+	if o.nodeName == "" {
+		return fmt.Errorf("the %v mode requires --node-name to be specified", modeDRA)
+	}
+	deviceListStrategies, err := spec.NewDeviceListStrategies(*config.Flags.Plugin.DeviceListStrategy)
+	if err != nil {
+		return fmt.Errorf("invalid --device-list-strategy option: %v", err)
+	}
+	if !deviceListStrategies.AnyCDIEnabled() {
+		return fmt.Errorf("the %v mode requires a CDI --device-list-strategy", modeDRA)
+	}
+	if config.Sharing.SharingStrategy() != spec.SharingStrategyNone {
+		return fmt.Errorf("sharing is not supported in the %v mode", modeDRA)
+	}
+	return nil
+}
+
+// getDRAPlugin returns a DRA kubelet plugin for the devices of the node.
+func getDRAPlugin(ctx context.Context, infolib info.Interface, nvmllib nvml.Interface, devicelib device.Interface, config *spec.Config, o *options, cdiHandler cdi.Interface, imexChannels imex.Channels, deviceDiscoveryStrategy string) (plugin.Interface, error) {
+	resourceManagers, err := plugin.NewResourceManagers(infolib, nvmllib, devicelib,
+		plugin.WithConfig(config),
+		plugin.WithFailOnInitError(*config.Flags.FailOnInitError),
+		plugin.WithDeviceDiscoveryStrategy(deviceDiscoveryStrategy),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create resource managers: %w", err)
+	}
+
+	csconfig, err := o.kubeClientConfig.NewClientSetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client configuration: %w", err)
+	}
+	client, err := kubernetes.NewForConfig(csconfig)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create client: %w", err)
+	}
+
+	p, err := dra.New(ctx, resourceManagers,
+		dra.WithClient(client),
+		dra.WithNodeName(o.nodeName),
+		dra.WithCDIHandler(cdiHandler),
+		dra.WithDeviceIDStrategy(*config.Flags.Plugin.DeviceIDStrategy),
+		dra.WithImexChannels(imexChannels),
+		dra.WithDeviceAttributes(getDeviceAttributes(infolib, nvmllib, devicelib, config)),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create DRA plugin: %w", err)
+	}
+	return p, nil
+}
+
+// getDeviceAttributes returns the additional attributes of the devices that
+// are also used for node labels. Since these attributes are optional, errors
+// are logged and no attributes are returned.
+func getDeviceAttributes(infolib info.Interface, nvmllib nvml.Interface, devicelib device.Interface, config *spec.Config) map[string]*lm.DeviceAttributes {
+	manager, err := resource.NewManager(infolib, nvmllib, devicelib, config)
+	if err != nil {
+		klog.Warningf("Unable to create resource manager for device attributes: %v", err)
+		return nil
+	}
+	if err := manager.Init(); err != nil {
+		klog.Warningf("Unable to initialize resource manager for device attributes: %v", err)
+		return nil
+	}
+	defer func() {
+		_ = manager.Shutdown()
+	}()
+
+	attributes, err := lm.GetDeviceAttributes(manager)
+	if err != nil {
+		klog.Warningf("Unable to get device attributes: %v", err)
+		return nil
+	}
+	return attributes
+}
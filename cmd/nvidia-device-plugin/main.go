@@ -17,11 +17,13 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"syscall"
 	"time"
 
@@ -34,18 +36,34 @@ import (
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/api/config/versioned"
+	"github.com/NVIDIA/k8s-device-plugin/internal/convert"
+	"github.com/NVIDIA/k8s-device-plugin/internal/flags"
 	"github.com/NVIDIA/k8s-device-plugin/internal/info"
+	"github.com/NVIDIA/k8s-device-plugin/internal/metrics"
 	"github.com/NVIDIA/k8s-device-plugin/internal/plugin"
 	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/status"
+	"github.com/NVIDIA/k8s-device-plugin/internal/validate"
 	"github.com/NVIDIA/k8s-device-plugin/internal/watch"
 )
 
 type options struct {
 	flags           []cli.Flag
 	configFile      string
+	statusFile      string
 	kubeletSocket   string
 	cdiFeatureFlags cli.StringSlice
 	cdiDisableHooks cli.StringSlice
+	metricsAddress  string
+
+	mode             string
+	nodeName         string
+	kubeClientConfig flags.KubeClientConfig
+
+	// configHash is the hash of the contents of the config file that were
+	// last loaded. This is reported in the status file.
+	configHash string
 }
 
 func main() {
@@ -57,6 +75,10 @@ func main() {
 	c.Action = func(ctx *cli.Context) error {
 		return start(ctx, o)
 	}
+	c.Commands = []*cli.Command{
+		validate.NewCommand("flags.gfd"),
+		convert.NewCommand(),
+	}
 
 	c.Flags = []cli.Flag{
 		&cli.StringFlag{
@@ -148,6 +170,24 @@ func main() {
 			Usage:   "the allocation policy for replicated and MIG resources:\n\t\t[distributed | packed]",
 			EnvVars: []string{"SHARED_DEVICES_ALLOCATION_POLICY"},
 		},
+		&cli.StringFlag{
+			Name:    "health-recovery-policy",
+			Value:   spec.HealthRecoveryPolicyNone,
+			Usage:   "the policy used to return devices marked unhealthy to a healthy state:\n\t\t[none | quiet-period | nvml-probe]",
+			EnvVars: []string{"HEALTH_RECOVERY_POLICY"},
+		},
+		&cli.StringFlag{
+			Name:    "cc-mode",
+			Value:   spec.CCModeAny,
+			Usage:   "the confidential computing mode that the system must be in for GPUs to be advertised:\n\t\t[any | on | off | devtools]",
+			EnvVars: []string{"CC_MODE"},
+		},
+		&cli.GenericFlag{
+			Name:    "health-recovery-quiet-period",
+			Value:   spec.NewDurationValue(spec.DefaultHealthRecoveryQuietPeriod),
+			Usage:   "the period without new health events after which an unhealthy device is considered for recovery",
+			EnvVars: []string{"HEALTH_RECOVERY_QUIET_PERIOD"},
+		},
 		&cli.StringFlag{
 			Name:    "device-discovery-strategy",
 			Value:   "auto",
@@ -178,6 +218,12 @@ func main() {
 			Destination: &o.configFile,
 			EnvVars:     []string{"CONFIG_FILE"},
 		},
+		&cli.StringFlag{
+			Name:        "status-file",
+			Usage:       "the path to a file in which the plugin reports whether it applied the contents of the config file; this allows the config manager to roll back a config that is not applied",
+			Destination: &o.statusFile,
+			EnvVars:     []string{"STATUS_FILE"},
+		},
 		&cli.StringSliceFlag{
 			Name:        "cdi-feature-flags",
 			Usage:       "A set of feature flags to be passed to the CDI spec generation logic",
@@ -190,7 +236,27 @@ func main() {
 			EnvVars:     []string{"CDI_DISABLE_HOOKS"},
 			Destination: &o.cdiDisableHooks,
 		},
+		&cli.StringFlag{
+			Name:        "metrics-address",
+			Usage:       "the address (e.g. ':9400') on which Prometheus metrics are served at /metrics; if this is empty, metrics are not served",
+			Destination: &o.metricsAddress,
+			EnvVars:     []string{"METRICS_ADDRESS"},
+		},
+		&cli.StringFlag{
+			Name:        "mode",
+			Value:       modeDevicePlugin,
+			Usage:       "the mode in which devices are served to the kubelet:\n\t\t[device-plugin | dra]",
+			Destination: &o.mode,
+			EnvVars:     []string{"MODE"},
+		},
+		&cli.StringFlag{
+			Name:        "node-name",
+			Usage:       "the name of the node on which the plugin runs; required in the dra mode to publish the ResourceSlice of the node",
+			Destination: &o.nodeName,
+			EnvVars:     []string{"NODE_NAME"},
+		},
 	}
+	c.Flags = append(c.Flags, o.kubeClientConfig.Flags()...)
 	o.flags = c.Flags
 
 	err := c.Run(os.Args)
@@ -224,15 +290,45 @@ func validateFlags(infolib nvinfo.Interface, config *spec.Config) error {
 		}
 	}
 
-	if config.Sharing.SharingStrategy() == spec.SharingStrategyMPS {
-		if *config.Flags.MigStrategy == spec.MigStrategyMixed {
-			return fmt.Errorf("using --mig-strategy=mixed is not supported with MPS")
+	if config.Flags.Plugin.HealthRecoveryPolicy != nil {
+		switch *config.Flags.Plugin.HealthRecoveryPolicy {
+		case spec.HealthRecoveryPolicyNone:
+		case spec.HealthRecoveryPolicyQuietPeriod:
+		case spec.HealthRecoveryPolicyNVMLProbe:
+		default:
+			return fmt.Errorf("invalid --health-recovery-policy option: %s", *config.Flags.Plugin.HealthRecoveryPolicy)
+		}
+	}
+
+	if config.Flags.Plugin.CCMode != nil {
+		switch *config.Flags.Plugin.CCMode {
+		case spec.CCModeAny:
+		case spec.CCModeOn:
+		case spec.CCModeOff:
+		case spec.CCModeDevtools:
+		default:
+			return fmt.Errorf("invalid --cc-mode option: %s", *config.Flags.Plugin.CCMode)
 		}
+	}
+
+	if config.Flags.Plugin.HealthRecoveryQuietPeriod != nil && *config.Flags.Plugin.HealthRecoveryQuietPeriod < 0 {
+		return fmt.Errorf("invalid --health-recovery-quiet-period option: %v", *config.Flags.Plugin.HealthRecoveryQuietPeriod)
+	}
+
+	if config.Sharing.SharingStrategy() == spec.SharingStrategyMPS {
 		if config.Flags.MpsRoot == nil || *config.Flags.MpsRoot == "" {
 			return fmt.Errorf("using MPS requires --mps-root to be specified")
 		}
 	}
 
+	sharing := spec.ValidateSharing(config)
+	for _, w := range sharing.Warnings {
+		klog.Warningf("Sharing config: %v", w)
+	}
+	if err := sharing.Errors.ToAggregate(); err != nil {
+		return fmt.Errorf("invalid sharing config: %w", err)
+	}
+
 	switch *config.Flags.DeviceDiscoveryStrategy {
 	case "auto":
 	case "nvml":
@@ -256,31 +352,57 @@ func validateFlags(infolib nvinfo.Interface, config *spec.Config) error {
 	return nil
 }
 
-func loadConfig(c *cli.Context, flags []cli.Flag) (*spec.Config, error) {
-	config, err := spec.NewConfig(c, flags)
+// loadConfig loads the config and returns it together with the hash of the
+// contents of the config file that it was loaded from.
+func loadConfig(c *cli.Context, flags []cli.Flag) (*spec.Config, string, error) {
+	config, contents, err := versioned.NewConfigWithContents(c, flags)
+	hash := status.HashContents(contents)
 	if err != nil {
-		return nil, fmt.Errorf("unable to finalize config: %v", err)
+		return nil, hash, fmt.Errorf("unable to finalize config: %v", err)
 	}
 	config.Flags.GFD = nil
-	return config, nil
+	return config, hash, nil
 }
 
 func start(c *cli.Context, o *options) error {
-	klog.InfoS(fmt.Sprintf("Starting %s", c.App.Name), "version", c.App.Version)
+	klog.InfoS(fmt.Sprintf("Starting %s", c.App.Name), "version", c.App.Version, "mode", o.mode)
 
-	kubeletSocketDir := filepath.Dir(o.kubeletSocket)
-	klog.Infof("Starting FS watcher for %v", kubeletSocketDir)
-	watcher, err := watch.Files(kubeletSocketDir)
+	switch o.mode {
+	case modeDevicePlugin:
+	case modeDRA:
+	default:
+		return fmt.Errorf("invalid --mode option: %v", o.mode)
+	}
+
+	// The directory of the config file is watched rather than the file itself
+	// since config files are typically replaced instead of being modified.
+	watchedDirs := []string{filepath.Dir(o.kubeletSocket)}
+	if o.configFile != "" {
+		watchedDirs = append(watchedDirs, filepath.Dir(o.configFile))
+	}
+	klog.Infof("Starting FS watcher for %v", watchedDirs)
+	watcher, err := watch.Files(watchedDirs...)
 	if err != nil {
-		return fmt.Errorf("failed to create FS watcher for %s: %v", pluginapi.DevicePluginPath, err)
+		return fmt.Errorf("failed to create FS watcher for %v: %v", watchedDirs, err)
 	}
 	defer watcher.Close()
 
 	klog.Info("Starting OS watcher.")
 	sigs := watch.Signals(syscall.SIGHUP, syscall.SIGINT, syscall.SIGTERM, syscall.SIGQUIT)
 
+	if o.metricsAddress != "" {
+		metricsServer, err := metrics.Serve(metrics.DevicePlugin, o.metricsAddress)
+		if err != nil {
+			return fmt.Errorf("failed to start metrics server on %v: %w", o.metricsAddress, err)
+		}
+		defer func() {
+			_ = metricsServer.Shutdown(context.Background())
+		}()
+	}
+
 	var started bool
 	var restartTimeout <-chan time.Time
+	var reloadTimeout <-chan time.Time
 	var plugins []plugin.Interface
 restart:
 	// If we are restarting, stop plugins from previous run.
@@ -289,6 +411,8 @@ restart:
 		if err != nil {
 			return fmt.Errorf("error stopping plugins from previous run: %v", err)
 		}
+		// The replicas are recorded again as the plugins are started.
+		metrics.Replicas.Reset()
 	}
 
 	klog.Info("Starting Plugins.")
@@ -311,27 +435,45 @@ restart:
 		case <-restartTimeout:
 			goto restart
 
+		// If the reload timeout has expired, then reload the config and
+		// restart the plugins whose resources are affected by the change.
+		case <-reloadTimeout:
+			plugins, restartPlugins, err = reloadPlugins(c, o, plugins)
+			if err != nil {
+				return fmt.Errorf("error reloading plugins: %v", err)
+			}
+			if restartPlugins {
+				klog.Infof("Failed to start one or more plugins. Retrying in 30s...")
+				restartTimeout = time.After(30 * time.Second)
+			}
+
 		// Detect a kubelet restart by watching for a newly created
 		// 'pluginapi.KubeletSocket' file. When this occurs, restart this loop,
-		// restarting all of the plugins in the process.
+		// restarting all of the plugins in the process. Changes to the
+		// config file trigger a reload once no further changes occur for a
+		// second.
 		case event := <-watcher.Events:
 			if o.kubeletSocket != "" && event.Name == o.kubeletSocket && event.Op&fsnotify.Create == fsnotify.Create {
 				klog.Infof("inotify: %s created, restarting.", o.kubeletSocket)
 				goto restart
 			}
+			if watch.IsConfigFileEvent(o.configFile, event) {
+				klog.Infof("inotify: %s changed, reloading.", o.configFile)
+				reloadTimeout = time.After(time.Second)
+			}
 
 		// Watch for any other fs errors and log them.
 		case err := <-watcher.Errors:
 			klog.Infof("inotify: %s", err)
 
-		// Watch for any signals from the OS. On SIGHUP, restart this loop,
-		// restarting all of the plugins in the process. On all other
-		// signals, exit the loop and exit the program.
+		// Watch for any signals from the OS. On SIGHUP, reload the config and
+		// restart the plugins whose resources are affected by the change. On
+		// all other signals, exit the loop and exit the program.
 		case s := <-sigs:
 			switch s {
 			case syscall.SIGHUP:
-				klog.Info("Received SIGHUP, restarting.")
-				goto restart
+				klog.Info("Received SIGHUP, reloading.")
+				reloadTimeout = time.After(0)
 			default:
 				klog.Infof("Received signal \"%v\", shutting down.", s)
 				goto exit
@@ -347,14 +489,117 @@ exit:
 }
 
 func startPlugins(c *cli.Context, o *options) ([]plugin.Interface, bool, error) {
+	plugins, err := loadPlugins(c, o)
+	if err != nil {
+		o.writeStatus(err)
+		return nil, false, err
+	}
+
+	// Loop through all plugins, starting them if they have any devices
+	// to serve. If even one plugin fails to start properly, try
+	// starting them all again.
+	started := 0
+	for _, p := range plugins {
+		// Just continue if there are no devices to serve for plugin p.
+		if len(p.Devices()) == 0 {
+			continue
+		}
+
+		// Start the gRPC server for plugin p and connect it with the kubelet.
+		if err := p.Start(o.kubeletSocket); err != nil {
+			klog.Errorf("Failed to start plugin: %v", err)
+			o.writeStatus(err)
+			return plugins, true, nil
+		}
+		started++
+	}
+
+	if started == 0 {
+		klog.Info("No devices found. Waiting indefinitely.")
+	}
+
+	o.writeStatus(nil)
+	return plugins, false, nil
+}
+
+// reloadPlugins loads the config again and replaces the running plugins whose
+// resource, devices or options have changed. The remaining plugins keep
+// serving their resources. If the config cannot be loaded, the running plugins
+// are kept as they are.
+func reloadPlugins(c *cli.Context, o *options, running []plugin.Interface) ([]plugin.Interface, bool, error) {
+	klog.Info("Reloading plugins.")
+	reloaded, err := loadPlugins(c, o)
+	if err != nil {
+		klog.Errorf("Failed to reload plugins; keeping the running plugins: %v", err)
+		o.writeStatus(err)
+		return running, false, nil
+	}
+
+	var plugins []plugin.Interface
+	kept := make(map[plugin.Interface]bool)
+	for _, p := range reloaded {
+		i := slices.IndexFunc(running, func(r plugin.Interface) bool {
+			return !kept[r] && plugin.Unchanged(r, p)
+		})
+		if i < 0 {
+			plugins = append(plugins, p)
+			continue
+		}
+		kept[running[i]] = true
+		plugins = append(plugins, running[i])
+	}
+
+	// The changed plugins are stopped before their replacements are started
+	// since these serve their resources on the same sockets.
+	var stopped []plugin.Interface
+	for _, r := range running {
+		if !kept[r] {
+			stopped = append(stopped, r)
+		}
+	}
+	if err := stopPlugins(stopped); err != nil {
+		return nil, false, fmt.Errorf("error stopping changed plugins: %v", err)
+	}
+
+	restarted := 0
+	for _, p := range plugins {
+		if kept[p] || len(p.Devices()) == 0 {
+			continue
+		}
+		if err := p.Start(o.kubeletSocket); err != nil {
+			klog.Errorf("Failed to start plugin: %v", err)
+			o.writeStatus(err)
+			return plugins, true, nil
+		}
+		restarted++
+	}
+	klog.Infof("Reloaded plugins: %d unchanged, %d stopped, %d started.", len(kept), len(stopped), restarted)
+
+	o.writeStatus(nil)
+	return plugins, false, nil
+}
+
+// writeStatus reports in the status file, if any, whether the contents of the
+// config file that were last loaded were applied.
+func (o *options) writeStatus(err error) {
+	if o.statusFile == "" {
+		return
+	}
+	if err := status.Write(o.statusFile, o.configHash, err); err != nil {
+		klog.Warningf("Failed to write status file %v: %v", o.statusFile, err)
+	}
+}
+
+// loadPlugins loads the config and constructs the plugins for it without
+// starting them.
+func loadPlugins(c *cli.Context, o *options) ([]plugin.Interface, error) {
 	// Load the configuration file
 	klog.Info("Loading configuration.")
-	config, err := loadConfig(c, o.flags)
+	config, hash, err := loadConfig(c, o.flags)
+	o.configHash = hash
 	if err != nil {
-		return nil, false, fmt.Errorf("unable to load config: %v", err)
+		return nil, fmt.Errorf("unable to load config: %v", err)
 	}
-	spec.DisableResourceNamingInConfig(config)
-
 	driverRoot := root(*config.Flags.Plugin.ContainerDriverRoot)
 	// We construct an NVML library specifying the path to libnvidia-ml.so.1
 	// explicitly so that we don't have to rely on the library path.
@@ -370,20 +615,25 @@ func startPlugins(c *cli.Context, o *options) ([]plugin.Interface, bool, error)
 
 	err = validateFlags(infolib, config)
 	if err != nil {
-		return nil, false, fmt.Errorf("unable to validate flags: %v", err)
+		return nil, fmt.Errorf("unable to validate flags: %v", err)
+	}
+	if o.mode == modeDRA {
+		if err := validateDRAFlags(config, o); err != nil {
+			return nil, fmt.Errorf("unable to validate flags: %v", err)
+		}
 	}
 
 	// Update the configuration file with default resources.
 	klog.Info("Updating config with default resource matching patterns.")
 	err = rm.AddDefaultResourcesToConfig(infolib, nvmllib, devicelib, config)
 	if err != nil {
-		return nil, false, fmt.Errorf("unable to add default resources to config: %v", err)
+		return nil, fmt.Errorf("unable to add default resources to config: %v", err)
 	}
 
 	// Print the config to the output.
 	configJSON, err := json.MarshalIndent(config, "", "  ")
 	if err != nil {
-		return nil, false, fmt.Errorf("failed to marshal config to JSON: %v", err)
+		return nil, fmt.Errorf("failed to marshal config to JSON: %v", err)
 	}
 	klog.Infof("\nRunning with config:\n%v", string(configJSON))
 
@@ -391,32 +641,18 @@ func startPlugins(c *cli.Context, o *options) ([]plugin.Interface, bool, error)
 	klog.Info("Retrieving plugins.")
 	plugins, err := GetPlugins(c.Context, infolib, nvmllib, devicelib, config, o)
 	if err != nil {
-		return nil, false, fmt.Errorf("error getting plugins: %v", err)
+		return nil, fmt.Errorf("error getting plugins: %v", err)
 	}
 
-	// Loop through all plugins, starting them if they have any devices
-	// to serve. If even one plugin fails to start properly, try
-	// starting them all again.
-	started := 0
-	for _, p := range plugins {
-		// Just continue if there are no devices to serve for plugin p.
-		if len(p.Devices()) == 0 {
-			continue
-		}
-
-		// Start the gRPC server for plugin p and connect it with the kubelet.
-		if err := p.Start(o.kubeletSocket); err != nil {
-			klog.Errorf("Failed to start plugin: %v", err)
-			return plugins, true, nil
+	if o.mode == modeDevicePlugin {
+		// The sockets of previously served resources are removed in case
+		// resources were renamed since the plugin last shut down.
+		if err := plugin.RemoveStaleSockets(pluginapi.DevicePluginPath); err != nil {
+			klog.Warningf("Failed to remove stale plugin sockets: %v", err)
 		}
-		started++
 	}
 
-	if started == 0 {
-		klog.Info("No devices found. Waiting indefinitely.")
-	}
-
-	return plugins, false, nil
+	return plugins, nil
 }
 
 func stopPlugins(plugins []plugin.Interface) error {
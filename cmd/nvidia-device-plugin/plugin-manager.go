@@ -70,6 +70,17 @@ func GetPlugins(ctx context.Context, infolib info.Interface, nvmllib nvml.Interf
 		return nil, fmt.Errorf("unable to create cdi handler: %v", err)
 	}
 
+	if o.mode == modeDRA {
+		p, err := getDRAPlugin(ctx, infolib, nvmllib, devicelib, config, o, cdiHandler, imexChannels, resolvedStrategy)
+		if err != nil {
+			return nil, err
+		}
+		if err := cdiHandler.CreateSpecFile(); err != nil {
+			return nil, fmt.Errorf("unable to create cdi spec file: %v", err)
+		}
+		return []plugin.Interface{p}, nil
+	}
+
 	plugins, err := plugin.New(ctx, infolib, nvmllib, devicelib,
 		plugin.WithCDIHandler(cdiHandler),
 		plugin.WithConfig(config),
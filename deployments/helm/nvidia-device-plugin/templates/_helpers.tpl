@@ -220,7 +220,7 @@ Get the name of the default configuration
     {{- end -}}
   {{- end -}}
   {{- if has "single" .Values.config.fallbackStrategies -}}
-    {{- if eq (.Values.config.map | keys | len) 1 -}}
+    {{- if eq (omit .Values.config.map "product-configs" | keys | len) 1 -}}
       {{- $result = "true" -}}
     {{- end -}}
   {{- end -}}
@@ -0,0 +1,130 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package convert
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+	"sigs.k8s.io/yaml"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	v2 "github.com/NVIDIA/k8s-device-plugin/api/config/v2"
+)
+
+type command struct {
+	output  string
+	inPlace bool
+}
+
+// NewCommand constructs a convert-config command.
+func NewCommand() *cli.Command {
+	v := command{}
+
+	c := cli.Command{
+		Name:      "convert-config",
+		Usage:     "Convert a v1 config file to a v2 config file with all options set explicitly",
+		ArgsUsage: "CONFIG_FILE",
+		Action: func(c *cli.Context) error {
+			if c.Args().Len() != 1 {
+				return fmt.Errorf("exactly one config file must be specified")
+			}
+			return v.run(c.App.Writer, c.Args().First())
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.StringFlag{
+			Name:        "output",
+			Aliases:     []string{"o"},
+			Usage:       "the file to write the converted config to instead of stdout",
+			Destination: &v.output,
+		},
+		&cli.BoolFlag{
+			Name:        "in-place",
+			Usage:       "overwrite the specified config file with the converted config",
+			Destination: &v.inPlace,
+		},
+	}
+
+	return &c
+}
+
+// run converts the specified config file and writes the result to the
+// configured output.
+func (v command) run(w io.Writer, configFile string) error {
+	if v.inPlace && v.output != "" {
+		return fmt.Errorf("--in-place and --output are mutually exclusive")
+	}
+
+	contents, err := os.ReadFile(configFile)
+	if err != nil {
+		return fmt.Errorf("error reading config file: %w", err)
+	}
+
+	converted, err := Convert(contents)
+	if err != nil {
+		return fmt.Errorf("error converting %v: %w", configFile, err)
+	}
+
+	output := v.output
+	if v.inPlace {
+		output = configFile
+	}
+	if output == "" {
+		_, err := w.Write(converted)
+		return err
+	}
+	return os.WriteFile(output, converted, 0644)
+}
+
+// Convert converts the contents of a v1 config file to a v2 config file. A
+// v2 config file is returned with its defaults filled in.
+func Convert(contents []byte) ([]byte, error) {
+	var versioned struct {
+		Version string `json:"version"`
+	}
+	if err := yaml.Unmarshal(contents, &versioned); err != nil {
+		return nil, fmt.Errorf("unmarshal error: %w", err)
+	}
+
+	var config *v2.Config
+	switch versioned.Version {
+	case "", v1.Version:
+		var v1Config v1.Config
+		if err := yaml.UnmarshalStrict(contents, &v1Config); err != nil {
+			return nil, fmt.Errorf("unmarshal error: %w", err)
+		}
+		c, err := v2.ConvertFromV1(&v1Config)
+		if err != nil {
+			return nil, err
+		}
+		config = c
+	case v2.Version:
+		c, err := v2.Parse(contents)
+		if err != nil {
+			return nil, err
+		}
+		config = c
+	default:
+		return nil, fmt.Errorf("unknown version: %v", versioned.Version)
+	}
+
+	return yaml.Marshal(config)
+}
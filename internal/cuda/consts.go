@@ -87,9 +87,18 @@ const (
 type DeviceAttribute int32
 
 const (
+	COMPUTE_MODE             DeviceAttribute = 20
+	ECC_ENABLED              DeviceAttribute = 32
 	COMPUTE_CAPABILITY_MAJOR DeviceAttribute = 75
 	COMPUTE_CAPABILITY_MINOR DeviceAttribute = 76
 )
 
+// Constants for the values of the COMPUTE_MODE device attribute.
+const (
+	COMPUTEMODE_DEFAULT           = 0
+	COMPUTEMODE_PROHIBITED        = 2
+	COMPUTEMODE_EXCLUSIVE_PROCESS = 3
+)
+
 // Device represents a CUDA device handle
 type Device int32
@@ -0,0 +1,180 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package dra
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	resourceapi "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"k8s.io/utils/ptr"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+// allocatableDevice is a full GPU or MIG device that is published in the
+// ResourceSlice of the node.
+type allocatableDevice struct {
+	*rm.Device
+	// resourceName is the extended resource name that the device would be
+	// advertised as by the device plugin.
+	resourceName string
+	// attributes holds the additional attributes of the device. This is nil
+	// if these could not be determined.
+	attributes *lm.DeviceAttributes
+}
+
+// allocatableDevices maps the names of devices in the ResourceSlice to the
+// corresponding devices.
+type allocatableDevices map[string]*allocatableDevice
+
+// newAllocatableDevices constructs the allocatable devices for the specified
+// resource managers. Since DRA allocates devices exclusively, the devices of
+// the resource managers must not be shared.
+func newAllocatableDevices(resourceManagers []rm.ResourceManager, attributes map[string]*lm.DeviceAttributes) (allocatableDevices, error) {
+	devices := make(allocatableDevices)
+	for _, r := range resourceManagers {
+		for _, d := range r.Devices() {
+			if d.Replicas > 1 {
+				return nil, fmt.Errorf("device %v of resource %v is shared; sharing is not supported with DRA", d.GetUUID(), r.Resource())
+			}
+			device := &allocatableDevice{
+				Device:       d,
+				resourceName: string(r.Resource()),
+				attributes:   attributes[d.GetUUID()],
+			}
+			devices[device.name()] = device
+		}
+	}
+	if len(devices) > resourceapi.ResourceSliceMaxDevices {
+		return nil, fmt.Errorf("the number of devices (%d) exceeds the maximum number of devices in a ResourceSlice (%d)", len(devices), resourceapi.ResourceSliceMaxDevices)
+	}
+	return devices, nil
+}
+
+// name returns the name of the device in the ResourceSlice. This is derived
+// from the index of the device since the name must be a DNS label.
+func (d *allocatableDevice) name() string {
+	if d.IsMigDevice() {
+		return "mig-" + strings.ReplaceAll(d.Index, ":", "-")
+	}
+	return "gpu-" + d.Index
+}
+
+// deviceType returns the type of the device.
+func (d *allocatableDevice) deviceType() string {
+	if d.IsMigDevice() {
+		return "mig"
+	}
+	return "gpu"
+}
+
+// cdiDeviceID returns the ID of the device in the generated CDI spec for
+// the specified device ID strategy.
+func (d *allocatableDevice) cdiDeviceID(deviceIDStrategy string) string {
+	if deviceIDStrategy == spec.DeviceIDStrategyIndex {
+		return d.Index
+	}
+	return d.GetUUID()
+}
+
+// apiDevice returns the API representation of the device.
+func (d *allocatableDevice) apiDevice() resourceapi.Device {
+	attributes := map[resourceapi.QualifiedName]resourceapi.DeviceAttribute{
+		"type":         {StringValue: ptr.To(d.deviceType())},
+		"uuid":         {StringValue: ptr.To(d.GetUUID())},
+		"resourceName": {StringValue: ptr.To(d.resourceName)},
+	}
+	if !d.IsMigDevice() {
+		if index, err := strconv.ParseInt(d.Index, 10, 64); err == nil {
+			attributes["index"] = resourceapi.DeviceAttribute{IntValue: ptr.To(index)}
+		}
+	}
+	if version := d.cudaComputeCapability(); version != "" {
+		attributes["cudaComputeCapability"] = resourceapi.DeviceAttribute{VersionValue: ptr.To(version)}
+	}
+	if a := d.attributes; a != nil {
+		if a.Product != "" {
+			attributes["productName"] = resourceapi.DeviceAttribute{StringValue: ptr.To(a.Product)}
+		}
+		if a.Family != "" {
+			attributes["architecture"] = resourceapi.DeviceAttribute{StringValue: ptr.To(a.Family)}
+		}
+		if a.MigProfile != "" {
+			attributes["migProfile"] = resourceapi.DeviceAttribute{StringValue: ptr.To(a.MigProfile)}
+		}
+		if a.CliqueID != "" {
+			attributes["cliqueID"] = resourceapi.DeviceAttribute{StringValue: ptr.To(a.CliqueID)}
+		}
+	}
+
+	device := resourceapi.Device{
+		Name:       d.name(),
+		Attributes: attributes,
+	}
+	if memory := d.memory(); memory != 0 {
+		device.Capacity = map[resourceapi.QualifiedName]resourceapi.DeviceCapacity{
+			"memory": {Value: *resource.NewQuantity(int64(memory), resource.BinarySI)},
+		}
+	}
+	return device
+}
+
+// cudaComputeCapability returns the compute capability of the device as a
+// semantic version.
+func (d *allocatableDevice) cudaComputeCapability() string {
+	if a := d.attributes; a != nil && a.ComputeMajor != 0 {
+		return fmt.Sprintf("%d.%d.0", a.ComputeMajor, a.ComputeMinor)
+	}
+	if d.ComputeCapability == "" {
+		return ""
+	}
+	return d.ComputeCapability + ".0"
+}
+
+// memory returns the total memory of the device in bytes.
+func (d *allocatableDevice) memory() uint64 {
+	if d.TotalMemory != 0 {
+		return d.TotalMemory
+	}
+	if d.attributes != nil {
+		return d.attributes.MemoryMiB * 1024 * 1024
+	}
+	return 0
+}
+
+// apiDevices returns the API representation of the healthy devices ordered
+// by name.
+func (ds allocatableDevices) apiDevices() []resourceapi.Device {
+	var devices []resourceapi.Device
+	for _, d := range ds {
+		if d.Health != pluginapi.Healthy {
+			continue
+		}
+		devices = append(devices, d.apiDevice())
+	}
+	sort.Slice(devices, func(i, j int) bool {
+		return devices[i].Name < devices[j].Name
+	})
+	return devices
+}
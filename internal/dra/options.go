@@ -0,0 +1,90 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package dra
+
+import (
+	"k8s.io/client-go/kubernetes"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/cdi"
+	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+)
+
+// Option is a function that configures a DRA kubelet plugin.
+type Option func(*Plugin)
+
+// WithClient sets the Kubernetes client used to publish ResourceSlices and
+// to get ResourceClaims.
+func WithClient(client kubernetes.Interface) Option {
+	return func(p *Plugin) {
+		p.client = client
+	}
+}
+
+// WithNodeName sets the name of the node on which the plugin runs.
+func WithNodeName(nodeName string) Option {
+	return func(p *Plugin) {
+		p.nodeName = nodeName
+	}
+}
+
+// WithDriverName sets the name of the DRA driver.
+func WithDriverName(driverName string) Option {
+	return func(p *Plugin) {
+		p.driverName = driverName
+	}
+}
+
+// WithCDIHandler sets the CDI handler used to construct CDI device names.
+func WithCDIHandler(handler cdi.Interface) Option {
+	return func(p *Plugin) {
+		p.cdiHandler = handler
+	}
+}
+
+// WithDeviceIDStrategy sets the device ID strategy that the CDI spec was
+// generated with.
+func WithDeviceIDStrategy(deviceIDStrategy string) Option {
+	return func(p *Plugin) {
+		p.deviceIDStrategy = deviceIDStrategy
+	}
+}
+
+// WithImexChannels sets the IMEX channels that are injected with the
+// prepared devices.
+func WithImexChannels(imexChannels imex.Channels) Option {
+	return func(p *Plugin) {
+		p.imexChannels = imexChannels
+	}
+}
+
+// WithDeviceAttributes sets the additional attributes of the devices keyed
+// by device UUID.
+func WithDeviceAttributes(attributes map[string]*lm.DeviceAttributes) Option {
+	return func(p *Plugin) {
+		p.attributes = attributes
+	}
+}
+
+// WithKubeletPluginDirectories sets the directory in which the socket of the
+// plugin is created and the kubelet plugin registry directory.
+func WithKubeletPluginDirectories(pluginsDir string, registryDir string) Option {
+	return func(p *Plugin) {
+		p.pluginsDir = pluginsDir
+		p.registryDir = registryDir
+	}
+}
@@ -0,0 +1,410 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package dra
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/klog/v2"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	drapb "k8s.io/kubelet/pkg/apis/dra/v1beta1"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/cdi"
+	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+const (
+	// DefaultDriverName is the default name of the DRA driver.
+	DefaultDriverName = "gpu.nvidia.com"
+	// DefaultKubeletPluginsDir is the default directory in which DRA kubelet
+	// plugins create their sockets.
+	DefaultKubeletPluginsDir = "/var/lib/kubelet/plugins"
+	// DefaultKubeletRegistryDir is the default kubelet plugin registry directory.
+	DefaultKubeletRegistryDir = "/var/lib/kubelet/plugins_registry"
+
+	draSocketName = "dra.sock"
+)
+
+// publishRetryInterval is the interval at which publishing the ResourceSlice
+// is retried after a health change if it failed.
+var publishRetryInterval = 10 * time.Second
+
+// Plugin is a DRA kubelet plugin. It publishes the GPUs and MIG devices of
+// the node in a ResourceSlice and prepares the devices allocated to
+// ResourceClaims by returning the names of their CDI devices.
+type Plugin struct {
+	drapb.UnimplementedDRAPluginServer
+
+	ctx    context.Context
+	client kubernetes.Interface
+
+	driverName  string
+	nodeName    string
+	pluginsDir  string
+	registryDir string
+
+	cdiHandler       cdi.Interface
+	deviceIDStrategy string
+	imexChannels     imex.Channels
+
+	resourceManagers []rm.ResourceManager
+	attributes       map[string]*lm.DeviceAttributes
+	devices          allocatableDevices
+
+	mu sync.Mutex
+	// prepared holds the prepared devices keyed by claim UID.
+	prepared map[string][]*drapb.Device
+
+	server *grpc.Server
+	health chan *rm.HealthEvent
+	stop   chan interface{}
+}
+
+var _ drapb.DRAPluginServer = (*Plugin)(nil)
+
+// New creates a DRA kubelet plugin for the devices of the specified resource
+// managers.
+func New(ctx context.Context, resourceManagers []rm.ResourceManager, opts ...Option) (*Plugin, error) {
+	p := &Plugin{
+		ctx:              ctx,
+		driverName:       DefaultDriverName,
+		pluginsDir:       DefaultKubeletPluginsDir,
+		registryDir:      DefaultKubeletRegistryDir,
+		deviceIDStrategy: spec.DeviceIDStrategyUUID,
+		resourceManagers: resourceManagers,
+		prepared:         make(map[string][]*drapb.Device),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	if p.client == nil {
+		return nil, fmt.Errorf("a Kubernetes client is required")
+	}
+	if p.nodeName == "" {
+		return nil, fmt.Errorf("a node name is required")
+	}
+	if p.cdiHandler == nil {
+		return nil, fmt.Errorf("a CDI handler is required")
+	}
+
+	devices, err := newAllocatableDevices(resourceManagers, p.attributes)
+	if err != nil {
+		return nil, err
+	}
+	p.devices = devices
+
+	return p, nil
+}
+
+// Devices returns the full set of devices associated with the plugin.
+func (p *Plugin) Devices() rm.Devices {
+	devices := make(rm.Devices)
+	for _, d := range p.devices {
+		devices[d.ID] = d.Device
+	}
+	return devices
+}
+
+// Start starts the gRPC server of the plugin, publishes the ResourceSlice of
+// the node, and starts the device healthchecks. The kubelet discovers the
+// plugin through its registration socket so the specified kubelet socket is
+// not used.
+func (p *Plugin) Start(string) error {
+	p.server = grpc.NewServer()
+	p.health = make(chan *rm.HealthEvent)
+	p.stop = make(chan interface{})
+
+	if err := p.serve(); err != nil {
+		return errors.Join(err, p.Stop())
+	}
+	klog.Infof("Starting to serve DRA driver '%s' on %s", p.driverName, p.draSocket())
+
+	if err := p.publishResources(); err != nil {
+		return errors.Join(fmt.Errorf("failed to publish ResourceSlice: %w", err), p.Stop())
+	}
+
+	for _, r := range p.resourceManagers {
+		go func() {
+			err := r.CheckHealth(p.stop, p.health)
+			if err != nil {
+				klog.Errorf("Failed to start health check for '%s': %v; continuing with health checks disabled", r.Resource(), err)
+			}
+		}()
+	}
+	go p.watchHealth(p.stop, p.health)
+
+	return nil
+}
+
+// Stop stops the gRPC server of the plugin and removes its sockets. The
+// ResourceSlice is kept so that it is not recreated after a restart.
+func (p *Plugin) Stop() error {
+	if p == nil || p.server == nil {
+		return nil
+	}
+	klog.Infof("Stopping to serve DRA driver '%s' on %s", p.driverName, p.draSocket())
+	p.server.Stop()
+	close(p.stop)
+	p.server = nil
+
+	var errs error
+	for _, socket := range []string{p.draSocket(), p.registrationSocket()} {
+		if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+			errs = errors.Join(errs, err)
+		}
+	}
+	return errs
+}
+
+// serve starts serving the DRA kubelet plugin API on the plugin socket and
+// the plugin registration API on the registration socket.
+func (p *Plugin) serve() error {
+	server := p.server
+	drapb.RegisterDRAPluginServer(server, p)
+	registerapi.RegisterRegistrationServer(server, newRegistrar(p.driverName, p.draSocket()))
+
+	for _, dir := range []string{filepath.Dir(p.draSocket()), p.registryDir} {
+		if err := os.MkdirAll(dir, 0750); err != nil {
+			return fmt.Errorf("failed to create directory for socket: %w", err)
+		}
+	}
+	for _, socket := range []string{p.draSocket(), p.registrationSocket()} {
+		_ = os.Remove(socket)
+		listener, err := net.Listen("unix", socket)
+		if err != nil {
+			return err
+		}
+		go func() {
+			if err := server.Serve(listener); err != nil {
+				klog.Errorf("GRPC server for DRA driver '%s' on %s failed: %v", p.driverName, socket, err)
+			}
+		}()
+	}
+	return nil
+}
+
+func (p *Plugin) draSocket() string {
+	return filepath.Join(p.pluginsDir, p.driverName, draSocketName)
+}
+
+func (p *Plugin) registrationSocket() string {
+	return filepath.Join(p.registryDir, p.driverName+"-reg.sock")
+}
+
+// resourceSliceName returns the name of the ResourceSlice of the node.
+func (p *Plugin) resourceSliceName() string {
+	return p.nodeName + "-" + p.driverName
+}
+
+// publishResources creates or updates the ResourceSlice of the node with the
+// healthy devices. The node is set as the owner of the ResourceSlice so that
+// it is removed with the node.
+func (p *Plugin) publishResources() error {
+	node, err := p.client.CoreV1().Nodes().Get(p.ctx, p.nodeName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get node %v: %w", p.nodeName, err)
+	}
+
+	p.mu.Lock()
+	devices := p.devices.apiDevices()
+	p.mu.Unlock()
+
+	slice := &resourceapi.ResourceSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: p.resourceSliceName(),
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: corev1.SchemeGroupVersion.String(),
+					Kind:       "Node",
+					Name:       node.Name,
+					UID:        node.UID,
+				},
+			},
+		},
+		Spec: resourceapi.ResourceSliceSpec{
+			Driver:   p.driverName,
+			NodeName: &p.nodeName,
+			Pool: resourceapi.ResourcePool{
+				Name:               p.nodeName,
+				ResourceSliceCount: 1,
+			},
+			Devices: devices,
+		},
+	}
+
+	slices := p.client.ResourceV1().ResourceSlices()
+	existing, err := slices.Get(p.ctx, slice.Name, metav1.GetOptions{})
+	switch {
+	case apierrors.IsNotFound(err):
+		_, err = slices.Create(p.ctx, slice, metav1.CreateOptions{})
+	case err == nil:
+		slice.ResourceVersion = existing.ResourceVersion
+		slice.Spec.Pool.Generation = existing.Spec.Pool.Generation + 1
+		_, err = slices.Update(p.ctx, slice, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	klog.Infof("Published ResourceSlice %s with %d devices", slice.Name, len(devices))
+	return nil
+}
+
+// watchHealth applies health events to the devices and republishes the
+// ResourceSlice when the health of a device changes. If publishing fails, it
+// is retried until it succeeds or the health changes again.
+func (p *Plugin) watchHealth(stop <-chan interface{}, health <-chan *rm.HealthEvent) {
+	var retry <-chan time.Time
+	for {
+		select {
+		case <-stop:
+			return
+		case <-retry:
+		case event := <-health:
+			if event == nil || event.Device == nil {
+				continue
+			}
+			p.mu.Lock()
+			changed := event.Device.Health != event.Health
+			event.Device.Health = event.Health
+			p.mu.Unlock()
+			if !changed {
+				continue
+			}
+			klog.Infof("Device %s marked %s (%s)", event.Device.ID, event.Health, event.Reason)
+		}
+
+		retry = nil
+		if err := p.publishResources(); err != nil {
+			klog.Errorf("Failed to publish ResourceSlice: %v; retrying in %v", err, publishRetryInterval)
+			retry = time.After(publishRetryInterval)
+		}
+	}
+}
+
+// NodePrepareResources prepares the devices allocated to the specified
+// claims. Errors are reported per claim.
+func (p *Plugin) NodePrepareResources(ctx context.Context, req *drapb.NodePrepareResourcesRequest) (*drapb.NodePrepareResourcesResponse, error) {
+	response := &drapb.NodePrepareResourcesResponse{
+		Claims: make(map[string]*drapb.NodePrepareResourceResponse),
+	}
+	for _, claim := range req.Claims {
+		devices, err := p.prepare(ctx, claim)
+		if err != nil {
+			klog.Errorf("Failed to prepare ResourceClaim %s/%s: %v", claim.Namespace, claim.Name, err)
+			response.Claims[claim.Uid] = &drapb.NodePrepareResourceResponse{Error: err.Error()}
+			continue
+		}
+		response.Claims[claim.Uid] = &drapb.NodePrepareResourceResponse{Devices: devices}
+	}
+	return response, nil
+}
+
+// NodeUnprepareResources unprepares the devices of the specified claims.
+func (p *Plugin) NodeUnprepareResources(ctx context.Context, req *drapb.NodeUnprepareResourcesRequest) (*drapb.NodeUnprepareResourcesResponse, error) {
+	response := &drapb.NodeUnprepareResourcesResponse{
+		Claims: make(map[string]*drapb.NodeUnprepareResourceResponse),
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, claim := range req.Claims {
+		delete(p.prepared, claim.Uid)
+		response.Claims[claim.Uid] = &drapb.NodeUnprepareResourceResponse{}
+	}
+	return response, nil
+}
+
+// prepare returns the prepared devices for the specified claim. The devices
+// are determined from the allocation result of the ResourceClaim.
+func (p *Plugin) prepare(ctx context.Context, claim *drapb.Claim) ([]*drapb.Device, error) {
+	p.mu.Lock()
+	devices, exists := p.prepared[claim.Uid]
+	p.mu.Unlock()
+	if exists {
+		return devices, nil
+	}
+
+	resourceClaim, err := p.client.ResourceV1().ResourceClaims(claim.Namespace).Get(ctx, claim.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ResourceClaim: %w", err)
+	}
+	if string(resourceClaim.UID) != claim.Uid {
+		return nil, fmt.Errorf("ResourceClaim has UID %v; expected %v", resourceClaim.UID, claim.Uid)
+	}
+	if resourceClaim.Status.Allocation == nil {
+		return nil, fmt.Errorf("ResourceClaim is not allocated")
+	}
+
+	for _, result := range resourceClaim.Status.Allocation.Devices.Results {
+		if result.Driver != p.driverName {
+			continue
+		}
+		if result.Pool != p.nodeName {
+			return nil, fmt.Errorf("device %v is allocated from pool %v; expected %v", result.Device, result.Pool, p.nodeName)
+		}
+		d, exists := p.devices[result.Device]
+		if !exists {
+			return nil, fmt.Errorf("unknown device %v", result.Device)
+		}
+		// The device may have become unhealthy after the claim was
+		// allocated and before the ResourceSlice was updated.
+		p.mu.Lock()
+		healthy := d.Health == pluginapi.Healthy
+		p.mu.Unlock()
+		if !healthy {
+			return nil, fmt.Errorf("device %v is unhealthy", result.Device)
+		}
+		devices = append(devices, &drapb.Device{
+			RequestNames: []string{result.Request},
+			PoolName:     result.Pool,
+			DeviceName:   result.Device,
+			CdiDeviceIds: []string{p.cdiHandler.QualifiedName("gpu", d.cdiDeviceID(p.deviceIDStrategy))},
+		})
+	}
+
+	// The devices that are required by all containers are only added to
+	// the first device to avoid duplicate CDI devices.
+	if len(devices) > 0 {
+		for _, channel := range p.imexChannels {
+			devices[0].CdiDeviceIds = append(devices[0].CdiDeviceIds, p.cdiHandler.QualifiedName("imex-channel", channel.ID))
+		}
+		devices[0].CdiDeviceIds = append(devices[0].CdiDeviceIds, p.cdiHandler.AdditionalDevices()...)
+	}
+
+	p.mu.Lock()
+	p.prepared[claim.Uid] = devices
+	p.mu.Unlock()
+
+	return devices, nil
+}
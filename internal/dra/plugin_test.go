@@ -0,0 +1,272 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package dra
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	corev1 "k8s.io/api/core/v1"
+	resourceapi "k8s.io/api/resource/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	drapb "k8s.io/kubelet/pkg/apis/dra/v1beta1"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+	"k8s.io/utils/ptr"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/cdi"
+	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/lm"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+const gib = 1024 * 1024 * 1024
+
+func newTestResourceManager(resource spec.ResourceName, devices ...*rm.Device) *rm.ResourceManagerMock {
+	ds := make(rm.Devices)
+	for _, d := range devices {
+		d.Health = pluginapi.Healthy
+		ds[d.ID] = d
+	}
+	return &rm.ResourceManagerMock{
+		ResourceFunc: func() spec.ResourceName { return resource },
+		DevicesFunc:  func() rm.Devices { return ds },
+	}
+}
+
+func newTestCDIHandler() *cdi.InterfaceMock {
+	return &cdi.InterfaceMock{
+		QualifiedNameFunc: func(class string, id string) string {
+			return "k8s.device-plugin.nvidia.com/" + class + "=" + id
+		},
+		AdditionalDevicesFunc: func() []string { return nil },
+	}
+}
+
+func TestNewAllocatableDevices(t *testing.T) {
+	gpus := newTestResourceManager("nvidia.com/gpu",
+		&rm.Device{Device: pluginapi.Device{ID: "GPU-0"}, Index: "0", TotalMemory: 40 * gib, ComputeCapability: "8.0"},
+	)
+	migs := newTestResourceManager("nvidia.com/mig-1g.5gb",
+		&rm.Device{Device: pluginapi.Device{ID: "MIG-1"}, Index: "1:0", TotalMemory: 5 * gib, ComputeCapability: "8.0"},
+	)
+	attributes := map[string]*lm.DeviceAttributes{
+		"GPU-0": {UUID: "GPU-0", Product: "NVIDIA-A100-SXM4-40GB", ComputeMajor: 8, Family: "ampere", CliqueID: "cluster.7"},
+		"MIG-1": {UUID: "MIG-1", Product: "NVIDIA-A100-SXM4-40GB", ComputeMajor: 8, Family: "ampere", MigProfile: "1g.5gb"},
+	}
+
+	devices, err := newAllocatableDevices([]rm.ResourceManager{gpus, migs}, attributes)
+	require.NoError(t, err)
+	require.Equal(t, []resourceapi.Device{
+		{
+			Name: "gpu-0",
+			Attributes: map[resourceapi.QualifiedName]resourceapi.DeviceAttribute{
+				"type":                  {StringValue: ptr.To("gpu")},
+				"uuid":                  {StringValue: ptr.To("GPU-0")},
+				"index":                 {IntValue: ptr.To[int64](0)},
+				"resourceName":          {StringValue: ptr.To("nvidia.com/gpu")},
+				"productName":           {StringValue: ptr.To("NVIDIA-A100-SXM4-40GB")},
+				"architecture":          {StringValue: ptr.To("ampere")},
+				"cudaComputeCapability": {VersionValue: ptr.To("8.0.0")},
+				"cliqueID":              {StringValue: ptr.To("cluster.7")},
+			},
+			Capacity: map[resourceapi.QualifiedName]resourceapi.DeviceCapacity{
+				"memory": {Value: *resource.NewQuantity(40*gib, resource.BinarySI)},
+			},
+		},
+		{
+			Name: "mig-1-0",
+			Attributes: map[resourceapi.QualifiedName]resourceapi.DeviceAttribute{
+				"type":                  {StringValue: ptr.To("mig")},
+				"uuid":                  {StringValue: ptr.To("MIG-1")},
+				"resourceName":          {StringValue: ptr.To("nvidia.com/mig-1g.5gb")},
+				"productName":           {StringValue: ptr.To("NVIDIA-A100-SXM4-40GB")},
+				"architecture":          {StringValue: ptr.To("ampere")},
+				"cudaComputeCapability": {VersionValue: ptr.To("8.0.0")},
+				"migProfile":            {StringValue: ptr.To("1g.5gb")},
+			},
+			Capacity: map[resourceapi.QualifiedName]resourceapi.DeviceCapacity{
+				"memory": {Value: *resource.NewQuantity(5*gib, resource.BinarySI)},
+			},
+		},
+	}, devices.apiDevices())
+
+	devices["gpu-0"].Health = pluginapi.Unhealthy
+	require.Len(t, devices.apiDevices(), 1)
+}
+
+func TestNewAllocatableDevicesRejectsSharing(t *testing.T) {
+	shared := newTestResourceManager("nvidia.com/gpu",
+		&rm.Device{Device: pluginapi.Device{ID: "GPU-0::0"}, Index: "0", Replicas: 2},
+		&rm.Device{Device: pluginapi.Device{ID: "GPU-0::1"}, Index: "0", Replicas: 2},
+	)
+	_, err := newAllocatableDevices([]rm.ResourceManager{shared}, nil)
+	require.Error(t, err)
+}
+
+// dialSocket connects to the specified socket as the kubelet would.
+func dialSocket(t *testing.T, socket string) *grpc.ClientConn {
+	conn, err := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func TestPlugin(t *testing.T) {
+	// The path of a unix socket is limited in length so we do not use t.TempDir().
+	root, err := os.MkdirTemp("", "dra")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(root) })
+	pluginsDir := filepath.Join(root, "plugins")
+	registryDir := filepath.Join(root, "registry")
+
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-a", UID: "node-uid"}},
+		&resourceapi.ResourceClaim{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "claim", UID: "claim-uid"},
+			Status: resourceapi.ResourceClaimStatus{
+				Allocation: &resourceapi.AllocationResult{
+					Devices: resourceapi.DeviceAllocationResult{
+						Results: []resourceapi.DeviceRequestAllocationResult{
+							{Request: "gpu", Driver: DefaultDriverName, Pool: "node-a", Device: "gpu-0"},
+							{Request: "other", Driver: "other.example.com", Pool: "node-a", Device: "dev-0"},
+						},
+					},
+				},
+			},
+		},
+	)
+
+	gpu := &rm.Device{Device: pluginapi.Device{ID: "GPU-0"}, Index: "0", TotalMemory: 40 * gib}
+	gpus := newTestResourceManager("nvidia.com/gpu",
+		gpu,
+		&rm.Device{Device: pluginapi.Device{ID: "GPU-1"}, Index: "1", TotalMemory: 40 * gib},
+	)
+
+	p, err := New(context.Background(), []rm.ResourceManager{gpus},
+		WithClient(client),
+		WithNodeName("node-a"),
+		WithCDIHandler(newTestCDIHandler()),
+		WithImexChannels(imex.Channels{{ID: "0"}}),
+		WithKubeletPluginDirectories(pluginsDir, registryDir),
+	)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"GPU-0", "GPU-1"}, p.Devices().GetIDs())
+
+	require.NoError(t, p.Start(""))
+	t.Cleanup(func() { _ = p.Stop() })
+
+	slice, err := client.ResourceV1().ResourceSlices().Get(context.Background(), "node-a-gpu.nvidia.com", metav1.GetOptions{})
+	require.NoError(t, err)
+	require.Equal(t, DefaultDriverName, slice.Spec.Driver)
+	require.Equal(t, ptr.To("node-a"), slice.Spec.NodeName)
+	require.Equal(t, resourceapi.ResourcePool{Name: "node-a", ResourceSliceCount: 1}, slice.Spec.Pool)
+	require.Len(t, slice.Spec.Devices, 2)
+	require.Equal(t, "node-uid", string(slice.OwnerReferences[0].UID))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Register the plugin as the kubelet does through the registration socket.
+	registration := registerapi.NewRegistrationClient(dialSocket(t, filepath.Join(registryDir, "gpu.nvidia.com-reg.sock")))
+	info, err := registration.GetInfo(ctx, &registerapi.InfoRequest{})
+	require.NoError(t, err)
+	require.Equal(t, registerapi.DRAPlugin, info.Type)
+	require.Equal(t, DefaultDriverName, info.Name)
+	require.Equal(t, filepath.Join(pluginsDir, "gpu.nvidia.com", "dra.sock"), info.Endpoint)
+	require.Equal(t, []string{drapb.DRAPluginService}, info.SupportedVersions)
+	_, err = registration.NotifyRegistrationStatus(ctx, &registerapi.RegistrationStatus{PluginRegistered: true})
+	require.NoError(t, err)
+
+	endpoint := drapb.NewDRAPluginClient(dialSocket(t, info.Endpoint))
+	prepared, err := endpoint.NodePrepareResources(ctx, &drapb.NodePrepareResourcesRequest{
+		Claims: []*drapb.Claim{
+			{Namespace: "default", Name: "claim", Uid: "claim-uid"},
+			{Namespace: "default", Name: "missing", Uid: "missing-uid"},
+		},
+	})
+	require.NoError(t, err)
+	require.Len(t, prepared.Claims, 2)
+	require.Empty(t, prepared.Claims["claim-uid"].Error)
+	require.Len(t, prepared.Claims["claim-uid"].Devices, 1)
+	expected := &drapb.Device{
+		RequestNames: []string{"gpu"},
+		PoolName:     "node-a",
+		DeviceName:   "gpu-0",
+		CdiDeviceIds: []string{
+			"k8s.device-plugin.nvidia.com/gpu=GPU-0",
+			"k8s.device-plugin.nvidia.com/imex-channel=0",
+		},
+	}
+	require.True(t, proto.Equal(expected, prepared.Claims["claim-uid"].Devices[0]), prepared.Claims["claim-uid"].Devices[0])
+	require.NotEmpty(t, prepared.Claims["missing-uid"].Error)
+
+	unprepared, err := endpoint.NodeUnprepareResources(ctx, &drapb.NodeUnprepareResourcesRequest{
+		Claims: []*drapb.Claim{{Namespace: "default", Name: "claim", Uid: "claim-uid"}},
+	})
+	require.NoError(t, err)
+	require.Empty(t, unprepared.Claims["claim-uid"].Error)
+	require.Empty(t, p.prepared)
+
+	// An unhealthy device is removed from the ResourceSlice.
+	p.health <- rm.NewUnhealthyEvent(gpu, "XID 79")
+	require.Eventually(t, func() bool {
+		slice, err := client.ResourceV1().ResourceSlices().Get(context.Background(), "node-a-gpu.nvidia.com", metav1.GetOptions{})
+		return err == nil && slice.Spec.Pool.Generation == 1 && len(slice.Spec.Devices) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// A claim that was allocated an unhealthy device is not prepared.
+	prepared, err = endpoint.NodePrepareResources(ctx, &drapb.NodePrepareResourcesRequest{
+		Claims: []*drapb.Claim{{Namespace: "default", Name: "claim", Uid: "claim-uid"}},
+	})
+	require.NoError(t, err)
+	require.Contains(t, prepared.Claims["claim-uid"].Error, "device gpu-0 is unhealthy")
+
+	// Publishing the ResourceSlice is retried if it fails.
+	publishRetryInterval = 10 * time.Millisecond
+	failed := false
+	client.PrependReactor("update", "resourceslices", func(k8stesting.Action) (bool, runtime.Object, error) {
+		if failed {
+			return false, nil, nil
+		}
+		failed = true
+		return true, nil, errors.New("conflict")
+	})
+	p.health <- rm.NewHealthyEvent(gpu, "recovered")
+	require.Eventually(t, func() bool {
+		slice, err := client.ResourceV1().ResourceSlices().Get(context.Background(), "node-a-gpu.nvidia.com", metav1.GetOptions{})
+		return err == nil && slice.Spec.Pool.Generation == 2 && len(slice.Spec.Devices) == 2
+	}, 5*time.Second, 10*time.Millisecond)
+	require.True(t, failed)
+
+	require.NoError(t, p.Stop())
+	require.NoFileExists(t, info.Endpoint)
+	require.NoFileExists(t, filepath.Join(registryDir, "gpu.nvidia.com-reg.sock"))
+}
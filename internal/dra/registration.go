@@ -0,0 +1,62 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package dra
+
+import (
+	"context"
+
+	"k8s.io/klog/v2"
+	drapb "k8s.io/kubelet/pkg/apis/dra/v1beta1"
+	registerapi "k8s.io/kubelet/pkg/apis/pluginregistration/v1"
+)
+
+// registrar serves the plugin registration API for a DRA driver.
+type registrar struct {
+	registerapi.UnimplementedRegistrationServer
+
+	driverName string
+	endpoint   string
+}
+
+var _ registerapi.RegistrationServer = (*registrar)(nil)
+
+func newRegistrar(driverName string, endpoint string) *registrar {
+	return &registrar{
+		driverName: driverName,
+		endpoint:   endpoint,
+	}
+}
+
+// GetInfo returns the information of the DRA kubelet plugin.
+func (r *registrar) GetInfo(context.Context, *registerapi.InfoRequest) (*registerapi.PluginInfo, error) {
+	return &registerapi.PluginInfo{
+		Type:              registerapi.DRAPlugin,
+		Name:              r.driverName,
+		Endpoint:          r.endpoint,
+		SupportedVersions: []string{drapb.DRAPluginService},
+	}, nil
+}
+
+// NotifyRegistrationStatus logs the registration status reported by the kubelet.
+func (r *registrar) NotifyRegistrationStatus(ctx context.Context, status *registerapi.RegistrationStatus) (*registerapi.RegistrationStatusResponse, error) {
+	if !status.PluginRegistered {
+		klog.Errorf("Registration of DRA driver '%s' with the kubelet failed: %s", r.driverName, status.Error)
+		return &registerapi.RegistrationStatusResponse{}, nil
+	}
+	klog.Infof("Registered DRA driver '%s' with the kubelet", r.driverName)
+	return &registerapi.RegistrationStatusResponse{}, nil
+}
@@ -0,0 +1,157 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// DeviceAttributes holds the properties of a single full GPU or MIG device
+// that are otherwise exposed as node labels.
+type DeviceAttributes struct {
+	UUID      string
+	Product   string
+	MemoryMiB uint64
+	// ComputeMajor and ComputeMinor hold the CUDA compute capability. For MIG
+	// devices this is the compute capability of the parent GPU.
+	ComputeMajor int
+	ComputeMinor int
+	Family       string
+	// MigProfile is the MIG profile of a MIG device and empty for full GPUs.
+	MigProfile string
+	// CliqueID is the <cluster-uuid>.<clique-id> of a fabric-attached GPU
+	// and is empty otherwise. MIG devices inherit the clique of their parent.
+	CliqueID string
+}
+
+// IsMig checks whether the attributes describe a MIG device.
+func (a *DeviceAttributes) IsMig() bool {
+	return a.MigProfile != ""
+}
+
+// GetDeviceAttributes returns the attributes of the full GPUs and MIG devices
+// of the specified manager keyed by device UUID. The manager is expected to
+// have been initialized.
+func GetDeviceAttributes(manager resource.Manager) (map[string]*DeviceAttributes, error) {
+	devices, err := manager.GetDevices()
+	if err != nil {
+		return nil, fmt.Errorf("error getting devices: %w", err)
+	}
+
+	attributes := make(map[string]*DeviceAttributes)
+	for _, d := range devices {
+		gpu, err := newDeviceAttributes(d)
+		if err != nil {
+			return nil, err
+		}
+		attributes[gpu.UUID] = gpu
+
+		migEnabled, err := d.IsMigEnabled()
+		if err != nil {
+			return nil, fmt.Errorf("error checking if MIG is enabled on device: %w", err)
+		}
+		if !migEnabled {
+			continue
+		}
+		migs, err := d.GetMigDevices()
+		if err != nil {
+			return nil, fmt.Errorf("error getting MIG devices: %w", err)
+		}
+		for _, mig := range migs {
+			a, err := newMigDeviceAttributes(gpu, mig)
+			if err != nil {
+				return nil, err
+			}
+			attributes[a.UUID] = a
+		}
+	}
+	return attributes, nil
+}
+
+func newDeviceAttributes(d resource.Device) (*DeviceAttributes, error) {
+	uuid, err := d.GetUUID()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device UUID: %w", err)
+	}
+	name, err := d.GetName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device name: %w", err)
+	}
+	memory, err := d.GetTotalMemoryMiB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting device memory: %w", err)
+	}
+	computeMajor, computeMinor, err := d.GetCudaComputeCapability()
+	if err != nil {
+		return nil, fmt.Errorf("failed to determine CUDA compute capability: %w", err)
+	}
+
+	a := &DeviceAttributes{
+		UUID:         uuid,
+		Product:      sanitise(name),
+		MemoryMiB:    memory,
+		ComputeMajor: computeMajor,
+		ComputeMinor: computeMinor,
+	}
+	if computeMajor != 0 {
+		a.Family = getArchFamily(computeMajor, computeMinor)
+	}
+
+	isFabricAttached, err := d.IsFabricAttached()
+	if err != nil {
+		return nil, fmt.Errorf("error checking imex capability: %w", err)
+	}
+	if isFabricAttached {
+		clusterUUID, cliqueID, err := d.GetFabricIDs()
+		if err != nil {
+			return nil, fmt.Errorf("error getting fabric IDs: %w", err)
+		}
+		if clusterUUID != "" && cliqueID != "" {
+			a.CliqueID = strings.Join([]string{clusterUUID, cliqueID}, ".")
+		}
+	}
+	return a, nil
+}
+
+func newMigDeviceAttributes(parent *DeviceAttributes, mig resource.Device) (*DeviceAttributes, error) {
+	uuid, err := mig.GetUUID()
+	if err != nil {
+		return nil, fmt.Errorf("error getting MIG device UUID: %w", err)
+	}
+	profile, err := mig.GetName()
+	if err != nil {
+		return nil, fmt.Errorf("error getting MIG profile: %w", err)
+	}
+	memory, err := mig.GetTotalMemoryMiB()
+	if err != nil {
+		return nil, fmt.Errorf("error getting MIG device memory: %w", err)
+	}
+
+	return &DeviceAttributes{
+		UUID:         uuid,
+		Product:      parent.Product,
+		MemoryMiB:    memory,
+		ComputeMajor: parent.ComputeMajor,
+		ComputeMinor: parent.ComputeMinor,
+		Family:       parent.Family,
+		MigProfile:   profile,
+		CliqueID:     parent.CliqueID,
+	}, nil
+}
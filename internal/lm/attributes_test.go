@@ -0,0 +1,64 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+func TestGetDeviceAttributes(t *testing.T) {
+	gpu := rt.NewDeviceMock(false).WithUUID("GPU-0")
+	gpu.GetNameFunc = func() (string, error) { return "NVIDIA A100-SXM4-40GB", nil }
+	gpu.IsFabricAttachedFunc = func() (bool, error) { return true, nil }
+	gpu.GetFabricIDsFunc = func() (string, string, error) { return "cluster", "7", nil }
+
+	mig := rt.NewMigDevice(1, 1, 5)
+	mig.GetUUIDFunc = func() (string, error) { return "MIG-0", nil }
+	mig.GetTotalMemoryMiBFunc = func() (uint64, error) { return 4864, nil }
+	migEnabled := rt.NewDeviceMock(true).WithUUID("GPU-1").WithMigDevices(mig)
+
+	attributes, err := GetDeviceAttributes(rt.NewManagerMockWithDevices(gpu, migEnabled))
+	require.NoError(t, err)
+	require.EqualValues(t, map[string]*DeviceAttributes{
+		"GPU-0": {
+			UUID:         "GPU-0",
+			Product:      "NVIDIA-A100-SXM4-40GB",
+			MemoryMiB:    300,
+			ComputeMajor: 8,
+			ComputeMinor: 0,
+			Family:       "ampere",
+			CliqueID:     "cluster.7",
+		},
+		"GPU-1": {
+			UUID:      "GPU-1",
+			Product:   "MOCKMODEL",
+			MemoryMiB: 300,
+		},
+		"MIG-0": {
+			UUID:       "MIG-0",
+			Product:    "MOCKMODEL",
+			MemoryMiB:  4864,
+			MigProfile: "1g.5gb",
+		},
+	}, attributes)
+	require.True(t, attributes["MIG-0"].IsMig())
+	require.False(t, attributes["GPU-0"].IsMig())
+}
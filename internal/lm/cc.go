@@ -0,0 +1,48 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"errors"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// newConfComputeLabeler creates a labeler that reports the confidential
+// computing (CC) mode of the system. Since NVML does not report the CC mode
+// of individual GPUs, there is no label per GPU. No labels are generated if
+// the CC state cannot be queried.
+func newConfComputeLabeler(manager resource.Manager) Labeler {
+	state, err := manager.GetConfComputeState()
+	if errors.Is(err, resource.ErrNotSupported) {
+		klog.Infof("Not labeling CC mode: %v", err)
+		return empty{}
+	}
+	if err != nil {
+		klog.Warningf("Failed to get CC state: %v", err)
+		return empty{}
+	}
+
+	labels := Labels{
+		"nvidia.com/cc.mode.detected":  string(state.Mode),
+		"nvidia.com/cc.protected-pcie": strconv.FormatBool(state.ProtectedPCIe),
+	}
+	return labels
+}
@@ -0,0 +1,87 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+func TestConfComputeLabeler(t *testing.T) {
+	testCases := []struct {
+		description    string
+		manager        *rt.ManagerMock
+		expectedLabels Labels
+	}{
+		{
+			description: "cc off",
+			manager:     rt.NewManagerMockWithDevices(rt.NewFullGPU()),
+			expectedLabels: Labels{
+				"nvidia.com/cc.mode.detected":  "off",
+				"nvidia.com/cc.protected-pcie": "false",
+			},
+		},
+		{
+			description: "cc on with protected pcie",
+			manager: rt.NewManagerMockWithDevices(rt.NewFullGPU(), rt.NewFullGPU()).WithConfComputeState(&resource.ConfComputeState{
+				Mode:          resource.ConfComputeModeOn,
+				ProtectedPCIe: true,
+			}),
+			expectedLabels: Labels{
+				"nvidia.com/cc.mode.detected":  "on",
+				"nvidia.com/cc.protected-pcie": "true",
+			},
+		},
+		{
+			description: "devtools",
+			manager: rt.NewManagerMockWithDevices(rt.NewFullGPU()).WithConfComputeState(&resource.ConfComputeState{
+				Mode: resource.ConfComputeModeDevtools,
+			}),
+			expectedLabels: Labels{
+				"nvidia.com/cc.mode.detected":  "devtools",
+				"nvidia.com/cc.protected-pcie": "false",
+			},
+		},
+		{
+			description: "unsupported",
+			manager: func() *rt.ManagerMock {
+				m := rt.NewManagerMockWithDevices(rt.NewFullGPU())
+				m.GetConfComputeStateFunc = func() (*resource.ConfComputeState, error) {
+					return nil, fmt.Errorf("GetConfComputeState is %w", resource.ErrNotSupported)
+				}
+				return m
+			}(),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			labels, err := newConfComputeLabeler(tc.manager).Labels()
+			require.NoError(t, err)
+			if tc.expectedLabels == nil {
+				require.Empty(t, labels)
+				return
+			}
+			require.EqualValues(t, tc.expectedLabels, labels)
+		})
+	}
+}
@@ -0,0 +1,165 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+const (
+	// deviceLabelPrefix is the prefix of the labels of individual GPUs. These
+	// are named <deviceLabelPrefix><id>.<attribute>.
+	deviceLabelPrefix = "nvidia.com/gpu.device-"
+	// deviceFeatureName is the name of the NodeFeature instance feature that
+	// holds the attributes of the individual GPUs.
+	deviceFeatureName = "nvidia.com/gpu.device"
+	// deviceIDHashLength is the number of hex digits of the hash of the UUID
+	// that identifies a GPU in the uuid mode. The full UUID does not fit into
+	// a label name.
+	deviceIDHashLength = 12
+)
+
+// newPerDeviceLabeler creates a labeler that generates labels for each
+// individual GPU as selected by the deviceLabels option. This describes the
+// GPUs of nodes with different GPU models, which the per-resource labels
+// cannot. The node is also labeled as heterogeneous in this case.
+func newPerDeviceLabeler(config *spec.Config, devices []resource.Device) (Labeler, error) {
+	mode := spec.DeviceLabelsNone
+	if config.Flags.GFD != nil && config.Flags.GFD.DeviceLabels != nil {
+		mode = *config.Flags.GFD.DeviceLabels
+	}
+	if mode == spec.DeviceLabelsNone {
+		return empty{}, nil
+	}
+
+	labels := make(Labels)
+	products := make(map[string]bool)
+	for i, d := range devices {
+		attributes, err := getDeviceLabelAttributes(d)
+		if err != nil {
+			return nil, err
+		}
+		id := getDeviceLabelID(mode, i, attributes["uuid"])
+		for attribute, value := range attributes {
+			labels[deviceLabelPrefix+id+"."+attribute] = value
+		}
+		products[attributes["product"]] = true
+	}
+	labels["nvidia.com/gpu.heterogeneous"] = strconv.FormatBool(len(products) > 1)
+
+	return labels, nil
+}
+
+// getDeviceLabelID returns the ID of a GPU in its labels. This is either its
+// index or a hash of its UUID.
+func getDeviceLabelID(mode string, index int, uuid string) string {
+	if mode == spec.DeviceLabelsUUID {
+		hash := sha256.Sum256([]byte(uuid))
+		return hex.EncodeToString(hash[:])[:deviceIDHashLength]
+	}
+	return strconv.Itoa(index)
+}
+
+// getDeviceLabelAttributes returns the attributes of a GPU that are labeled.
+func getDeviceLabelAttributes(d resource.Device) (map[string]string, error) {
+	a, err := newDeviceAttributes(d)
+	if err != nil {
+		return nil, err
+	}
+	migEnabled, err := d.IsMigEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("error checking if MIG is enabled on device: %w", err)
+	}
+
+	migMode := "disabled"
+	if migEnabled {
+		migMode = "enabled"
+	}
+	attributes := map[string]string{
+		"uuid":     a.UUID,
+		"product":  a.Product,
+		"mig.mode": migMode,
+	}
+	if a.MemoryMiB != 0 {
+		attributes["memory"] = strconv.FormatUint(a.MemoryMiB, 10)
+	}
+	if a.ComputeMajor != 0 {
+		attributes["family"] = a.Family
+		attributes["compute.major"] = strconv.Itoa(a.ComputeMajor)
+		attributes["compute.minor"] = strconv.Itoa(a.ComputeMinor)
+	}
+	return attributes, nil
+}
+
+// getDeviceInstanceFeatures returns the instance features that hold the same
+// attributes of individual GPUs as the specified labels. Each instance also
+// has an "id" attribute with the ID used in the labels.
+func getDeviceInstanceFeatures(labels Labels) map[string]nfdv1alpha1.InstanceFeatureSet {
+	devices := make(map[string]map[string]string)
+	for key, value := range labels {
+		rest, found := strings.CutPrefix(key, deviceLabelPrefix)
+		if !found {
+			continue
+		}
+		id, attribute, found := strings.Cut(rest, ".")
+		if !found {
+			continue
+		}
+		if devices[id] == nil {
+			devices[id] = map[string]string{"id": id}
+		}
+		devices[id][attribute] = value
+	}
+	if len(devices) == 0 {
+		return nil
+	}
+
+	ids := make([]string, 0, len(devices))
+	for id := range devices {
+		ids = append(ids, id)
+	}
+	slices.SortFunc(ids, compareDeviceIDs)
+
+	var elements []nfdv1alpha1.InstanceFeature
+	for _, id := range ids {
+		elements = append(elements, *nfdv1alpha1.NewInstanceFeature(devices[id]))
+	}
+	return map[string]nfdv1alpha1.InstanceFeatureSet{
+		deviceFeatureName: nfdv1alpha1.NewInstanceFeatures(elements...),
+	}
+}
+
+// compareDeviceIDs orders indices numerically and hashes lexically.
+func compareDeviceIDs(a, b string) int {
+	i, errA := strconv.Atoi(a)
+	j, errB := strconv.Atoi(b)
+	if errA == nil && errB == nil {
+		return cmp.Compare(i, j)
+	}
+	return strings.Compare(a, b)
+}
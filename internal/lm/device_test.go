@@ -0,0 +1,128 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	nfdv1alpha1 "sigs.k8s.io/node-feature-discovery/api/nfd/v1alpha1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+func TestPerDeviceLabeler(t *testing.T) {
+	rtx := rt.NewDeviceMock(false).WithUUID("GPU-0").WithName("NVIDIA RTX A6000")
+	rtx.GetTotalMemoryMiBFunc = func() (uint64, error) { return 49140, nil }
+	rtx.GetCudaComputeCapabilityFunc = func() (int, int, error) { return 8, 6, nil }
+	a100 := rt.NewDeviceMock(true).WithUUID("GPU-1").WithName("NVIDIA A100-PCIE-40GB")
+
+	testCases := []struct {
+		description    string
+		deviceLabels   *string
+		devices        []resource.Device
+		expectedLabels Labels
+	}{
+		{
+			description: "disabled by default",
+			devices:     []resource.Device{rtx, a100},
+		},
+		{
+			description:  "none",
+			deviceLabels: ptr(spec.DeviceLabelsNone),
+			devices:      []resource.Device{rtx, a100},
+		},
+		{
+			description:  "index",
+			deviceLabels: ptr(spec.DeviceLabelsIndex),
+			devices:      []resource.Device{rtx, a100},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.device-0.uuid":          "GPU-0",
+				"nvidia.com/gpu.device-0.product":       "NVIDIA-RTX-A6000",
+				"nvidia.com/gpu.device-0.memory":        "49140",
+				"nvidia.com/gpu.device-0.family":        "ampere",
+				"nvidia.com/gpu.device-0.compute.major": "8",
+				"nvidia.com/gpu.device-0.compute.minor": "6",
+				"nvidia.com/gpu.device-0.mig.mode":      "disabled",
+				"nvidia.com/gpu.device-1.uuid":          "GPU-1",
+				"nvidia.com/gpu.device-1.product":       "NVIDIA-A100-PCIE-40GB",
+				"nvidia.com/gpu.device-1.memory":        "300",
+				"nvidia.com/gpu.device-1.mig.mode":      "enabled",
+				"nvidia.com/gpu.heterogeneous":          "true",
+			},
+		},
+		{
+			description:  "uuid",
+			deviceLabels: ptr(spec.DeviceLabelsUUID),
+			devices:      []resource.Device{a100},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.device-9207b013e156.uuid":     "GPU-1",
+				"nvidia.com/gpu.device-9207b013e156.product":  "NVIDIA-A100-PCIE-40GB",
+				"nvidia.com/gpu.device-9207b013e156.memory":   "300",
+				"nvidia.com/gpu.device-9207b013e156.mig.mode": "enabled",
+				"nvidia.com/gpu.heterogeneous":                "false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config := &spec.Config{
+				Flags: spec.Flags{
+					CommandLineFlags: spec.CommandLineFlags{
+						GFD: &spec.GFDCommandLineFlags{
+							DeviceLabels: tc.deviceLabels,
+						},
+					},
+				},
+			}
+
+			l, err := newPerDeviceLabeler(config, tc.devices)
+			require.NoError(t, err)
+
+			labels, err := l.Labels()
+			require.NoError(t, err)
+			if tc.expectedLabels == nil {
+				require.Empty(t, labels)
+				return
+			}
+			require.EqualValues(t, tc.expectedLabels, labels)
+		})
+	}
+}
+
+func TestGetDeviceInstanceFeatures(t *testing.T) {
+	require.Nil(t, getDeviceInstanceFeatures(Labels{"nvidia.com/gpu.product": "MOCKMODEL"}))
+
+	labels := Labels{
+		"nvidia.com/gpu.product":           "MOCKMODEL",
+		"nvidia.com/gpu.heterogeneous":     "true",
+		"nvidia.com/gpu.device-10.product": "NVIDIA-A100-PCIE-40GB",
+		"nvidia.com/gpu.device-2.product":  "NVIDIA-RTX-A6000",
+		"nvidia.com/gpu.device-2.mig.mode": "disabled",
+	}
+	require.Equal(t, map[string]nfdv1alpha1.InstanceFeatureSet{
+		"nvidia.com/gpu.device": {
+			Elements: []nfdv1alpha1.InstanceFeature{
+				{Attributes: map[string]string{"id": "2", "product": "NVIDIA-RTX-A6000", "mig.mode": "disabled"}},
+				{Attributes: map[string]string{"id": "10", "product": "NVIDIA-A100-PCIE-40GB"}},
+			},
+		},
+	}, getDeviceInstanceFeatures(labels))
+}
@@ -0,0 +1,133 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"errors"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// mixedValue is the value of a hardware label if the GPUs on the node differ
+// in the labeled attribute.
+const mixedValue = "mixed"
+
+// newHardwareLabeler creates a labeler that reports the firmware versions and
+// the ECC, persistence, and compute modes of the GPUs on the node. Labels for
+// attributes that cannot be queried for all GPUs are omitted.
+func newHardwareLabeler(devices []resource.Device) Labeler {
+	labels := make(Labels)
+
+	vbios, err := getCommonValue(devices, "VBIOS version", func(d resource.Device) (string, error) {
+		version, err := d.GetVbiosVersion()
+		return sanitise(version), err
+	})
+	if err == nil {
+		labels["nvidia.com/gpu.vbios-version"] = vbios
+	}
+
+	inforom, err := getCommonValue(devices, "InfoROM image version", func(d resource.Device) (string, error) {
+		version, err := d.GetInforomImageVersion()
+		return sanitise(version), err
+	})
+	if err == nil {
+		labels["nvidia.com/gpu.inforom-version"] = inforom
+	}
+
+	computeMode, err := getCommonValue(devices, "compute mode", func(d resource.Device) (string, error) {
+		mode, err := d.GetComputeMode()
+		return string(mode), err
+	})
+	if err == nil {
+		labels["nvidia.com/gpu.compute-mode"] = computeMode
+	}
+
+	persistenceModeEnabled, err := allDevices(devices, "persistence mode", func(d resource.Device) (bool, error) {
+		return d.IsPersistenceModeEnabled()
+	})
+	if err == nil {
+		labels["nvidia.com/gpu.persistence-mode.enabled"] = strconv.FormatBool(persistenceModeEnabled)
+	}
+
+	eccEnabled, rebootRequired, err := getECCMode(devices)
+	if err == nil {
+		labels["nvidia.com/gpu.ecc.enabled"] = strconv.FormatBool(eccEnabled)
+		labels["nvidia.com/gpu.ecc.reboot-required"] = strconv.FormatBool(rebootRequired)
+	}
+
+	return labels
+}
+
+// getCommonValue returns the value of an attribute if it is the same for all
+// devices or mixedValue otherwise.
+func getCommonValue(devices []resource.Device, attribute string, get func(resource.Device) (string, error)) (string, error) {
+	var common string
+	for i, d := range devices {
+		value, err := get(d)
+		if err != nil {
+			logHardwareError(attribute, err)
+			return "", err
+		}
+		if i > 0 && value != common {
+			klog.Infof("GPUs have different %v values: %q and %q", attribute, common, value)
+			return mixedValue, nil
+		}
+		common = value
+	}
+	return common, nil
+}
+
+// allDevices returns whether a condition is true for all devices.
+func allDevices(devices []resource.Device, attribute string, condition func(resource.Device) (bool, error)) (bool, error) {
+	all := true
+	for _, d := range devices {
+		ok, err := condition(d)
+		if err != nil {
+			logHardwareError(attribute, err)
+			return false, err
+		}
+		all = all && ok
+	}
+	return all, nil
+}
+
+// getECCMode returns whether ECC is enabled on all devices and whether a
+// reboot is required for a pending change to the ECC mode of any device.
+func getECCMode(devices []resource.Device) (bool, bool, error) {
+	allEnabled, anyPending := true, false
+	for _, d := range devices {
+		current, pending, err := d.GetECCMode()
+		if err != nil {
+			logHardwareError("ECC mode", err)
+			return false, false, err
+		}
+		allEnabled = allEnabled && current
+		anyPending = anyPending || current != pending
+	}
+	return allEnabled, anyPending, nil
+}
+
+func logHardwareError(attribute string, err error) {
+	if errors.Is(err, resource.ErrNotSupported) {
+		klog.Infof("Not labeling %v: %v", attribute, err)
+		return
+	}
+	klog.Warningf("Failed to get %v: %v", attribute, err)
+}
@@ -0,0 +1,91 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+func TestHardwareLabeler(t *testing.T) {
+	testCases := []struct {
+		description    string
+		devices        func() []resource.Device
+		expectedLabels Labels
+	}{
+		{
+			description: "uniform gpus",
+			devices: func() []resource.Device {
+				return []resource.Device{rt.NewFullGPU(), rt.NewFullGPU()}
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.vbios-version":            "96.00.89.00.01",
+				"nvidia.com/gpu.inforom-version":          "G500.0200.00.03",
+				"nvidia.com/gpu.compute-mode":             "default",
+				"nvidia.com/gpu.persistence-mode.enabled": "true",
+				"nvidia.com/gpu.ecc.enabled":              "true",
+				"nvidia.com/gpu.ecc.reboot-required":      "false",
+			},
+		},
+		{
+			description: "gpus that differ",
+			devices: func() []resource.Device {
+				d := rt.NewDeviceMock(false)
+				d.GetVbiosVersionFunc = func() (string, error) { return "94.02.5C.00.02", nil }
+				d.GetComputeModeFunc = func() (resource.ComputeMode, error) { return resource.ComputeModeExclusiveProcess, nil }
+				d.IsPersistenceModeEnabledFunc = func() (bool, error) { return false, nil }
+				d.GetECCModeFunc = func() (bool, bool, error) { return false, true, nil }
+				return []resource.Device{rt.NewFullGPU(), d}
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.vbios-version":            "mixed",
+				"nvidia.com/gpu.inforom-version":          "G500.0200.00.03",
+				"nvidia.com/gpu.compute-mode":             "mixed",
+				"nvidia.com/gpu.persistence-mode.enabled": "false",
+				"nvidia.com/gpu.ecc.enabled":              "false",
+				"nvidia.com/gpu.ecc.reboot-required":      "true",
+			},
+		},
+		{
+			description: "unsupported and failing queries are not labeled",
+			devices: func() []resource.Device {
+				d := rt.NewDeviceMock(false)
+				d.GetVbiosVersionFunc = func() (string, error) { return "", resource.ErrNotSupported }
+				d.GetInforomImageVersionFunc = func() (string, error) { return "", resource.ErrNotSupported }
+				d.IsPersistenceModeEnabledFunc = func() (bool, error) { return false, resource.ErrNotSupported }
+				d.GetECCModeFunc = func() (bool, bool, error) { return false, false, fmt.Errorf("failed") }
+				return []resource.Device{rt.NewFullGPU(), d}
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.compute-mode": "default",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			labels, err := newHardwareLabeler(tc.devices()).Labels()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedLabels, labels)
+		})
+	}
+}
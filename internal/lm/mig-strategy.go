@@ -33,6 +33,15 @@ const (
 	MigStrategyMixed  = "mixed"
 )
 
+// fullGPUGroup identifies a set of full GPUs with the same name that are
+// labelled together. If only some of the devices of a resource are shared,
+// the shared and exclusive devices are labelled separately.
+type fullGPUGroup struct {
+	name         string
+	resourceName spec.ResourceName
+	exclusive    bool
+}
+
 // migResource is used to track MIG devices for labelling under the single and mixed strategies.
 // This allows a particular resource name to be associated with an resource.Device and count.
 type migResource struct {
@@ -112,36 +121,52 @@ func newMigLabeler(manager resource.Manager, config *spec.Config) (Labeler, erro
 
 // newGPULabelers creates a set of labelers for full GPUs
 func newGPULabelers(manager resource.Manager, config *spec.Config) (Labeler, error) {
-	deviceInfo := mig.NewDeviceInfo(manager)
-
-	devicesByMigEnabled, err := deviceInfo.GetDevicesMap()
+	devices, err := manager.GetDevices()
 	if err != nil {
-		return nil, fmt.Errorf("error getting map of devices: %v", err)
+		return nil, fmt.Errorf("error getting devices: %v", err)
 	}
 
-	if len(devicesByMigEnabled) == 0 {
+	if len(devices) == 0 {
 		return nil, fmt.Errorf("no GPU devices detected")
 	}
 
 	counts := make(map[string]int)
 	migEnabledDevices := make(map[string]resource.Device)
-	for _, device := range devicesByMigEnabled[true] {
-		name, err := device.GetName()
+	fullGPUs := make(map[fullGPUGroup]resource.Device)
+	deviceReplicas := make(map[fullGPUGroup][]int)
+	// If only some devices of a resource are shared, the shared and exclusive
+	// devices are counted separately.
+	partialCounts := make(map[fullGPUGroup]int)
+	positions := make(map[spec.ResourceName]int)
+	// The devices are enumerated in index order.
+	for index, device := range devices {
+		isMigEnabled, err := device.IsMigEnabled()
 		if err != nil {
-			return nil, fmt.Errorf("error getting device name: %v", err)
+			return nil, fmt.Errorf("error checking if MIG is enabled: %v", err)
 		}
-		migEnabledDevices[name] = device
-		counts[name]++
-	}
-
-	fullGPUs := make(map[string]resource.Device)
-	for _, device := range devicesByMigEnabled[false] {
 		name, err := device.GetName()
 		if err != nil {
 			return nil, fmt.Errorf("error getting device name: %v", err)
 		}
-		fullGPUs[name] = device
 		counts[name]++
+		if isMigEnabled {
+			migEnabledDevices[name] = device
+			continue
+		}
+		sharedLabeler := newResourceLabeler(getGPUResourceName(&config.Resources, name), config)
+		resourceName, exclusive := sharedLabeler.deviceResourceName(positions[sharedLabeler.resourceName], index, device)
+		positions[sharedLabeler.resourceName]++
+
+		group := fullGPUGroup{name: name, resourceName: resourceName, exclusive: exclusive}
+		if exclusive || resourceName != sharedLabeler.resourceName {
+			partialCounts[group]++
+		}
+		fullGPUs[group] = device
+		replicas := 1
+		if !exclusive {
+			replicas = sharedLabeler.replicasForDevice(index, device)
+		}
+		deviceReplicas[group] = append(deviceReplicas[group], replicas)
 	}
 
 	if len(counts) > 1 {
@@ -157,7 +182,7 @@ func newGPULabelers(manager resource.Manager, config *spec.Config) (Labeler, err
 	// These do not include sharing information.
 	for name, migEnabledDevice := range migEnabledDevices {
 		// We generate a resource label with no sharing modifications
-		l, err := NewGPUResourceLabelerWithoutSharing(migEnabledDevice, counts[name])
+		l, err := newGPUResourceLabeler(getGPUResourceName(&config.Resources, name), nil, migEnabledDevice, counts[name], nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct labeler: %v", err)
 		}
@@ -167,8 +192,18 @@ func newGPULabelers(manager resource.Manager, config *spec.Config) (Labeler, err
 
 	// We construct labelers for the full GPUs.
 	// These override any resources with the same name that have MIG enabled.
-	for name, fullGPU := range fullGPUs {
-		l, err := NewGPUResourceLabeler(config, fullGPU, counts[name])
+	for group, fullGPU := range fullGPUs {
+		count := counts[group.name]
+		if c, ok := partialCounts[group]; ok {
+			count = c
+		}
+		groupConfig := config
+		if group.exclusive {
+			exclusiveConfig := *config
+			exclusiveConfig.Sharing = spec.Sharing{}
+			groupConfig = &exclusiveConfig
+		}
+		l, err := newGPUResourceLabeler(group.resourceName, groupConfig, fullGPU, count, deviceReplicas[group])
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct labeler: %v", err)
 		}
@@ -225,7 +260,7 @@ func newMigStrategySingleLabeler(manager resource.Manager, config *spec.Config)
 		// For the first occurrence we update the device reference and the resource name
 		if !exists {
 			resource.device = mig
-			resource.name = fullGPUResourceName
+			resource.name = getMIGResourceName(&config.Resources, name, fullGPUResourceName)
 		}
 		// We increase the count
 		resource.count++
@@ -285,7 +320,7 @@ func newMigStrategyMixedLabeler(manager resource.Manager, config *spec.Config) (
 		// For the first occurrence we update the device reference and the resource name
 		if !exists {
 			resource.device = mig
-			resource.name = spec.ResourceName("nvidia.com/mig-" + name)
+			resource.name = getMIGResourceName(&config.Resources, name, spec.ResourceName("nvidia.com/mig-"+name))
 		}
 		// We increase the count
 		resource.count++
@@ -299,6 +334,9 @@ func newMigStrategyMixedLabeler(manager resource.Manager, config *spec.Config) (
 func newMIGDeviceLabelers(resources map[string]migResource, config *spec.Config) (Labeler, error) {
 	var labelers list
 	for _, resource := range resources {
+		if r := newResourceLabeler(resource.name, config).replicationInfo(); r != nil && !sharesAllDevices(r) {
+			klog.Warningf("The labels for resource %v do not reflect that only some of its MIG devices are shared", resource.name)
+		}
 		l, err := NewMIGResourceLabeler(resource.name, config, resource.device, resource.count)
 		if err != nil {
 			return nil, fmt.Errorf("failed to construct labeler: %v", err)
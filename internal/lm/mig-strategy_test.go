@@ -102,6 +102,43 @@ func TestMigStrategyNoneLabels(t *testing.T) {
 				"nvidia.com/gpu.product":          "MOCKMODEL-SHARED",
 			},
 		},
+		{
+			description: "device replica overrides label minimum and total replicas",
+			devices: []resource.Device{
+				rt.NewFullGPU(),
+				rt.NewFullGPU(),
+				rt.NewDeviceMock(false).WithUUID("GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"),
+			},
+			timeSlicing: spec.ReplicatedResources{
+				Resources: []spec.ReplicatedResource{
+					{
+						Name:     "nvidia.com/gpu",
+						Replicas: 4,
+						DeviceReplicas: []spec.DeviceReplicas{
+							{
+								Devices:  spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"0"}},
+								Replicas: 2,
+							},
+							{
+								Devices:  spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"}},
+								Replicas: 7,
+							},
+						},
+					},
+				},
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.compute.major":    "8",
+				"nvidia.com/gpu.compute.minor":    "0",
+				"nvidia.com/gpu.family":           "ampere",
+				"nvidia.com/gpu.count":            "3",
+				"nvidia.com/gpu.replicas":         "2",
+				"nvidia.com/gpu.replicas.total":   "13",
+				"nvidia.com/gpu.sharing-strategy": "time-slicing",
+				"nvidia.com/gpu.memory":           "300",
+				"nvidia.com/gpu.product":          "MOCKMODEL-SHARED",
+			},
+		},
 		{
 			description: "sharing is not applied to single MIG device; replicas is zero",
 			devices: []resource.Device{
@@ -420,3 +457,116 @@ func TestMigStrategySingleLabels(t *testing.T) {
 func ptr[T any](x T) *T {
 	return &x
 }
+
+func TestResourceLabelsWithCustomResourceNames(t *testing.T) {
+	config := spec.Config{
+		Flags: spec.Flags{
+			CommandLineFlags: spec.CommandLineFlags{
+				MigStrategy: ptr(MigStrategyMixed),
+			},
+		},
+		Resources: spec.Resources{
+			GPUs: []spec.Resource{
+				{Pattern: "*A100*", Name: "nvidia.com/a100"},
+				{Pattern: "*L4", Name: "nvidia.com/l4"},
+			},
+			MIGs: []spec.Resource{
+				{Pattern: "1g.*", Name: "nvidia.com/a100-small"},
+			},
+		},
+		Sharing: spec.Sharing{
+			TimeSlicing: spec.ReplicatedResources{
+				Resources: []spec.ReplicatedResource{
+					{Name: "nvidia.com/l4", Replicas: 2},
+				},
+			},
+		},
+	}
+
+	manager := rt.NewManagerMockWithDevices(
+		rt.NewDeviceMock(false).WithName("NVIDIA A100-SXM4-40GB"),
+		rt.NewDeviceMock(false).WithName("NVIDIA L4"),
+		rt.NewDeviceMock(false).WithName("NVIDIA L4"),
+		rt.NewDeviceMock(true).WithName("NVIDIA H100").WithMigDevices(
+			rt.NewMigDevice(1, 1, 10),
+			rt.NewMigDevice(3, 3, 40),
+		),
+	)
+
+	labeler, err := NewResourceLabeler(manager, &config)
+	require.NoError(t, err)
+	labels, err := labeler.Labels()
+	require.NoError(t, err)
+
+	require.Equal(t, "NVIDIA-A100-SXM4-40GB", labels["nvidia.com/a100.product"])
+	require.Equal(t, "1", labels["nvidia.com/a100.count"])
+	require.Equal(t, "NVIDIA-L4-SHARED", labels["nvidia.com/l4.product"])
+	require.Equal(t, "2", labels["nvidia.com/l4.count"])
+	require.Equal(t, "2", labels["nvidia.com/l4.replicas"])
+	require.Equal(t, "time-slicing", labels["nvidia.com/l4.sharing-strategy"])
+	require.Equal(t, "NVIDIA-H100", labels["nvidia.com/gpu.product"])
+	require.Equal(t, "1", labels["nvidia.com/a100-small.count"])
+	require.Equal(t, "NVIDIA-H100-MIG-1g.10gb", labels["nvidia.com/a100-small.product"])
+	require.Equal(t, "1", labels["nvidia.com/mig-3g.40gb.count"])
+}
+
+func TestResourceLabelsWithPartialSharing(t *testing.T) {
+	testCases := []struct {
+		description string
+		devices     spec.ReplicatedDevices
+	}{
+		{
+			description: "devices selected by count",
+			devices:     spec.ReplicatedDevices{Count: 2},
+		},
+		{
+			description: "devices selected by index and UUID",
+			devices:     spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"0", "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config := spec.Config{
+				Flags: spec.Flags{
+					CommandLineFlags: spec.CommandLineFlags{
+						MigStrategy: ptr(MigStrategyNone),
+					},
+				},
+				Sharing: spec.Sharing{
+					TimeSlicing: spec.ReplicatedResources{
+						Resources: []spec.ReplicatedResource{
+							{
+								Name:     "nvidia.com/gpu",
+								Rename:   "nvidia.com/gpu.shared",
+								Devices:  tc.devices,
+								Replicas: 4,
+							},
+						},
+					},
+				},
+			}
+
+			manager := rt.NewManagerMockWithDevices(
+				rt.NewDeviceMock(false).WithUUID("GPU-4cf8db2d-06c0-7d70-1a51-e59b25b2c16c"),
+				rt.NewDeviceMock(false).WithUUID("GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"),
+				rt.NewDeviceMock(false).WithUUID("GPU-e8f1d8ad-33ad-4ddd-b2b5-0a5d2d1ea6a4"),
+			)
+
+			labeler, err := NewResourceLabeler(manager, &config)
+			require.NoError(t, err)
+			labels, err := labeler.Labels()
+			require.NoError(t, err)
+
+			require.Equal(t, "MOCKMODEL", labels["nvidia.com/gpu.product"])
+			require.Equal(t, "1", labels["nvidia.com/gpu.count"])
+			require.Equal(t, "1", labels["nvidia.com/gpu.replicas"])
+			require.Equal(t, "none", labels["nvidia.com/gpu.sharing-strategy"])
+			require.Equal(t, "MOCKMODEL", labels["nvidia.com/gpu.shared.product"])
+			require.Equal(t, "2", labels["nvidia.com/gpu.shared.count"])
+			require.Equal(t, "4", labels["nvidia.com/gpu.shared.replicas"])
+			require.Equal(t, "time-slicing", labels["nvidia.com/gpu.shared.sharing-strategy"])
+			require.Equal(t, "300", labels["nvidia.com/gpu.shared.memory"])
+		})
+	}
+}
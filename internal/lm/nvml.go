@@ -17,7 +17,6 @@
 package lm
 
 import (
-	"errors"
 	"fmt"
 	"strconv"
 	"strings"
@@ -30,8 +29,6 @@ import (
 	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
 )
 
-var errMPSSharingNotSupported = errors.New("MPS sharing is not supported")
-
 // NewDeviceLabeler creates a new labeler for the specified resource manager.
 func NewDeviceLabeler(manager resource.Manager, config *spec.Config) (Labeler, error) {
 	if err := manager.Init(); err != nil {
@@ -85,6 +82,15 @@ func NewDeviceLabeler(manager resource.Manager, config *spec.Config) (Labeler, e
 		return nil, fmt.Errorf("error creating IMEX labeler: %v", err)
 	}
 
+	perDeviceLabeler, err := newPerDeviceLabeler(config, devices)
+	if err != nil {
+		return nil, fmt.Errorf("error creating per-device labeler: %v", err)
+	}
+
+	topologyLabeler := newTopologyLabeler(devices)
+	hardwareLabeler := newHardwareLabeler(devices)
+	confComputeLabeler := newConfComputeLabeler(manager)
+
 	l := Merge(
 		machineTypeLabeler,
 		versionLabeler,
@@ -93,6 +99,10 @@ func NewDeviceLabeler(manager resource.Manager, config *spec.Config) (Labeler, e
 		resourceLabeler,
 		gpuModeLabeler,
 		imexLabeler,
+		perDeviceLabeler,
+		topologyLabeler,
+		hardwareLabeler,
+		confComputeLabeler,
 	)
 
 	return l, nil
@@ -198,13 +208,22 @@ func isMPSCapable(manager resource.Manager) (bool, error) {
 		return false, fmt.Errorf("failed to get device: %w", err)
 	}
 
+	// MPS is supported on MIG devices with a separate daemon per MIG device.
+	// A MIG-enabled GPU without any MIG devices has nothing to share though.
 	for _, d := range devices {
 		isMigEnabled, err := d.IsMigEnabled()
 		if err != nil {
 			return false, fmt.Errorf("failed to check if device is MIG-enabled: %w", err)
 		}
-		if isMigEnabled {
-			return false, fmt.Errorf("%w for mig devices", errMPSSharingNotSupported)
+		if !isMigEnabled {
+			continue
+		}
+		migs, err := d.GetMigDevices()
+		if err != nil {
+			return false, fmt.Errorf("failed to get MIG devices: %w", err)
+		}
+		if len(migs) == 0 {
+			return false, nil
 		}
 	}
 	return true, nil
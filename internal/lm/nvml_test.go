@@ -171,6 +171,9 @@ func TestSharingLabeler(t *testing.T) {
 							IsMigEnabledFunc: func() (bool, error) {
 								return true, nil
 							},
+							GetMigDevicesFunc: func() ([]resource.Device, error) {
+								return []resource.Device{&resource.DeviceMock{}}, nil
+							},
 						},
 					}
 					return devices, nil
@@ -187,8 +190,41 @@ func TestSharingLabeler(t *testing.T) {
 					},
 				},
 			},
-			expectedError:  errMPSSharingNotSupported,
-			expectedLabels: nil,
+			expectedLabels: map[string]string{
+				"nvidia.com/mps.capable": "true",
+			},
+		},
+		{
+			description: "config with mps replicas empty mig-devices",
+			manager: &resource.ManagerMock{
+				GetDevicesFunc: func() ([]resource.Device, error) {
+					devices := []resource.Device{
+						&resource.DeviceMock{
+							IsMigEnabledFunc: func() (bool, error) {
+								return true, nil
+							},
+							GetMigDevicesFunc: func() ([]resource.Device, error) {
+								return nil, nil
+							},
+						},
+					}
+					return devices, nil
+				},
+			},
+			config: &spec.Config{
+				Sharing: spec.Sharing{
+					MPS: &spec.ReplicatedResources{
+						Resources: []spec.ReplicatedResource{
+							{
+								Replicas: 2,
+							},
+						},
+					},
+				},
+			},
+			expectedLabels: map[string]string{
+				"nvidia.com/mps.capable": "false",
+			},
 		},
 	}
 
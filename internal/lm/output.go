@@ -134,6 +134,11 @@ func (n *nodeFeatureObject) Output(labels Labels) error {
 		return fmt.Errorf("failed to get NodeFeature object: %w", err)
 	}
 
+	features := *nfdv1alpha1.NewFeatures()
+	for name, instances := range getDeviceInstanceFeatures(labels) {
+		features.Instances[name] = instances
+	}
+
 	if errors.IsNotFound(err) {
 		klog.Infof("creating NodeFeature object %s", nodeFeatureName)
 		nfr = &nfdv1alpha1.NodeFeature{
@@ -142,7 +147,7 @@ func (n *nodeFeatureObject) Output(labels Labels) error {
 				Labels:          map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: nodename},
 				OwnerReferences: n.ownerRefs,
 			},
-			Spec: nfdv1alpha1.NodeFeatureSpec{Features: *nfdv1alpha1.NewFeatures(), Labels: labels},
+			Spec: nfdv1alpha1.NodeFeatureSpec{Features: features, Labels: labels},
 		}
 		nfrCreated, err := n.nfdClientset.NfdV1alpha1().NodeFeatures(namespace).Create(context.TODO(), nfr, metav1.CreateOptions{})
 		if err != nil {
@@ -154,7 +159,7 @@ func (n *nodeFeatureObject) Output(labels Labels) error {
 
 	nfrUpdated := nfr.DeepCopy()
 	nfrUpdated.Labels = map[string]string{nfdv1alpha1.NodeFeatureObjNodeNameLabel: nodename}
-	nfrUpdated.Spec = nfdv1alpha1.NodeFeatureSpec{Features: *nfdv1alpha1.NewFeatures(), Labels: labels}
+	nfrUpdated.Spec = nfdv1alpha1.NodeFeatureSpec{Features: features, Labels: labels}
 	nfrUpdated.OwnerReferences = n.ownerRefs
 
 	if apiequality.Semantic.DeepEqual(nfr, nfrUpdated) {
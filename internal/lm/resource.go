@@ -19,6 +19,7 @@ package lm
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"k8s.io/klog/v2"
@@ -32,11 +33,23 @@ const fullGPUResourceName = "nvidia.com/gpu"
 // NewGPUResourceLabelerWithoutSharing creates a resource labeler for the specified device that does not apply sharing labels.
 func NewGPUResourceLabelerWithoutSharing(device resource.Device, count int) (Labeler, error) {
 	// NOTE: We use a nil config to signal that sharing is disabled.
-	return NewGPUResourceLabeler(nil, device, count)
+	return newGPUResourceLabeler(fullGPUResourceName, nil, device, count, nil)
 }
 
 // NewGPUResourceLabeler creates a resource labeler for the specified full GPU device with the specified count
 func NewGPUResourceLabeler(config *spec.Config, device resource.Device, count int) (Labeler, error) {
+	model, err := device.GetName()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device model: %v", err)
+	}
+	return newGPUResourceLabeler(getGPUResourceName(getResources(config), model), config, device, count, nil)
+}
+
+// newGPUResourceLabeler creates a resource labeler for the specified full GPU
+// device under the specified resource name. Sharing labels are only applied if
+// a config is specified. If known, deviceReplicas holds the number of replicas
+// of each of the devices represented by the labeler.
+func newGPUResourceLabeler(resourceName spec.ResourceName, config *spec.Config, device resource.Device, count int, deviceReplicas []int) (Labeler, error) {
 	if count == 0 {
 		return empty{}, nil
 	}
@@ -51,7 +64,8 @@ func NewGPUResourceLabeler(config *spec.Config, device resource.Device, count in
 		klog.Warningf("Ignoring error getting memory info for device: %v", err)
 	}
 
-	resourceLabeler := newResourceLabeler(fullGPUResourceName, config)
+	resourceLabeler := newResourceLabeler(resourceName, config)
+	resourceLabeler.deviceReplicas = deviceReplicas
 
 	architectureLabels, err := newArchitectureLabels(resourceLabeler, device)
 	if err != nil {
@@ -66,6 +80,7 @@ func NewGPUResourceLabeler(config *spec.Config, device resource.Device, count in
 	labelers := Merge(
 		resourceLabeler.baseLabeler(count, model),
 		memoryLabeler,
+		resourceLabeler.replicaMemoryLabeler(device),
 		architectureLabels,
 	)
 
@@ -101,12 +116,50 @@ func NewMIGResourceLabeler(resourceName spec.ResourceName, config *spec.Config,
 
 	labelers := Merge(
 		resourceLabeler.baseLabeler(count, model, "MIG", migProfile),
+		resourceLabeler.replicaMemoryLabeler(device),
 		attributeLabels,
 	)
 
 	return labelers, nil
 }
 
+// getResources returns the resources of the specified config, if any.
+func getResources(config *spec.Config) *spec.Resources {
+	if config == nil {
+		return nil
+	}
+	return &config.Resources
+}
+
+// getGPUResourceName returns the name of the resource that the device plugin
+// advertises for a full GPU with the specified name. As in the device plugin,
+// this is the name of the first matching resource, or nvidia.com/gpu if no
+// resource matches.
+func getGPUResourceName(resources *spec.Resources, name string) spec.ResourceName {
+	if resources != nil {
+		for _, r := range resources.GPUs {
+			if r.Pattern.Matches(name) {
+				return r.Name
+			}
+		}
+	}
+	return fullGPUResourceName
+}
+
+// getMIGResourceName returns the name of the resource that the device plugin
+// advertises for a MIG device with the specified profile. If no resource
+// matches, the specified default name is returned.
+func getMIGResourceName(resources *spec.Resources, profile string, defaultName spec.ResourceName) spec.ResourceName {
+	if resources != nil {
+		for _, r := range resources.MIGs {
+			if r.Pattern.Matches(profile) {
+				return r.Name
+			}
+		}
+	}
+	return defaultName
+}
+
 func newResourceLabeler(resourceName spec.ResourceName, config *spec.Config) resourceLabeler {
 	var sharing *spec.Sharing
 	if config != nil {
@@ -122,6 +175,9 @@ func newResourceLabeler(resourceName spec.ResourceName, config *spec.Config) res
 type resourceLabeler struct {
 	resourceName spec.ResourceName
 	sharing      *spec.Sharing
+	// deviceReplicas optionally holds the number of replicas of each device
+	// of the resource. This may differ between devices.
+	deviceReplicas []int
 }
 
 // single creates a single label for the resource. The label key is
@@ -169,6 +225,11 @@ func (rl resourceLabeler) baseLabeler(count int, parts ...string) Labeler {
 		"replicas":         replicas,
 		"sharing-strategy": strategy,
 	}
+	// If devices have different numbers of replicas, the replicas label
+	// holds the minimum and the total is labelled separately.
+	if total := rl.totalReplicas(); total > 0 && total != replicas*len(rl.deviceReplicas) {
+		rawLabels["replicas.total"] = total
+	}
 
 	labels := make(Labels)
 	for k, v := range rawLabels {
@@ -177,6 +238,25 @@ func (rl resourceLabeler) baseLabeler(count int, parts ...string) Labeler {
 	return labels
 }
 
+// replicaMemoryLabeler generates the label for the memory (in MiB) that each
+// replica of the resource is limited to. No label is generated if the replica
+// memory is not limited.
+func (rl resourceLabeler) replicaMemoryLabeler(device resource.Device) Labeler {
+	r := rl.replicationInfo()
+	if r == nil || r.Replicas < 2 || r.MemoryLimit == nil {
+		return empty{}
+	}
+	totalMemoryMiB, err := device.GetTotalMemoryMiB()
+	if err != nil {
+		klog.Warningf("Ignoring error getting memory info for device: %v", err)
+	}
+	limitMiB := r.MemoryLimit.ForDevice(totalMemoryMiB*1024*1024) / 1024 / 1024
+	if limitMiB == 0 {
+		return empty{}
+	}
+	return rl.single("replica-memory", limitMiB)
+}
+
 // Deprecated
 func (rl resourceLabeler) productLabel(parts ...string) Labels {
 	name := rl.getProductName(parts...)
@@ -208,10 +288,90 @@ func (rl resourceLabeler) getProductName(parts ...string) string {
 func (rl resourceLabeler) getReplicas() int {
 	if rl.sharingDisabled() {
 		return 0
-	} else if r := rl.replicationInfo(); r != nil && r.Replicas > 0 {
+	}
+	r := rl.replicationInfo()
+	if r == nil || r.Replicas <= 0 {
+		return 1
+	}
+	if len(rl.deviceReplicas) == 0 {
 		return r.Replicas
 	}
-	return 1
+	replicas := rl.deviceReplicas[0]
+	for _, dr := range rl.deviceReplicas[1:] {
+		replicas = min(replicas, dr)
+	}
+	return replicas
+}
+
+// totalReplicas returns the total number of replicas across all devices of the
+// resource. This is 0 if the number of replicas per device is not known or the
+// resource is not shared.
+func (rl resourceLabeler) totalReplicas() int {
+	if !rl.isShared() {
+		return 0
+	}
+	var total int
+	for _, dr := range rl.deviceReplicas {
+		total += dr
+	}
+	return total
+}
+
+// replicasForDevice returns the number of replicas of the full GPU at the
+// specified index. The UUID of the device is only queried if the device
+// replica overrides for the resource reference devices by UUID.
+func (rl resourceLabeler) replicasForDevice(index int, device resource.Device) int {
+	r := rl.replicationInfo()
+	if r == nil || r.Replicas < 2 {
+		return 1
+	}
+	var referencesUUIDs bool
+	for _, dr := range r.DeviceReplicas {
+		for _, ref := range dr.Devices.List {
+			referencesUUIDs = referencesUUIDs || ref.IsUUID()
+		}
+	}
+	var uuid string
+	if referencesUUIDs {
+		id, err := device.GetUUID()
+		if err != nil {
+			klog.Warningf("Ignoring error getting UUID for device %d: %v", index, err)
+		}
+		uuid = id
+	}
+	return r.ReplicasFor(strconv.Itoa(index), uuid)
+}
+
+// deviceResourceName returns the name of the resource that the full GPU at the
+// specified index is labelled under and whether the device is labelled as an
+// exclusive (i.e. not shared) device. If only some of the devices of the
+// resource are shared, the shared devices are labelled under the name they
+// are renamed to and the remaining devices are exclusive. The position is the
+// number of devices of the resource that precede the device in index order.
+func (rl resourceLabeler) deviceResourceName(position int, index int, device resource.Device) (spec.ResourceName, bool) {
+	r := rl.replicationInfo()
+	if r == nil || r.Replicas < 2 || sharesAllDevices(r) {
+		return rl.resourceName, false
+	}
+	if r.Devices.Count > 0 && position < r.Devices.Count {
+		return r.Rename, false
+	}
+	for _, ref := range r.Devices.List {
+		if ref.IsGPUIndex() && string(ref) == strconv.Itoa(index) {
+			return r.Rename, false
+		}
+		if !ref.IsGpuUUID() {
+			continue
+		}
+		uuid, err := device.GetUUID()
+		if err != nil {
+			klog.Warningf("Ignoring error getting UUID for device %d: %v", index, err)
+		}
+		if string(ref) == uuid {
+			return r.Rename, false
+		}
+	}
+	return rl.resourceName, true
 }
 
 // sharingDisabled checks whether the resourceLabeler has sharing disabled
@@ -245,10 +405,21 @@ func (rl resourceLabeler) replicationInfo() *spec.ReplicatedResource {
 		if r.Name == rl.resourceName {
 			return &r
 		}
+		// If only some of the devices are shared, the shared devices are
+		// labelled under the name they are renamed to.
+		if !sharesAllDevices(&r) && r.Rename == rl.resourceName {
+			return &r
+		}
 	}
 	return nil
 }
 
+// sharesAllDevices checks whether all devices of the replicated resource are
+// shared. This is also the case if the devices to share are not specified.
+func sharesAllDevices(r *spec.ReplicatedResource) bool {
+	return r.Devices.All || (r.Devices.Count == 0 && len(r.Devices.List) == 0)
+}
+
 func newMigAttributeLabels(rl resourceLabeler, device resource.Device) (Labels, error) {
 	attributes, err := device.GetAttributes()
 	if err != nil {
@@ -99,6 +99,32 @@ func TestGPUResourceLabeler(t *testing.T) {
 				"nvidia.com/gpu.compute.minor":    "0",
 			},
 		},
+		{
+			description: "time-slicing with memory limit adds replica memory",
+			count:       1,
+			sharing: spec.Sharing{
+				TimeSlicing: spec.ReplicatedResources{
+					Resources: []spec.ReplicatedResource{
+						{
+							Name:        "nvidia.com/gpu",
+							Replicas:    3,
+							MemoryLimit: &spec.ReplicaMemoryLimit{Percent: 30},
+						},
+					},
+				},
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.count":            "1",
+				"nvidia.com/gpu.replicas":         "3",
+				"nvidia.com/gpu.sharing-strategy": "time-slicing",
+				"nvidia.com/gpu.memory":           "300",
+				"nvidia.com/gpu.replica-memory":   "90",
+				"nvidia.com/gpu.product":          "MOCKMODEL-SHARED",
+				"nvidia.com/gpu.family":           "ampere",
+				"nvidia.com/gpu.compute.major":    "8",
+				"nvidia.com/gpu.compute.minor":    "0",
+			},
+		},
 		{
 			description: "time-slicing renamed does not append suffix and doubles count",
 			count:       1,
@@ -0,0 +1,184 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"fmt"
+	"strconv"
+
+	"k8s.io/klog/v2"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// Constants for the values of the nvidia.com/gpu.nvlink.topology label.
+const (
+	nvlinkTopologyNone     = "none"
+	nvlinkTopologyDirect   = "direct"
+	nvlinkTopologyNVSwitch = "nvswitch"
+)
+
+// newTopologyLabeler creates a labeler that reports how the GPUs on the node
+// are interconnected. Topology information that cannot be queried for all
+// GPUs is logged and not labeled. Information that is not supported for the
+// devices is only logged at the info level.
+func newTopologyLabeler(devices []resource.Device) Labeler {
+	return Merge(
+		newNVLinkLabeler(devices),
+		newPCIeLabeler(devices),
+		newNUMALabeler(devices),
+	)
+}
+
+// newNVLinkLabeler creates a labeler for the NVLinks of the GPUs. This reports
+// the lowest number of active NVLinks of any GPU and whether the GPUs are
+// connected through NVSwitches, directly, or not at all. The GPUs are fully
+// connected if each GPU can reach each other GPU over NVLink.
+func newNVLinkLabeler(devices []resource.Device) Labeler {
+	topology, count, fullyConnected, err := getNVLinkTopology(devices)
+	if err != nil {
+		logHardwareError("NVLink topology", err)
+		return empty{}
+	}
+
+	labels := Labels{
+		"nvidia.com/gpu.nvlink.count":           strconv.Itoa(count),
+		"nvidia.com/gpu.nvlink.topology":        topology,
+		"nvidia.com/gpu.nvlink.fully-connected": strconv.FormatBool(fullyConnected),
+	}
+	return labels
+}
+
+func getNVLinkTopology(devices []resource.Device) (string, int, bool, error) {
+	busIDs := make(map[string]int)
+	for i, d := range devices {
+		busID, err := d.GetPCIBusID()
+		if err != nil {
+			return "", 0, false, fmt.Errorf("error getting PCI bus ID: %w", err)
+		}
+		busIDs[busID] = i
+	}
+
+	minLinks := -1
+	allSwitched := true
+	anyLinks := false
+	allPeersLinked := true
+	for i, d := range devices {
+		links, err := d.GetNVLinks()
+		if err != nil {
+			return "", 0, false, fmt.Errorf("error getting NVLinks: %w", err)
+		}
+		if minLinks < 0 || len(links) < minLinks {
+			minLinks = len(links)
+		}
+		if len(links) > 0 {
+			anyLinks = true
+		}
+
+		switched := false
+		peers := make(map[int]bool)
+		for _, link := range links {
+			if link.RemoteType == resource.NVLinkRemoteSwitch {
+				switched = true
+				continue
+			}
+			// Older drivers do not report the type of the remote device so
+			// we also identify peers by their PCI bus ID.
+			if peer, exists := busIDs[link.RemotePCIBusID]; exists && peer != i {
+				peers[peer] = true
+			}
+		}
+		if !switched {
+			allSwitched = false
+		}
+		if len(peers) != len(devices)-1 {
+			allPeersLinked = false
+		}
+	}
+
+	switch {
+	case !anyLinks:
+		return nvlinkTopologyNone, minLinks, false, nil
+	case allSwitched:
+		return nvlinkTopologyNVSwitch, minLinks, true, nil
+	default:
+		return nvlinkTopologyDirect, minLinks, allPeersLinked && len(devices) > 1, nil
+	}
+}
+
+// newPCIeLabeler creates a labeler for the PCIe links of the GPUs. This
+// reports the lowest PCIe generation and width of any GPU.
+func newPCIeLabeler(devices []resource.Device) Labeler {
+	minGeneration, minWidth := -1, -1
+	for _, d := range devices {
+		generation, width, err := d.GetPCIeLink()
+		if err != nil {
+			logHardwareError("PCIe link", err)
+			return empty{}
+		}
+		if minGeneration < 0 || generation < minGeneration {
+			minGeneration = generation
+		}
+		if minWidth < 0 || width < minWidth {
+			minWidth = width
+		}
+	}
+	if minGeneration <= 0 || minWidth <= 0 {
+		return empty{}
+	}
+
+	labels := Labels{
+		"nvidia.com/gpu.pcie.generation": strconv.Itoa(minGeneration),
+		"nvidia.com/gpu.pcie.width":      strconv.Itoa(minWidth),
+	}
+	return labels
+}
+
+// newNUMALabeler creates a labeler for the NUMA nodes of the GPUs. This
+// reports the number of NUMA nodes that GPUs are attached to and whether each
+// of these nodes has the same number of GPUs.
+func newNUMALabeler(devices []resource.Device) Labeler {
+	gpusPerNode := make(map[int]int)
+	for _, d := range devices {
+		known, node, err := d.GetNumaNode()
+		if err != nil {
+			logHardwareError("NUMA node", err)
+			return empty{}
+		}
+		if !known {
+			klog.Infof("NUMA node of GPU is unknown; not labeling NUMA topology")
+			return empty{}
+		}
+		gpusPerNode[node]++
+	}
+	if len(gpusPerNode) == 0 {
+		return empty{}
+	}
+
+	balanced := true
+	for _, count := range gpusPerNode {
+		if count != len(devices)/len(gpusPerNode) {
+			balanced = false
+		}
+	}
+
+	labels := Labels{
+		"nvidia.com/gpu.numa.count":    strconv.Itoa(len(gpusPerNode)),
+		"nvidia.com/gpu.numa.balanced": strconv.FormatBool(balanced),
+	}
+	return labels
+}
@@ -0,0 +1,133 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package lm
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+	rt "github.com/NVIDIA/k8s-device-plugin/internal/resource/testing"
+)
+
+func TestTopologyLabeler(t *testing.T) {
+	toGPU := func(busID string) resource.NVLink {
+		return resource.NVLink{RemoteType: resource.NVLinkRemoteGPU, RemotePCIBusID: busID}
+	}
+	toUnknown := func(busID string) resource.NVLink {
+		return resource.NVLink{RemoteType: resource.NVLinkRemoteUnknown, RemotePCIBusID: busID}
+	}
+	toSwitch := resource.NVLink{RemoteType: resource.NVLinkRemoteSwitch, RemotePCIBusID: "0000:ff:00.0"}
+
+	testCases := []struct {
+		description    string
+		devices        []resource.Device
+		expectedLabels Labels
+	}{
+		{
+			description: "no nvlink and unknown numa node",
+			devices: []resource.Device{
+				rt.NewFullGPU(),
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.nvlink.count":           "0",
+				"nvidia.com/gpu.nvlink.topology":        "none",
+				"nvidia.com/gpu.nvlink.fully-connected": "false",
+				"nvidia.com/gpu.pcie.generation":        "4",
+				"nvidia.com/gpu.pcie.width":             "16",
+			},
+		},
+		{
+			description: "nvswitch",
+			devices: []resource.Device{
+				rt.NewDeviceMock(false).WithPCIBusID("0000:07:00.0").WithNVLinks(toSwitch, toSwitch).WithPCIeLink(5, 16).WithNumaNode(0),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:0f:00.0").WithNVLinks(toSwitch, toSwitch).WithPCIeLink(5, 16).WithNumaNode(1),
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.nvlink.count":           "2",
+				"nvidia.com/gpu.nvlink.topology":        "nvswitch",
+				"nvidia.com/gpu.nvlink.fully-connected": "true",
+				"nvidia.com/gpu.pcie.generation":        "5",
+				"nvidia.com/gpu.pcie.width":             "16",
+				"nvidia.com/gpu.numa.count":             "2",
+				"nvidia.com/gpu.numa.balanced":          "true",
+			},
+		},
+		{
+			description: "direct links between all gpus",
+			devices: []resource.Device{
+				rt.NewDeviceMock(false).WithPCIBusID("0000:01:00.0").WithNVLinks(toGPU("0000:02:00.0"), toUnknown("0000:03:00.0")).WithNumaNode(0),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:02:00.0").WithNVLinks(toGPU("0000:01:00.0"), toGPU("0000:03:00.0")).WithNumaNode(0),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:03:00.0").WithNVLinks(toGPU("0000:01:00.0"), toGPU("0000:02:00.0"), toGPU("0000:02:00.0")).WithPCIeLink(3, 8).WithNumaNode(1),
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.nvlink.count":           "2",
+				"nvidia.com/gpu.nvlink.topology":        "direct",
+				"nvidia.com/gpu.nvlink.fully-connected": "true",
+				"nvidia.com/gpu.pcie.generation":        "3",
+				"nvidia.com/gpu.pcie.width":             "8",
+				"nvidia.com/gpu.numa.count":             "2",
+				"nvidia.com/gpu.numa.balanced":          "false",
+			},
+		},
+		{
+			description: "nvlink bridged pairs",
+			devices: []resource.Device{
+				rt.NewDeviceMock(false).WithPCIBusID("0000:01:00.0").WithNVLinks(toGPU("0000:02:00.0")),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:02:00.0").WithNVLinks(toGPU("0000:01:00.0")),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:03:00.0").WithNVLinks(toGPU("0000:04:00.0")),
+				rt.NewDeviceMock(false).WithPCIBusID("0000:04:00.0").WithNVLinks(toGPU("0000:03:00.0")),
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.nvlink.count":           "1",
+				"nvidia.com/gpu.nvlink.topology":        "direct",
+				"nvidia.com/gpu.nvlink.fully-connected": "false",
+				"nvidia.com/gpu.pcie.generation":        "4",
+				"nvidia.com/gpu.pcie.width":             "16",
+			},
+		},
+		{
+			description: "unsupported queries are not labeled",
+			devices: []resource.Device{
+				func() resource.Device {
+					d := rt.NewDeviceMock(false).WithNumaNode(0)
+					d.GetNVLinksFunc = func() ([]resource.NVLink, error) {
+						return nil, fmt.Errorf("GetNVLinks is %w", resource.ErrNotSupported)
+					}
+					d.GetPCIeLinkFunc = func() (int, int, error) {
+						return 0, 0, fmt.Errorf("failed to get PCIe link")
+					}
+					return d
+				}(),
+			},
+			expectedLabels: Labels{
+				"nvidia.com/gpu.numa.count":    "1",
+				"nvidia.com/gpu.numa.balanced": "true",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			labels, err := newTopologyLabeler(tc.devices).Labels()
+			require.NoError(t, err)
+			require.EqualValues(t, tc.expectedLabels, labels)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+const namespace = "nvidia_device_plugin"
+
+// DevicePlugin is the registry containing the device plugin metrics.
+var DevicePlugin = prometheus.NewRegistry()
+
+var factory = promauto.With(DevicePlugin)
+
+// The metrics exported by the device plugin.
+var (
+	// Requests counts the device plugin API requests per resource and method.
+	Requests = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "requests_total",
+		Help:      "Number of device plugin API requests.",
+	}, []string{"resource", "method"})
+	// RequestErrors counts the failed device plugin API requests per resource and method.
+	RequestErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "request_errors_total",
+		Help:      "Number of failed device plugin API requests.",
+	}, []string{"resource", "method"})
+	// RequestDuration tracks the latency of device plugin API requests per resource and method.
+	RequestDuration = factory.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "request_duration_seconds",
+		Help:      "Latency of device plugin API requests in seconds.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"resource", "method"})
+	// HealthTransitions counts the changes in the health of the devices of a
+	// resource. Devices are not labeled individually since each replica of a
+	// GPU would otherwise add a time series.
+	HealthTransitions = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "device_health_transitions_total",
+		Help:      "Number of device health transitions by the resulting health.",
+	}, []string{"resource", "health"})
+	// XidErrors counts the XID critical errors observed per GPU. This includes
+	// XIDs that are ignored for health checks.
+	XidErrors = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "xid_errors_total",
+		Help:      "Number of XID critical errors observed.",
+	}, []string{"resource", "gpu", "xid"})
+	// Replicas reports the number of replicas advertised for each physical GPU.
+	Replicas = factory.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "replicas",
+		Help:      "Number of replicas advertised per GPU.",
+	}, []string{"resource", "gpu"})
+	// ServerRestarts counts the restarts of the gRPC server of a plugin after it crashed.
+	ServerRestarts = factory.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "grpc_server_restarts_total",
+		Help:      "Number of restarts of the device plugin gRPC server after a crash.",
+	}, []string{"resource"})
+)
@@ -0,0 +1,49 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package metrics
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevicePluginRegistry(t *testing.T) {
+	HealthTransitions.WithLabelValues("nvidia.com/gpu", "Unhealthy").Inc()
+	defer HealthTransitions.Reset()
+	Replicas.WithLabelValues("nvidia.com/gpu", "GPU-0").Set(4)
+	defer Replicas.Reset()
+
+	families, err := DevicePlugin.Gather()
+	require.NoError(t, err)
+
+	labels := make(map[string][]string)
+	for _, f := range families {
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				labels[f.GetName()] = append(labels[f.GetName()], l.GetName()+"="+l.GetValue())
+			}
+		}
+	}
+	require.Equal(t,
+		map[string][]string{
+			"nvidia_device_plugin_device_health_transitions_total": {"health=Unhealthy", "resource=nvidia.com/gpu"},
+			"nvidia_device_plugin_replicas":                        {"gpu=GPU-0", "resource=nvidia.com/gpu"},
+		},
+		labels,
+	)
+}
@@ -49,6 +49,40 @@ type options struct {
 
 // New a new set of plugins with the supplied options.
 func New(ctx context.Context, infolib info.Interface, nvmllib nvml.Interface, devicelib device.Interface, opts ...Option) ([]Interface, error) {
+	o := newOptions(infolib, nvmllib, devicelib, opts...)
+	if o.config == nil {
+		klog.Warning("no config provided, returning a null manager")
+		return nil, nil
+	}
+
+	resourceManagers, err := o.getValidatedResourceManagers()
+	if err != nil {
+		return nil, err
+	}
+
+	var plugins []Interface
+	for _, resourceManager := range resourceManagers {
+		plugin, err := o.devicePluginForResource(ctx, resourceManager)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create plugin: %w", err)
+		}
+		plugins = append(plugins, plugin)
+	}
+	return plugins, nil
+}
+
+// NewResourceManagers constructs the resource managers for the supplied
+// options without creating device plugins for them. This allows the devices
+// to be served through other APIs such as DRA.
+func NewResourceManagers(infolib info.Interface, nvmllib nvml.Interface, devicelib device.Interface, opts ...Option) ([]rm.ResourceManager, error) {
+	o := newOptions(infolib, nvmllib, devicelib, opts...)
+	if o.config == nil {
+		return nil, fmt.Errorf("no config provided")
+	}
+	return o.getValidatedResourceManagers()
+}
+
+func newOptions(infolib info.Interface, nvmllib nvml.Interface, devicelib device.Interface, opts ...Option) *options {
 	o := &options{
 		infolib:   infolib,
 		nvmllib:   nvmllib,
@@ -57,16 +91,15 @@ func New(ctx context.Context, infolib info.Interface, nvmllib nvml.Interface, de
 	for _, opt := range opts {
 		opt(o)
 	}
-
-	if o.config == nil {
-		klog.Warning("no config provided, returning a null manager")
-		return nil, nil
-	}
-
 	if o.cdiHandler == nil {
 		o.cdiHandler = cdi.NewNullHandler()
 	}
+	return o
+}
 
+// getValidatedResourceManagers checks that a device discovery strategy is
+// set before constructing the resource managers.
+func (o *options) getValidatedResourceManagers() ([]rm.ResourceManager, error) {
 	if o.deviceDiscoveryStrategy == "" {
 		return nil, fmt.Errorf("device discovery strategy not set")
 	}
@@ -75,16 +108,7 @@ func New(ctx context.Context, infolib info.Interface, nvmllib nvml.Interface, de
 	if err != nil {
 		return nil, fmt.Errorf("failed to construct resource managers: %w", err)
 	}
-
-	var plugins []Interface
-	for _, resourceManager := range resourceManagers {
-		plugin, err := o.devicePluginForResource(ctx, resourceManager)
-		if err != nil {
-			return nil, fmt.Errorf("failed to create plugin: %w", err)
-		}
-		plugins = append(plugins, plugin)
-	}
-	return plugins, nil
+	return resourceManagers, nil
 }
 
 // getResourceManager constructs a set of resource managers.
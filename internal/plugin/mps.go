@@ -19,7 +19,10 @@ package plugin
 import (
 	"errors"
 	"fmt"
+	"sync"
+	"time"
 
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
 
@@ -28,11 +31,42 @@ import (
 	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
 )
 
+const (
+	// mpsHealthCheckInterval is the interval at which the MPS daemon health
+	// is checked while the plugin is running.
+	mpsHealthCheckInterval = 30 * time.Second
+	// mpsHealthSource is the source of the health events sent when the
+	// health of an MPS daemon changes.
+	mpsHealthSource = "mps"
+)
+
+// mpsStartupBackoff defines the retries when waiting for the MPS daemon on
+// startup. With these settings the plugin waits for about three minutes.
+var mpsStartupBackoff = wait.Backoff{
+	Duration: 1 * time.Second,
+	Factor:   2,
+	Steps:    10,
+	Cap:      30 * time.Second,
+}
+
+// mpsDaemon defines the subset of the MPS daemon used by the plugin.
+type mpsDaemon interface {
+	AssertHealthy() error
+	PipeDir() string
+	ShmDir() string
+}
+
 type mpsOptions struct {
 	enabled      bool
 	resourceName spec.ResourceName
-	daemon       *mps.Daemon
-	hostRoot     mps.Root
+	// daemons holds the MPS daemons for the resource keyed by the UUID of the
+	// MIG device they are responsible for. A daemon that is responsible for all
+	// (full GPU) devices of the resource has an empty key.
+	daemons  map[string]mpsDaemon
+	hostRoot mps.Root
+
+	startupBackoff      wait.Backoff
+	healthCheckInterval time.Duration
 }
 
 // getMPSOptions returns the MPS options specified for the resource manager.
@@ -41,51 +75,181 @@ func (o *options) getMPSOptions(resourceManager rm.ResourceManager) (mpsOptions,
 	if o.config.Sharing.SharingStrategy() != spec.SharingStrategyMPS {
 		return mpsOptions{}, nil
 	}
+	// If only some devices are shared, no MPS daemon is started for the
+	// resource of the devices that are not shared.
+	if !rm.AnnotatedIDs(resourceManager.Devices().GetIDs()).AnyHasAnnotations() {
+		return mpsOptions{}, nil
+	}
 
 	// TODO: It might make sense to pull this logic into a resource manager.
+	daemons := make(map[string]mpsDaemon)
 	for _, device := range resourceManager.Devices() {
-		if device.IsMigDevice() {
-			return mpsOptions{}, errors.New("sharing using MPS is not supported for MIG devices")
+		if !device.IsMigDevice() {
+			continue
 		}
+		uuid := device.GetUUID()
+		if _, exists := daemons[uuid]; !exists {
+			daemons[uuid] = mps.NewMigDaemon(resourceManager, mps.ContainerRoot, uuid)
+		}
+	}
+	if len(daemons) == 0 {
+		daemons[""] = mps.NewDaemon(resourceManager, mps.ContainerRoot)
 	}
 
 	m := mpsOptions{
 		enabled:      true,
 		resourceName: resourceManager.Resource(),
-		daemon:       mps.NewDaemon(resourceManager, mps.ContainerRoot),
+		daemons:      daemons,
 		hostRoot:     mps.Root(*o.config.Flags.MpsRoot),
+
+		startupBackoff:      mpsStartupBackoff,
+		healthCheckInterval: mpsHealthCheckInterval,
 	}
 	return m, nil
 }
 
+// waitForDaemon waits for the MPS daemons for the resource to become healthy.
+// The health of each daemon is checked with an exponential backoff and an error
+// is returned if a daemon is not healthy once the retries are exhausted.
 func (m *mpsOptions) waitForDaemon() error {
 	if m == nil || !m.enabled {
 		return nil
 	}
-	// TODO: Check the .ready file here.
-	// TODO: Have some retry strategy here.
-	if err := m.daemon.AssertHealthy(); err != nil {
-		return fmt.Errorf("error checking MPS daemon health: %w", err)
+	for migUUID, daemon := range m.daemons {
+		var lastErr error
+		err := wait.ExponentialBackoff(m.startupBackoff, func() (bool, error) {
+			lastErr = daemon.AssertHealthy()
+			if lastErr != nil {
+				klog.InfoS("Waiting for MPS daemon", "resource", m.resourceName, "migDevice", migUUID, "error", lastErr)
+				return false, nil
+			}
+			return true, nil
+		})
+		if err != nil {
+			return fmt.Errorf("error checking MPS daemon health: %w", errors.Join(err, lastErr))
+		}
 	}
 	klog.InfoS("MPS daemon is healthy", "resource", m.resourceName)
 	return nil
 }
 
-func (m *mpsOptions) updateReponse(response *pluginapi.ContainerAllocateResponse) {
+// monitorDaemon periodically checks the health of the MPS daemons until the
+// stop channel is closed. If a daemon becomes unhealthy all the devices it is
+// responsible for are marked unhealthy. Once the daemon has recovered, these
+// are marked healthy again unless another health check still considers them
+// unhealthy.
+func (m *mpsOptions) monitorDaemon(stop <-chan interface{}, devices rm.Devices, health chan<- *rm.HealthEvent) {
 	if m == nil || !m.enabled {
 		return
 	}
+
+	var wg sync.WaitGroup
+	for migUUID, daemon := range m.daemons {
+		daemonDevices := devices
+		if migUUID != "" {
+			daemonDevices = make(rm.Devices)
+			for id, d := range devices {
+				if d.GetUUID() == migUUID {
+					daemonDevices[id] = d
+				}
+			}
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			m.monitor(stop, migUUID, daemon, daemonDevices, health)
+		}()
+	}
+	wg.Wait()
+}
+
+// monitor checks the health of a single MPS daemon until the stop channel is
+// closed and sends health events for the specified devices on each change.
+func (m *mpsOptions) monitor(stop <-chan interface{}, migUUID string, daemon mpsDaemon, devices rm.Devices, health chan<- *rm.HealthEvent) {
+	ticker := time.NewTicker(m.healthCheckInterval)
+	defer ticker.Stop()
+
+	healthy := true
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		err := daemon.AssertHealthy()
+		if (err == nil) == healthy {
+			continue
+		}
+		healthy = err == nil
+
+		var event func(*rm.Device, string) *rm.HealthEvent
+		var reason string
+		if healthy {
+			klog.InfoS("MPS daemon has recovered", "resource", m.resourceName, "migDevice", migUUID)
+			event = rm.NewHealthyEvent
+			reason = "MPS daemon recovered"
+		} else {
+			klog.ErrorS(err, "MPS daemon is unhealthy", "resource", m.resourceName, "migDevice", migUUID)
+			event = rm.NewUnhealthyEvent
+			reason = fmt.Sprintf("MPS daemon is unhealthy: %v", err)
+		}
+		for _, d := range devices {
+			select {
+			case <-stop:
+				return
+			case health <- withSource(event(d, reason), mpsHealthSource):
+			}
+		}
+	}
+}
+
+// withSource sets the source of the specified health event.
+func withSource(event *rm.HealthEvent, source string) *rm.HealthEvent {
+	event.Source = source
+	return event
+}
+
+// updateReponse adds the pipe and shm directories of the MPS daemon for the
+// requested devices to the response. Since a container can only connect to a
+// single MPS daemon, requesting replicas of multiple MIG devices is an error.
+func (m *mpsOptions) updateReponse(response *pluginapi.ContainerAllocateResponse, requestIds []string) error {
+	if m == nil || !m.enabled {
+		return nil
+	}
+
+	var migUUID string
+	if _, exists := m.daemons[""]; !exists {
+		for _, id := range requestIds {
+			uuid := rm.AnnotatedID(id).GetID()
+			if migUUID != "" && migUUID != uuid {
+				return fmt.Errorf("requesting replicas of multiple MIG devices (%v, %v) is not supported with MPS", migUUID, uuid)
+			}
+			migUUID = uuid
+		}
+	}
+	daemon, exists := m.daemons[migUUID]
+	if !exists {
+		return fmt.Errorf("no MPS daemon for device %q", migUUID)
+	}
+
+	hostPipeDir := m.hostRoot.PipeDir(m.resourceName)
+	if migUUID != "" {
+		hostPipeDir = m.hostRoot.MigPipeDir(m.resourceName, migUUID)
+	}
+
 	// TODO: We should check that the deviceIDs are shared using MPS.
-	response.Envs["CUDA_MPS_PIPE_DIRECTORY"] = m.daemon.PipeDir()
+	response.Envs["CUDA_MPS_PIPE_DIRECTORY"] = daemon.PipeDir()
 
 	response.Mounts = append(response.Mounts,
 		&pluginapi.Mount{
-			ContainerPath: m.daemon.PipeDir(),
-			HostPath:      m.hostRoot.PipeDir(m.resourceName),
+			ContainerPath: daemon.PipeDir(),
+			HostPath:      hostPipeDir,
 		},
 		&pluginapi.Mount{
-			ContainerPath: m.daemon.ShmDir(),
+			ContainerPath: daemon.ShmDir(),
 			HostPath:      m.hostRoot.ShmDir(m.resourceName),
 		},
 	)
+	return nil
 }
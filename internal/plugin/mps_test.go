@@ -0,0 +1,311 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plugin
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/util/wait"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+// fakeMPSDaemon is an MPS daemon that returns the configured errors from
+// successive calls to AssertHealthy. Once the errors are exhausted, the last
+// error is returned.
+type fakeMPSDaemon struct {
+	sync.Mutex
+	errs    []error
+	calls   int
+	pipeDir string
+}
+
+func (d *fakeMPSDaemon) AssertHealthy() error {
+	d.Lock()
+	defer d.Unlock()
+	d.calls++
+	if len(d.errs) == 0 {
+		return nil
+	}
+	err := d.errs[0]
+	if len(d.errs) > 1 {
+		d.errs = d.errs[1:]
+	}
+	return err
+}
+
+func (d *fakeMPSDaemon) PipeDir() string {
+	if d.pipeDir != "" {
+		return d.pipeDir
+	}
+	return "/mps/pipe"
+}
+
+func (d *fakeMPSDaemon) ShmDir() string {
+	return "/dev/shm"
+}
+
+func TestMPSWaitForDaemon(t *testing.T) {
+	errNotStarted := errors.New("not started")
+	testCases := []struct {
+		description   string
+		errs          []error
+		expectedError bool
+		expectedCalls int
+	}{
+		{
+			description:   "healthy on first check",
+			expectedCalls: 1,
+		},
+		{
+			description:   "healthy after retries",
+			errs:          []error{errNotStarted, errNotStarted, nil},
+			expectedCalls: 3,
+		},
+		{
+			description:   "retries exhausted",
+			errs:          []error{errNotStarted},
+			expectedError: true,
+			expectedCalls: 4,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			daemon := &fakeMPSDaemon{errs: tc.errs}
+			m := &mpsOptions{
+				enabled:        true,
+				daemons:        map[string]mpsDaemon{"": daemon},
+				startupBackoff: wait.Backoff{Duration: time.Millisecond, Factor: 1, Steps: 4},
+			}
+
+			err := m.waitForDaemon()
+			if tc.expectedError {
+				require.ErrorIs(t, err, errNotStarted)
+			} else {
+				require.NoError(t, err)
+			}
+			require.Equal(t, tc.expectedCalls, daemon.calls)
+		})
+	}
+}
+
+func TestMPSMonitorDaemon(t *testing.T) {
+	errDown := errors.New("daemon down")
+	daemon := &fakeMPSDaemon{errs: []error{nil, errDown, errDown, nil}}
+	m := &mpsOptions{
+		enabled:             true,
+		daemons:             map[string]mpsDaemon{"": daemon},
+		healthCheckInterval: time.Millisecond,
+	}
+
+	devices := rm.Devices{
+		"GPU-0::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::0", Health: pluginapi.Healthy}},
+		"GPU-0::1": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::1", Health: pluginapi.Healthy}},
+	}
+
+	stop := make(chan interface{})
+	defer close(stop)
+	health := make(chan *rm.HealthEvent)
+	go m.monitorDaemon(stop, devices, health)
+
+	for _, expected := range []string{pluginapi.Unhealthy, pluginapi.Healthy} {
+		seen := make(map[string]bool)
+		for range devices {
+			select {
+			case event := <-health:
+				require.Equal(t, expected, event.Health)
+				require.Equal(t, mpsHealthSource, event.Source)
+				seen[event.Device.ID] = true
+			case <-time.After(time.Second):
+				t.Fatalf("timed out waiting for %v event", expected)
+			}
+		}
+		require.Len(t, seen, len(devices))
+	}
+}
+
+func TestMPSMonitorDaemonDisabled(t *testing.T) {
+	m := &mpsOptions{}
+	done := make(chan struct{})
+	go func() {
+		m.monitorDaemon(nil, nil, nil)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("monitor did not return for disabled MPS")
+	}
+}
+
+func TestGetMPSOptionsForPartiallySharedDevices(t *testing.T) {
+	o := &options{
+		config: &spec.Config{
+			Flags: spec.Flags{CommandLineFlags: spec.CommandLineFlags{MpsRoot: ptr("/run/nvidia/mps")}},
+			Sharing: spec.Sharing{
+				MPS: &spec.ReplicatedResources{
+					Resources: []spec.ReplicatedResource{
+						{
+							Name:     "nvidia.com/gpu",
+							Rename:   "nvidia.com/gpu.shared",
+							Devices:  spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"0"}},
+							Replicas: 2,
+						},
+					},
+				},
+			},
+		},
+	}
+
+	shared := &rm.ResourceManagerMock{
+		ResourceFunc: func() spec.ResourceName { return "nvidia.com/gpu.shared" },
+		DevicesFunc: func() rm.Devices {
+			return rm.Devices{
+				"GPU-0::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::0"}, Index: "0"},
+				"GPU-0::1": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::1"}, Index: "0"},
+			}
+		},
+	}
+	m, err := o.getMPSOptions(shared)
+	require.NoError(t, err)
+	require.True(t, m.enabled)
+	require.Len(t, m.daemons, 1)
+
+	exclusive := &rm.ResourceManagerMock{
+		ResourceFunc: func() spec.ResourceName { return "nvidia.com/gpu" },
+		DevicesFunc: func() rm.Devices {
+			return rm.Devices{
+				"GPU-1": &rm.Device{Device: pluginapi.Device{ID: "GPU-1"}, Index: "1"},
+			}
+		},
+	}
+	m, err = o.getMPSOptions(exclusive)
+	require.NoError(t, err)
+	require.False(t, m.enabled)
+	require.Empty(t, m.daemons)
+}
+
+func TestMPSMonitorMigDaemons(t *testing.T) {
+	errDown := errors.New("daemon down")
+	m := &mpsOptions{
+		enabled: true,
+		daemons: map[string]mpsDaemon{
+			"MIG-0": &fakeMPSDaemon{errs: []error{errDown}},
+			"MIG-1": &fakeMPSDaemon{},
+		},
+		healthCheckInterval: time.Millisecond,
+	}
+
+	devices := rm.Devices{
+		"MIG-0::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-0::0", Health: pluginapi.Healthy}, Index: "0:0"},
+		"MIG-0::1": &rm.Device{Device: pluginapi.Device{ID: "MIG-0::1", Health: pluginapi.Healthy}, Index: "0:0"},
+		"MIG-1::0": &rm.Device{Device: pluginapi.Device{ID: "MIG-1::0", Health: pluginapi.Healthy}, Index: "0:1"},
+	}
+
+	stop := make(chan interface{})
+	defer close(stop)
+	health := make(chan *rm.HealthEvent)
+	go m.monitorDaemon(stop, devices, health)
+
+	seen := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case event := <-health:
+			require.Equal(t, pluginapi.Unhealthy, event.Health)
+			seen[event.Device.ID] = true
+		case <-time.After(time.Second):
+			t.Fatal("timed out waiting for unhealthy event")
+		}
+	}
+	require.Equal(t, map[string]bool{"MIG-0::0": true, "MIG-0::1": true}, seen)
+
+	select {
+	case event := <-health:
+		t.Fatalf("unexpected event for device %v", event.Device.ID)
+	case <-time.After(20 * time.Millisecond):
+	}
+}
+
+func TestMPSUpdateResponse(t *testing.T) {
+	testCases := []struct {
+		description      string
+		daemons          map[string]mpsDaemon
+		requestIds       []string
+		expectedError    bool
+		expectedPipeDir  string
+		expectedHostPipe string
+	}{
+		{
+			description:      "full GPU daemon",
+			daemons:          map[string]mpsDaemon{"": &fakeMPSDaemon{}},
+			requestIds:       []string{"GPU-0::0", "GPU-1::3"},
+			expectedPipeDir:  "/mps/pipe",
+			expectedHostPipe: "/host/mps/nvidia.com/gpu/pipe",
+		},
+		{
+			description: "MIG device daemon",
+			daemons: map[string]mpsDaemon{
+				"MIG-0": &fakeMPSDaemon{pipeDir: "/mps/MIG-0/pipe"},
+				"MIG-1": &fakeMPSDaemon{pipeDir: "/mps/MIG-1/pipe"},
+			},
+			requestIds:       []string{"MIG-1::0", "MIG-1::1"},
+			expectedPipeDir:  "/mps/MIG-1/pipe",
+			expectedHostPipe: "/host/mps/nvidia.com/gpu/MIG-1/pipe",
+		},
+		{
+			description: "multiple MIG devices",
+			daemons: map[string]mpsDaemon{
+				"MIG-0": &fakeMPSDaemon{},
+				"MIG-1": &fakeMPSDaemon{},
+			},
+			requestIds:    []string{"MIG-0::0", "MIG-1::0"},
+			expectedError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			m := &mpsOptions{
+				enabled:      true,
+				resourceName: "nvidia.com/gpu",
+				daemons:      tc.daemons,
+				hostRoot:     "/host/mps",
+			}
+			response := &pluginapi.ContainerAllocateResponse{
+				Envs: make(map[string]string),
+			}
+
+			err := m.updateReponse(response, tc.requestIds)
+			if tc.expectedError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tc.expectedPipeDir, response.Envs["CUDA_MPS_PIPE_DIRECTORY"])
+			require.Equal(t, tc.expectedPipeDir, response.Mounts[0].ContainerPath)
+			require.Equal(t, tc.expectedHostPipe, response.Mounts[0].HostPath)
+		})
+	}
+}
@@ -0,0 +1,82 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plugin
+
+import (
+	"maps"
+	"reflect"
+	"slices"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+// Unchanged checks whether a running plugin serves the same resource with the
+// same devices and options as a plugin constructed from a reloaded config. In
+// this case the running plugin can keep serving its resource instead of being
+// replaced by the reloaded plugin. Plugins other than device plugins are
+// always considered changed.
+func Unchanged(running Interface, reloaded Interface) bool {
+	r, ok := running.(*nvidiaDevicePlugin)
+	if !ok {
+		return false
+	}
+	n, ok := reloaded.(*nvidiaDevicePlugin)
+	if !ok {
+		return false
+	}
+
+	if r.rm.Resource() != n.rm.Resource() {
+		return false
+	}
+	if !maps.EqualFunc(r.Devices(), n.Devices(), sameDevice) {
+		return false
+	}
+	if !reflect.DeepEqual(r.imexChannels, n.imexChannels) {
+		return false
+	}
+	return reflect.DeepEqual(sharedOptions(r.config), sharedOptions(n.config))
+}
+
+// sameDevice checks whether two devices are served in the same way. The
+// health of the devices is not considered since it is tracked while a plugin
+// is running.
+func sameDevice(a *rm.Device, b *rm.Device) bool {
+	return a.ID == b.ID &&
+		a.Index == b.Index &&
+		slices.Equal(a.Paths, b.Paths) &&
+		a.Replicas == b.Replicas &&
+		a.MemoryLimit == b.MemoryLimit &&
+		a.PinnedMemoryLimit == b.PinnedMemoryLimit &&
+		a.ActiveThreadPercentage == b.ActiveThreadPercentage
+}
+
+// sharedOptions returns the options of a config that apply to the plugins of
+// all resources. The resource patterns and the per-resource sharing settings
+// are excluded since their effect is reflected in the resource and devices of
+// a plugin.
+func sharedOptions(config *spec.Config) spec.Config {
+	c := *config
+	c.Resources = spec.Resources{}
+	c.Sharing.TimeSlicing.Resources = nil
+	if c.Sharing.MPS != nil {
+		mps := *c.Sharing.MPS
+		mps.Resources = nil
+		c.Sharing.MPS = &mps
+	}
+	return c
+}
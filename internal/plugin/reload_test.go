@@ -0,0 +1,144 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package plugin
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	v1 "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
+)
+
+func TestUnchanged(t *testing.T) {
+	newDevice := func(id string, replicas int) *rm.Device {
+		return &rm.Device{
+			Device: pluginapi.Device{
+				ID:     id,
+				Health: pluginapi.Healthy,
+			},
+			Index:    "0",
+			Replicas: replicas,
+		}
+	}
+	newPlugin := func(resource v1.ResourceName, config *v1.Config, devices ...*rm.Device) *nvidiaDevicePlugin {
+		deviceMap := make(rm.Devices)
+		for _, d := range devices {
+			deviceMap[d.ID] = d
+		}
+		return &nvidiaDevicePlugin{
+			rm: &rm.ResourceManagerMock{
+				ResourceFunc: func() v1.ResourceName { return resource },
+				DevicesFunc:  func() rm.Devices { return deviceMap },
+			},
+			config: config,
+		}
+	}
+	newConfig := func(passDeviceSpecs bool, resources ...v1.ReplicatedResource) *v1.Config {
+		return &v1.Config{
+			Flags: v1.Flags{
+				CommandLineFlags: v1.CommandLineFlags{
+					Plugin: &v1.PluginCommandLineFlags{
+						PassDeviceSpecs: &passDeviceSpecs,
+					},
+				},
+			},
+			Sharing: v1.Sharing{
+				TimeSlicing: v1.ReplicatedResources{
+					Resources: resources,
+				},
+			},
+		}
+	}
+
+	running := newPlugin("nvidia.com/gpu", newConfig(false), newDevice("GPU-0", 0))
+
+	unhealthy := newDevice("GPU-0", 0)
+	unhealthy.Health = pluginapi.Unhealthy
+
+	testCases := []struct {
+		description string
+		reloaded    Interface
+		expected    bool
+	}{
+		{
+			description: "same resource and devices",
+			reloaded:    newPlugin("nvidia.com/gpu", newConfig(false), newDevice("GPU-0", 0)),
+			expected:    true,
+		},
+		{
+			description: "health is ignored",
+			reloaded:    newPlugin("nvidia.com/gpu", newConfig(false), unhealthy),
+			expected:    true,
+		},
+		{
+			description: "sharing of other resources is ignored",
+			reloaded: newPlugin("nvidia.com/gpu", newConfig(false, v1.ReplicatedResource{Name: "nvidia.com/mig-1g.5gb", Replicas: 2}),
+				newDevice("GPU-0", 0),
+			),
+			expected: true,
+		},
+		{
+			description: "renamed resource",
+			reloaded:    newPlugin("nvidia.com/gpu.shared", newConfig(false), newDevice("GPU-0", 0)),
+			expected:    false,
+		},
+		{
+			description: "changed replicas",
+			reloaded:    newPlugin("nvidia.com/gpu", newConfig(false), newDevice("GPU-0::0", 2), newDevice("GPU-0::1", 2)),
+			expected:    false,
+		},
+		{
+			description: "changed device list",
+			reloaded:    newPlugin("nvidia.com/gpu", newConfig(false), newDevice("GPU-0", 0), newDevice("GPU-1", 0)),
+			expected:    false,
+		},
+		{
+			description: "changed options that apply to all resources",
+			reloaded:    newPlugin("nvidia.com/gpu", newConfig(true), newDevice("GPU-0", 0)),
+			expected:    false,
+		},
+		{
+			description: "changed IMEX channels",
+			reloaded: func() Interface {
+				p := newPlugin("nvidia.com/gpu", newConfig(false), newDevice("GPU-0", 0))
+				p.imexChannels = imex.Channels{{ID: "0"}}
+				return p
+			}(),
+			expected: false,
+		},
+		{
+			description: "other plugins are always changed",
+			reloaded:    otherPlugin{},
+			expected:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, Unchanged(running, tc.reloaded))
+		})
+	}
+}
+
+// otherPlugin is a plugin other than a device plugin.
+type otherPlugin struct {
+	Interface
+}
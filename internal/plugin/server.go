@@ -24,7 +24,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 	"time"
 
 	cdiapi "tags.cncf.io/container-device-interface/pkg/cdi"
@@ -32,6 +34,7 @@ import (
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
 	"github.com/NVIDIA/k8s-device-plugin/internal/cdi"
 	"github.com/NVIDIA/k8s-device-plugin/internal/imex"
+	"github.com/NVIDIA/k8s-device-plugin/internal/metrics"
 	"github.com/NVIDIA/k8s-device-plugin/internal/rm"
 
 	"github.com/google/uuid"
@@ -46,6 +49,10 @@ const (
 	deviceListEnvVar                          = "NVIDIA_VISIBLE_DEVICES"
 	deviceListAsVolumeMountsHostPath          = "/dev/null"
 	deviceListAsVolumeMountsContainerPathRoot = "/var/run/nvidia-container-devices"
+	// replicaMemoryLimitsEnvVar is set to the per-device memory limits of the
+	// requested replicas. This can be used by a cooperating runtime or shim to
+	// enforce these limits.
+	replicaMemoryLimitsEnvVar = "NVIDIA_REPLICA_MEMORY_LIMITS"
 )
 
 // nvidiaDevicePlugin implements the Kubernetes device plugin API
@@ -61,12 +68,16 @@ type nvidiaDevicePlugin struct {
 
 	socket string
 	server *grpc.Server
-	health chan *rm.Device
+	health chan *rm.HealthEvent
 	stop   chan interface{}
 
 	imexChannels imex.Channels
 
 	mps mpsOptions
+
+	// unhealthySources holds the sources of the health events that marked
+	// each device unhealthy, keyed by device ID.
+	unhealthySources map[string]map[string]bool
 }
 
 // devicePluginForResource creates a device plugin for the specified resource.
@@ -106,9 +117,46 @@ func getPluginSocketPath(resource spec.ResourceName) string {
 	return filepath.Join(pluginapi.DevicePluginPath, pluginName) + ".sock"
 }
 
+// RemoveStaleSockets removes the plugin sockets in the specified directory that
+// no server is listening on. Such sockets remain if a previous instance of the
+// plugin did not shut down cleanly. Since socket names are derived from
+// resource names, they are not reused if the resources have been renamed in the
+// meantime. Sockets that are still in use are left in place.
+func RemoveStaleSockets(dir string) error {
+	sockets, err := filepath.Glob(filepath.Join(dir, "nvidia-*.sock"))
+	if err != nil {
+		return err
+	}
+	for _, socket := range sockets {
+		if !isStaleSocket(socket) {
+			continue
+		}
+		klog.Infof("Removing stale plugin socket %s", socket)
+		if err := os.Remove(socket); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to remove stale socket %s: %w", socket, err)
+		}
+	}
+	return nil
+}
+
+// isStaleSocket checks whether the specified path is a unix socket that
+// connections are refused on.
+func isStaleSocket(socket string) bool {
+	info, err := os.Lstat(socket)
+	if err != nil || info.Mode()&os.ModeSocket == 0 {
+		return false
+	}
+	conn, err := net.DialTimeout("unix", socket, time.Second)
+	if err != nil {
+		return errors.Is(err, syscall.ECONNREFUSED)
+	}
+	conn.Close()
+	return false
+}
+
 func (plugin *nvidiaDevicePlugin) initialize() {
 	plugin.server = grpc.NewServer([]grpc.ServerOption{}...)
-	plugin.health = make(chan *rm.Device)
+	plugin.health = make(chan *rm.HealthEvent)
 	plugin.stop = make(chan interface{})
 }
 
@@ -148,14 +196,17 @@ func (plugin *nvidiaDevicePlugin) Start(kubeletSocket string) error {
 	}
 	klog.Infof("Registered device plugin for '%s' with Kubelet", plugin.rm.Resource())
 
+	plugin.updateReplicaMetrics()
+
 	go func() {
-		// TODO: add MPS health check
 		err := plugin.rm.CheckHealth(plugin.stop, plugin.health)
 		if err != nil {
 			klog.Errorf("Failed to start health check: %v; continuing with health checks disabled", err)
 		}
 	}()
 
+	go plugin.mps.monitorDaemon(plugin.stop, plugin.Devices(), plugin.health)
+
 	return nil
 }
 
@@ -166,6 +217,7 @@ func (plugin *nvidiaDevicePlugin) Stop() error {
 	}
 	klog.Infof("Stopping to serve '%s' on %s", plugin.rm.Resource(), plugin.socket)
 	plugin.server.Stop()
+	plugin.removeReplicaMetrics()
 	if err := os.Remove(plugin.socket); err != nil && !os.IsNotExist(err) {
 		return err
 	}
@@ -202,6 +254,7 @@ func (plugin *nvidiaDevicePlugin) Serve() error {
 			}
 
 			klog.Infof("GRPC server for '%s' crashed with error: %v", plugin.rm.Resource(), err)
+			metrics.ServerRestarts.WithLabelValues(string(plugin.rm.Resource())).Inc()
 
 			timeSinceLastCrash := time.Since(lastCrashTime).Seconds()
 			lastCrashTime = time.Now()
@@ -273,10 +326,10 @@ func (plugin *nvidiaDevicePlugin) ListAndWatch(e *pluginapi.Empty, s pluginapi.D
 		select {
 		case <-plugin.stop:
 			return nil
-		case d := <-plugin.health:
-			// FIXME: there is no way to recover from the Unhealthy state.
-			d.Health = pluginapi.Unhealthy
-			klog.Infof("'%s' device marked unhealthy: %s", plugin.rm.Resource(), d.ID)
+		case event := <-plugin.health:
+			if !plugin.updateDeviceHealth(event) {
+				continue
+			}
 			if err := s.Send(&pluginapi.ListAndWatchResponse{Devices: plugin.apiDevices()}); err != nil {
 				return nil
 			}
@@ -284,8 +337,85 @@ func (plugin *nvidiaDevicePlugin) ListAndWatch(e *pluginapi.Empty, s pluginapi.D
 	}
 }
 
+// updateDeviceHealth applies the specified health event to its device and
+// returns whether the health of the device has changed. A device is only
+// marked healthy once every source that marked it unhealthy has marked it
+// healthy again.
+func (plugin *nvidiaDevicePlugin) updateDeviceHealth(event *rm.HealthEvent) bool {
+	if event == nil || event.Device == nil {
+		return false
+	}
+	d := event.Device
+	if plugin.unhealthySources == nil {
+		plugin.unhealthySources = make(map[string]map[string]bool)
+	}
+	sources := plugin.unhealthySources[d.ID]
+	if event.IsHealthy() {
+		delete(sources, event.Source)
+	} else {
+		if sources == nil {
+			sources = make(map[string]bool)
+			plugin.unhealthySources[d.ID] = sources
+		}
+		sources[event.Source] = true
+	}
+	if event.IsHealthy() && len(sources) > 0 {
+		if d.Health != event.Health {
+			klog.Infof("'%s' device remains unhealthy: %s (%s)", plugin.rm.Resource(), d.ID, event.Reason)
+		}
+		return false
+	}
+	if d.Health == event.Health {
+		return false
+	}
+	d.Health = event.Health
+	metrics.HealthTransitions.WithLabelValues(string(plugin.rm.Resource()), d.Health).Inc()
+	if event.IsHealthy() {
+		klog.Infof("'%s' device marked healthy: %s (%s)", plugin.rm.Resource(), d.ID, event.Reason)
+	} else {
+		klog.Infof("'%s' device marked unhealthy: %s (%s)", plugin.rm.Resource(), d.ID, event.Reason)
+	}
+	return true
+}
+
+// updateReplicaMetrics records the number of replicas advertised for each GPU.
+func (plugin *nvidiaDevicePlugin) updateReplicaMetrics() {
+	replicas := make(map[string]int)
+	for _, d := range plugin.Devices() {
+		replicas[d.GetUUID()]++
+	}
+	for uuid, count := range replicas {
+		metrics.Replicas.WithLabelValues(string(plugin.rm.Resource()), uuid).Set(float64(count))
+	}
+}
+
+// removeReplicaMetrics removes the number of replicas recorded for each GPU so
+// that no metrics remain for resources that are no longer served.
+func (plugin *nvidiaDevicePlugin) removeReplicaMetrics() {
+	for _, d := range plugin.Devices() {
+		metrics.Replicas.DeleteLabelValues(string(plugin.rm.Resource()), d.GetUUID())
+	}
+}
+
+// observeRequest records the metrics for a device plugin API request.
+func (plugin *nvidiaDevicePlugin) observeRequest(method string, start time.Time, err error) {
+	resource := string(plugin.rm.Resource())
+	metrics.Requests.WithLabelValues(resource, method).Inc()
+	metrics.RequestDuration.WithLabelValues(resource, method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		metrics.RequestErrors.WithLabelValues(resource, method).Inc()
+	}
+}
+
 // GetPreferredAllocation returns the preferred allocation from the set of devices specified in the request
 func (plugin *nvidiaDevicePlugin) GetPreferredAllocation(ctx context.Context, r *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
+	start := time.Now()
+	response, err := plugin.getPreferredAllocation(r)
+	plugin.observeRequest("GetPreferredAllocation", start, err)
+	return response, err
+}
+
+func (plugin *nvidiaDevicePlugin) getPreferredAllocation(r *pluginapi.PreferredAllocationRequest) (*pluginapi.PreferredAllocationResponse, error) {
 	response := &pluginapi.PreferredAllocationResponse{}
 	for _, req := range r.ContainerRequests {
 		devices, err := plugin.rm.GetPreferredAllocation(req.AvailableDeviceIDs, req.MustIncludeDeviceIDs, int(req.AllocationSize))
@@ -304,6 +434,13 @@ func (plugin *nvidiaDevicePlugin) GetPreferredAllocation(ctx context.Context, r
 
 // Allocate returns a list of devices.
 func (plugin *nvidiaDevicePlugin) Allocate(ctx context.Context, reqs *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
+	start := time.Now()
+	response, err := plugin.allocate(reqs)
+	plugin.observeRequest("Allocate", start, err)
+	return response, err
+}
+
+func (plugin *nvidiaDevicePlugin) allocate(reqs *pluginapi.AllocateRequest) (*pluginapi.AllocateResponse, error) {
 	responses := pluginapi.AllocateResponse{}
 	for _, req := range reqs.ContainerRequests {
 		if err := plugin.rm.ValidateRequest(req.DevicesIds); err != nil {
@@ -333,7 +470,9 @@ func (plugin *nvidiaDevicePlugin) getAllocateResponse(requestIds []string) (*plu
 		}
 	}
 	if plugin.mps.enabled {
-		plugin.updateResponseForMPS(response)
+		if err := plugin.updateResponseForMPS(response, requestIds); err != nil {
+			return nil, fmt.Errorf("failed to get allocate response for MPS: %v", err)
+		}
 	}
 
 	if plugin.config.Flags.GDRCopyEnabled != nil && *plugin.config.Flags.GDRCopyEnabled {
@@ -345,6 +484,7 @@ func (plugin *nvidiaDevicePlugin) getAllocateResponse(requestIds []string) (*plu
 	if plugin.config.Flags.MOFEDEnabled != nil && *plugin.config.Flags.MOFEDEnabled {
 		response.Envs["NVIDIA_MOFED"] = "enabled"
 	}
+	plugin.updateResponseForReplicaMemoryLimits(response, requestIds)
 
 	// The following modifications are only made if at least one non-CDI device
 	// list strategy is selected.
@@ -368,8 +508,36 @@ func (plugin *nvidiaDevicePlugin) getAllocateResponse(requestIds []string) (*plu
 // updateResponseForMPS ensures that the ContainerAllocate response contains the information required to use MPS.
 // This includes per-resource pipe and log directories as well as a global daemon-specific shm
 // and assumes that an MPS control daemon has already been started.
-func (plugin nvidiaDevicePlugin) updateResponseForMPS(response *pluginapi.ContainerAllocateResponse) {
-	plugin.mps.updateReponse(response)
+func (plugin nvidiaDevicePlugin) updateResponseForMPS(response *pluginapi.ContainerAllocateResponse, requestIds []string) error {
+	return plugin.mps.updateReponse(response, requestIds)
+}
+
+// updateResponseForReplicaMemoryLimits sets the environment variable for the
+// memory limits of the requested replicas. The limits are specified as a
+// comma-separated list of <device-id>=<limit>M entries where the limit is in MiB.
+// If multiple replicas of the same device are requested, their limits are added.
+func (plugin *nvidiaDevicePlugin) updateResponseForReplicaMemoryLimits(response *pluginapi.ContainerAllocateResponse, requestIds []string) {
+	limits := make(map[string]uint64)
+	for _, d := range plugin.rm.Devices().Subset(requestIds) {
+		if d.MemoryLimit == 0 {
+			continue
+		}
+		id := d.GetUUID()
+		if *plugin.config.Flags.Plugin.DeviceIDStrategy == spec.DeviceIDStrategyIndex {
+			id = d.Index
+		}
+		limits[id] += d.MemoryLimit
+	}
+	if len(limits) == 0 {
+		return
+	}
+
+	var entries []string
+	for id, limit := range limits {
+		entries = append(entries, fmt.Sprintf("%s=%dM", id, limit/1024/1024))
+	}
+	sort.Strings(entries)
+	response.Envs[replicaMemoryLimitsEnvVar] = strings.Join(entries, ",")
 }
 
 // updateResponseForCDI updates the specified response for the given device IDs.
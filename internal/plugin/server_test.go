@@ -18,6 +18,9 @@ package plugin
 
 import (
 	"context"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -80,6 +83,9 @@ func TestAllocate(t *testing.T) {
 		t.Run(tc.description, func(t *testing.T) {
 			plugin := nvidiaDevicePlugin{
 				rm: &rm.ResourceManagerMock{
+					ResourceFunc: func() v1.ResourceName {
+						return "nvidia.com/gpu"
+					},
 					ValidateRequestFunc: func(annotatedIDs rm.AnnotatedIDs) error {
 						return nil
 					},
@@ -254,3 +260,141 @@ func TestCDIAllocateResponse(t *testing.T) {
 func ptr[T any](x T) *T {
 	return &x
 }
+
+func TestUpdateDeviceHealth(t *testing.T) {
+	device := &rm.Device{
+		Device: pluginapi.Device{
+			ID:     "GPU-0",
+			Health: pluginapi.Healthy,
+		},
+	}
+	plugin := nvidiaDevicePlugin{
+		rm: &rm.ResourceManagerMock{
+			ResourceFunc: func() v1.ResourceName {
+				return "nvidia.com/gpu"
+			},
+		},
+	}
+
+	require.False(t, plugin.updateDeviceHealth(rm.NewHealthyEvent(device, "already healthy")))
+	require.Equal(t, pluginapi.Healthy, device.Health)
+
+	require.True(t, plugin.updateDeviceHealth(rm.NewUnhealthyEvent(device, "XidCriticalError: Xid=79")))
+	require.Equal(t, pluginapi.Unhealthy, device.Health)
+
+	require.False(t, plugin.updateDeviceHealth(rm.NewUnhealthyEvent(device, "XidCriticalError: Xid=79")))
+	require.Equal(t, pluginapi.Unhealthy, device.Health)
+
+	require.True(t, plugin.updateDeviceHealth(rm.NewHealthyEvent(device, "device recovered")))
+	require.Equal(t, pluginapi.Healthy, device.Health)
+
+	// A device that is unhealthy for multiple reasons is only marked healthy
+	// once each of the sources has recovered.
+	require.True(t, plugin.updateDeviceHealth(rm.NewUnhealthyEvent(device, "XidCriticalError: Xid=79")))
+	require.False(t, plugin.updateDeviceHealth(withSource(rm.NewUnhealthyEvent(device, "MPS daemon is unhealthy"), mpsHealthSource)))
+	require.False(t, plugin.updateDeviceHealth(withSource(rm.NewHealthyEvent(device, "MPS daemon recovered"), mpsHealthSource)))
+	require.Equal(t, pluginapi.Unhealthy, device.Health)
+	require.True(t, plugin.updateDeviceHealth(rm.NewHealthyEvent(device, "device recovered")))
+	require.Equal(t, pluginapi.Healthy, device.Health)
+}
+
+func TestUpdateResponseForReplicaMemoryLimits(t *testing.T) {
+	devices := rm.Devices{
+		"GPU-0::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::0"}, Index: "0", Replicas: 2, MemoryLimit: 4 * 1024 * 1024 * 1024},
+		"GPU-0::1": &rm.Device{Device: pluginapi.Device{ID: "GPU-0::1"}, Index: "0", Replicas: 2, MemoryLimit: 4 * 1024 * 1024 * 1024},
+		"GPU-1::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-1::0"}, Index: "1", Replicas: 2, MemoryLimit: 1024 * 1024 * 1024},
+		"GPU-2::0": &rm.Device{Device: pluginapi.Device{ID: "GPU-2::0"}, Index: "2", Replicas: 2},
+	}
+
+	testCases := []struct {
+		description      string
+		deviceIDStrategy string
+		requestIDs       []string
+		expectedEnvs     map[string]string
+	}{
+		{
+			description:      "no memory limit",
+			deviceIDStrategy: v1.DeviceIDStrategyUUID,
+			requestIDs:       []string{"GPU-2::0"},
+			expectedEnvs:     map[string]string{},
+		},
+		{
+			description:      "single replica",
+			deviceIDStrategy: v1.DeviceIDStrategyUUID,
+			requestIDs:       []string{"GPU-0::1"},
+			expectedEnvs: map[string]string{
+				"NVIDIA_REPLICA_MEMORY_LIMITS": "GPU-0=4096M",
+			},
+		},
+		{
+			description:      "replicas of multiple devices",
+			deviceIDStrategy: v1.DeviceIDStrategyUUID,
+			requestIDs:       []string{"GPU-1::0", "GPU-0::0", "GPU-2::0"},
+			expectedEnvs: map[string]string{
+				"NVIDIA_REPLICA_MEMORY_LIMITS": "GPU-0=4096M,GPU-1=1024M",
+			},
+		},
+		{
+			description:      "multiple replicas of a device are added",
+			deviceIDStrategy: v1.DeviceIDStrategyIndex,
+			requestIDs:       []string{"GPU-0::0", "GPU-0::1"},
+			expectedEnvs: map[string]string{
+				"NVIDIA_REPLICA_MEMORY_LIMITS": "0=8192M",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			plugin := nvidiaDevicePlugin{
+				rm: &rm.ResourceManagerMock{
+					DevicesFunc: func() rm.Devices {
+						return devices
+					},
+				},
+				config: &v1.Config{
+					Flags: v1.Flags{
+						CommandLineFlags: v1.CommandLineFlags{
+							Plugin: &v1.PluginCommandLineFlags{
+								DeviceIDStrategy: ptr(tc.deviceIDStrategy),
+							},
+						},
+					},
+				},
+			}
+
+			response := pluginapi.ContainerAllocateResponse{Envs: make(map[string]string)}
+			plugin.updateResponseForReplicaMemoryLimits(&response, tc.requestIDs)
+			require.Equal(t, tc.expectedEnvs, response.Envs)
+		})
+	}
+}
+
+func TestRemoveStaleSockets(t *testing.T) {
+	// The path of a unix socket is limited in length so we do not use t.TempDir().
+	dir, err := os.MkdirTemp("", "plugins")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = os.RemoveAll(dir) })
+
+	listen := func(name string) *net.UnixListener {
+		l, err := net.ListenUnix("unix", &net.UnixAddr{Name: filepath.Join(dir, name), Net: "unix"})
+		require.NoError(t, err)
+		return l
+	}
+
+	live := listen("nvidia-a100.sock")
+	defer live.Close()
+
+	stale := listen("nvidia-gpu.sock")
+	stale.SetUnlinkOnClose(false)
+	require.NoError(t, stale.Close())
+
+	other := listen("other-gpu.sock")
+	other.SetUnlinkOnClose(false)
+	require.NoError(t, other.Close())
+
+	require.NoError(t, RemoveStaleSockets(dir))
+	require.FileExists(t, filepath.Join(dir, "nvidia-a100.sock"))
+	require.NoFileExists(t, filepath.Join(dir, "nvidia-gpu.sock"))
+	require.FileExists(t, filepath.Join(dir, "other-gpu.sock"))
+}
@@ -108,3 +108,58 @@ func (d *cudaDevice) IsFabricAttached() (bool, error) {
 func (d *cudaDevice) GetFabricIDs() (string, string, error) {
 	return "", "", fmt.Errorf("GetFabricIDs is not supported for CUDA devices")
 }
+
+func (d *cudaDevice) GetPCIBusID() (string, error) {
+	return "", fmt.Errorf("GetPCIBusID is %w for CUDA devices", ErrNotSupported)
+}
+
+func (d *cudaDevice) GetNVLinks() ([]NVLink, error) {
+	return nil, fmt.Errorf("GetNVLinks is %w for CUDA devices", ErrNotSupported)
+}
+
+func (d *cudaDevice) GetPCIeLink() (int, int, error) {
+	return 0, 0, fmt.Errorf("GetPCIeLink is %w for CUDA devices", ErrNotSupported)
+}
+
+func (d *cudaDevice) GetNumaNode() (bool, int, error) {
+	return false, 0, fmt.Errorf("GetNumaNode is %w for CUDA devices", ErrNotSupported)
+}
+
+func (d *cudaDevice) GetVbiosVersion() (string, error) {
+	return "", fmt.Errorf("GetVbiosVersion is %w for CUDA devices", ErrNotSupported)
+}
+
+func (d *cudaDevice) GetInforomImageVersion() (string, error) {
+	return "", fmt.Errorf("GetInforomImageVersion is %w for CUDA devices", ErrNotSupported)
+}
+
+// GetECCMode returns whether ECC is enabled. CUDA does not report a pending
+// ECC mode so this is the same as the current mode.
+func (d *cudaDevice) GetECCMode() (bool, bool, error) {
+	enabled, r := cuda.Device(*d).GetAttribute(cuda.ECC_ENABLED)
+	if r != cuda.SUCCESS {
+		return false, false, fmt.Errorf("failed to get ECC mode for device: result=%v", r)
+	}
+	return enabled != 0, enabled != 0, nil
+}
+
+func (d *cudaDevice) IsPersistenceModeEnabled() (bool, error) {
+	return false, fmt.Errorf("IsPersistenceModeEnabled is %w for CUDA devices", ErrNotSupported)
+}
+
+// GetComputeMode returns the compute mode of the device.
+func (d *cudaDevice) GetComputeMode() (ComputeMode, error) {
+	mode, r := cuda.Device(*d).GetAttribute(cuda.COMPUTE_MODE)
+	if r != cuda.SUCCESS {
+		return "", fmt.Errorf("failed to get compute mode for device: result=%v", r)
+	}
+	switch mode {
+	case cuda.COMPUTEMODE_DEFAULT:
+		return ComputeModeDefault, nil
+	case cuda.COMPUTEMODE_PROHIBITED:
+		return ComputeModeProhibited, nil
+	case cuda.COMPUTEMODE_EXCLUSIVE_PROCESS:
+		return ComputeModeExclusiveProcess, nil
+	}
+	return "", fmt.Errorf("unexpected compute mode %v", mode)
+}
@@ -86,3 +86,8 @@ func (l *cudaLib) Shutdown() (err error) {
 	}
 	return nil
 }
+
+// GetConfComputeState is not supported
+func (l *cudaLib) GetConfComputeState() (*ConfComputeState, error) {
+	return nil, fmt.Errorf("GetConfComputeState is %w for CUDA devices", ErrNotSupported)
+}
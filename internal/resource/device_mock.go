@@ -20,27 +20,54 @@ var _ Device = &DeviceMock{}
 //			GetAttributesFunc: func() (map[string]interface{}, error) {
 //				panic("mock out the GetAttributes method")
 //			},
+//			GetComputeModeFunc: func() (ComputeMode, error) {
+//				panic("mock out the GetComputeMode method")
+//			},
 //			GetCudaComputeCapabilityFunc: func() (int, int, error) {
 //				panic("mock out the GetCudaComputeCapability method")
 //			},
 //			GetDeviceHandleFromMigDeviceHandleFunc: func() (Device, error) {
 //				panic("mock out the GetDeviceHandleFromMigDeviceHandle method")
 //			},
+//			GetECCModeFunc: func() (bool, bool, error) {
+//				panic("mock out the GetECCMode method")
+//			},
 //			GetFabricIDsFunc: func() (string, string, error) {
 //				panic("mock out the GetFabricIDs method")
 //			},
+//			GetInforomImageVersionFunc: func() (string, error) {
+//				panic("mock out the GetInforomImageVersion method")
+//			},
 //			GetMigDevicesFunc: func() ([]Device, error) {
 //				panic("mock out the GetMigDevices method")
 //			},
+//			GetNVLinksFunc: func() ([]NVLink, error) {
+//				panic("mock out the GetNVLinks method")
+//			},
 //			GetNameFunc: func() (string, error) {
 //				panic("mock out the GetName method")
 //			},
+//			GetNumaNodeFunc: func() (bool, int, error) {
+//				panic("mock out the GetNumaNode method")
+//			},
+//			GetPCIBusIDFunc: func() (string, error) {
+//				panic("mock out the GetPCIBusID method")
+//			},
 //			GetPCIClassFunc: func() (uint32, error) {
 //				panic("mock out the GetPCIClass method")
 //			},
+//			GetPCIeLinkFunc: func() (int, int, error) {
+//				panic("mock out the GetPCIeLink method")
+//			},
 //			GetTotalMemoryMiBFunc: func() (uint64, error) {
 //				panic("mock out the GetTotalMemoryMiB method")
 //			},
+//			GetUUIDFunc: func() (string, error) {
+//				panic("mock out the GetUUID method")
+//			},
+//			GetVbiosVersionFunc: func() (string, error) {
+//				panic("mock out the GetVbiosVersion method")
+//			},
 //			IsFabricAttachedFunc: func() (bool, error) {
 //				panic("mock out the IsFabricAttached method")
 //			},
@@ -50,6 +77,9 @@ var _ Device = &DeviceMock{}
 //			IsMigEnabledFunc: func() (bool, error) {
 //				panic("mock out the IsMigEnabled method")
 //			},
+//			IsPersistenceModeEnabledFunc: func() (bool, error) {
+//				panic("mock out the IsPersistenceModeEnabled method")
+//			},
 //		}
 //
 //		// use mockedDevice in code that requires Device
@@ -60,27 +90,54 @@ type DeviceMock struct {
 	// GetAttributesFunc mocks the GetAttributes method.
 	GetAttributesFunc func() (map[string]interface{}, error)
 
+	// GetComputeModeFunc mocks the GetComputeMode method.
+	GetComputeModeFunc func() (ComputeMode, error)
+
 	// GetCudaComputeCapabilityFunc mocks the GetCudaComputeCapability method.
 	GetCudaComputeCapabilityFunc func() (int, int, error)
 
 	// GetDeviceHandleFromMigDeviceHandleFunc mocks the GetDeviceHandleFromMigDeviceHandle method.
 	GetDeviceHandleFromMigDeviceHandleFunc func() (Device, error)
 
+	// GetECCModeFunc mocks the GetECCMode method.
+	GetECCModeFunc func() (bool, bool, error)
+
 	// GetFabricIDsFunc mocks the GetFabricIDs method.
 	GetFabricIDsFunc func() (string, string, error)
 
+	// GetInforomImageVersionFunc mocks the GetInforomImageVersion method.
+	GetInforomImageVersionFunc func() (string, error)
+
 	// GetMigDevicesFunc mocks the GetMigDevices method.
 	GetMigDevicesFunc func() ([]Device, error)
 
+	// GetNVLinksFunc mocks the GetNVLinks method.
+	GetNVLinksFunc func() ([]NVLink, error)
+
 	// GetNameFunc mocks the GetName method.
 	GetNameFunc func() (string, error)
 
+	// GetNumaNodeFunc mocks the GetNumaNode method.
+	GetNumaNodeFunc func() (bool, int, error)
+
+	// GetPCIBusIDFunc mocks the GetPCIBusID method.
+	GetPCIBusIDFunc func() (string, error)
+
 	// GetPCIClassFunc mocks the GetPCIClass method.
 	GetPCIClassFunc func() (uint32, error)
 
+	// GetPCIeLinkFunc mocks the GetPCIeLink method.
+	GetPCIeLinkFunc func() (int, int, error)
+
 	// GetTotalMemoryMiBFunc mocks the GetTotalMemoryMiB method.
 	GetTotalMemoryMiBFunc func() (uint64, error)
 
+	// GetUUIDFunc mocks the GetUUID method.
+	GetUUIDFunc func() (string, error)
+
+	// GetVbiosVersionFunc mocks the GetVbiosVersion method.
+	GetVbiosVersionFunc func() (string, error)
+
 	// IsFabricAttachedFunc mocks the IsFabricAttached method.
 	IsFabricAttachedFunc func() (bool, error)
 
@@ -90,32 +147,62 @@ type DeviceMock struct {
 	// IsMigEnabledFunc mocks the IsMigEnabled method.
 	IsMigEnabledFunc func() (bool, error)
 
+	// IsPersistenceModeEnabledFunc mocks the IsPersistenceModeEnabled method.
+	IsPersistenceModeEnabledFunc func() (bool, error)
+
 	// calls tracks calls to the methods.
 	calls struct {
 		// GetAttributes holds details about calls to the GetAttributes method.
 		GetAttributes []struct {
 		}
+		// GetComputeMode holds details about calls to the GetComputeMode method.
+		GetComputeMode []struct {
+		}
 		// GetCudaComputeCapability holds details about calls to the GetCudaComputeCapability method.
 		GetCudaComputeCapability []struct {
 		}
 		// GetDeviceHandleFromMigDeviceHandle holds details about calls to the GetDeviceHandleFromMigDeviceHandle method.
 		GetDeviceHandleFromMigDeviceHandle []struct {
 		}
+		// GetECCMode holds details about calls to the GetECCMode method.
+		GetECCMode []struct {
+		}
 		// GetFabricIDs holds details about calls to the GetFabricIDs method.
 		GetFabricIDs []struct {
 		}
+		// GetInforomImageVersion holds details about calls to the GetInforomImageVersion method.
+		GetInforomImageVersion []struct {
+		}
 		// GetMigDevices holds details about calls to the GetMigDevices method.
 		GetMigDevices []struct {
 		}
+		// GetNVLinks holds details about calls to the GetNVLinks method.
+		GetNVLinks []struct {
+		}
 		// GetName holds details about calls to the GetName method.
 		GetName []struct {
 		}
+		// GetNumaNode holds details about calls to the GetNumaNode method.
+		GetNumaNode []struct {
+		}
+		// GetPCIBusID holds details about calls to the GetPCIBusID method.
+		GetPCIBusID []struct {
+		}
 		// GetPCIClass holds details about calls to the GetPCIClass method.
 		GetPCIClass []struct {
 		}
+		// GetPCIeLink holds details about calls to the GetPCIeLink method.
+		GetPCIeLink []struct {
+		}
 		// GetTotalMemoryMiB holds details about calls to the GetTotalMemoryMiB method.
 		GetTotalMemoryMiB []struct {
 		}
+		// GetUUID holds details about calls to the GetUUID method.
+		GetUUID []struct {
+		}
+		// GetVbiosVersion holds details about calls to the GetVbiosVersion method.
+		GetVbiosVersion []struct {
+		}
 		// IsFabricAttached holds details about calls to the IsFabricAttached method.
 		IsFabricAttached []struct {
 		}
@@ -125,18 +212,31 @@ type DeviceMock struct {
 		// IsMigEnabled holds details about calls to the IsMigEnabled method.
 		IsMigEnabled []struct {
 		}
+		// IsPersistenceModeEnabled holds details about calls to the IsPersistenceModeEnabled method.
+		IsPersistenceModeEnabled []struct {
+		}
 	}
 	lockGetAttributes                      sync.RWMutex
+	lockGetComputeMode                     sync.RWMutex
 	lockGetCudaComputeCapability           sync.RWMutex
 	lockGetDeviceHandleFromMigDeviceHandle sync.RWMutex
+	lockGetECCMode                         sync.RWMutex
 	lockGetFabricIDs                       sync.RWMutex
+	lockGetInforomImageVersion             sync.RWMutex
 	lockGetMigDevices                      sync.RWMutex
+	lockGetNVLinks                         sync.RWMutex
 	lockGetName                            sync.RWMutex
+	lockGetNumaNode                        sync.RWMutex
+	lockGetPCIBusID                        sync.RWMutex
 	lockGetPCIClass                        sync.RWMutex
+	lockGetPCIeLink                        sync.RWMutex
 	lockGetTotalMemoryMiB                  sync.RWMutex
+	lockGetUUID                            sync.RWMutex
+	lockGetVbiosVersion                    sync.RWMutex
 	lockIsFabricAttached                   sync.RWMutex
 	lockIsMigCapable                       sync.RWMutex
 	lockIsMigEnabled                       sync.RWMutex
+	lockIsPersistenceModeEnabled           sync.RWMutex
 }
 
 // GetAttributes calls GetAttributesFunc.
@@ -166,6 +266,33 @@ func (mock *DeviceMock) GetAttributesCalls() []struct {
 	return calls
 }
 
+// GetComputeMode calls GetComputeModeFunc.
+func (mock *DeviceMock) GetComputeMode() (ComputeMode, error) {
+	if mock.GetComputeModeFunc == nil {
+		panic("DeviceMock.GetComputeModeFunc: method is nil but Device.GetComputeMode was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetComputeMode.Lock()
+	mock.calls.GetComputeMode = append(mock.calls.GetComputeMode, callInfo)
+	mock.lockGetComputeMode.Unlock()
+	return mock.GetComputeModeFunc()
+}
+
+// GetComputeModeCalls gets all the calls that were made to GetComputeMode.
+// Check the length with:
+//
+//	len(mockedDevice.GetComputeModeCalls())
+func (mock *DeviceMock) GetComputeModeCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetComputeMode.RLock()
+	calls = mock.calls.GetComputeMode
+	mock.lockGetComputeMode.RUnlock()
+	return calls
+}
+
 // GetCudaComputeCapability calls GetCudaComputeCapabilityFunc.
 func (mock *DeviceMock) GetCudaComputeCapability() (int, int, error) {
 	if mock.GetCudaComputeCapabilityFunc == nil {
@@ -220,6 +347,33 @@ func (mock *DeviceMock) GetDeviceHandleFromMigDeviceHandleCalls() []struct {
 	return calls
 }
 
+// GetECCMode calls GetECCModeFunc.
+func (mock *DeviceMock) GetECCMode() (bool, bool, error) {
+	if mock.GetECCModeFunc == nil {
+		panic("DeviceMock.GetECCModeFunc: method is nil but Device.GetECCMode was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetECCMode.Lock()
+	mock.calls.GetECCMode = append(mock.calls.GetECCMode, callInfo)
+	mock.lockGetECCMode.Unlock()
+	return mock.GetECCModeFunc()
+}
+
+// GetECCModeCalls gets all the calls that were made to GetECCMode.
+// Check the length with:
+//
+//	len(mockedDevice.GetECCModeCalls())
+func (mock *DeviceMock) GetECCModeCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetECCMode.RLock()
+	calls = mock.calls.GetECCMode
+	mock.lockGetECCMode.RUnlock()
+	return calls
+}
+
 // GetFabricIDs calls GetFabricIDsFunc.
 func (mock *DeviceMock) GetFabricIDs() (string, string, error) {
 	if mock.GetFabricIDsFunc == nil {
@@ -247,6 +401,33 @@ func (mock *DeviceMock) GetFabricIDsCalls() []struct {
 	return calls
 }
 
+// GetInforomImageVersion calls GetInforomImageVersionFunc.
+func (mock *DeviceMock) GetInforomImageVersion() (string, error) {
+	if mock.GetInforomImageVersionFunc == nil {
+		panic("DeviceMock.GetInforomImageVersionFunc: method is nil but Device.GetInforomImageVersion was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetInforomImageVersion.Lock()
+	mock.calls.GetInforomImageVersion = append(mock.calls.GetInforomImageVersion, callInfo)
+	mock.lockGetInforomImageVersion.Unlock()
+	return mock.GetInforomImageVersionFunc()
+}
+
+// GetInforomImageVersionCalls gets all the calls that were made to GetInforomImageVersion.
+// Check the length with:
+//
+//	len(mockedDevice.GetInforomImageVersionCalls())
+func (mock *DeviceMock) GetInforomImageVersionCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetInforomImageVersion.RLock()
+	calls = mock.calls.GetInforomImageVersion
+	mock.lockGetInforomImageVersion.RUnlock()
+	return calls
+}
+
 // GetMigDevices calls GetMigDevicesFunc.
 func (mock *DeviceMock) GetMigDevices() ([]Device, error) {
 	if mock.GetMigDevicesFunc == nil {
@@ -274,6 +455,33 @@ func (mock *DeviceMock) GetMigDevicesCalls() []struct {
 	return calls
 }
 
+// GetNVLinks calls GetNVLinksFunc.
+func (mock *DeviceMock) GetNVLinks() ([]NVLink, error) {
+	if mock.GetNVLinksFunc == nil {
+		panic("DeviceMock.GetNVLinksFunc: method is nil but Device.GetNVLinks was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetNVLinks.Lock()
+	mock.calls.GetNVLinks = append(mock.calls.GetNVLinks, callInfo)
+	mock.lockGetNVLinks.Unlock()
+	return mock.GetNVLinksFunc()
+}
+
+// GetNVLinksCalls gets all the calls that were made to GetNVLinks.
+// Check the length with:
+//
+//	len(mockedDevice.GetNVLinksCalls())
+func (mock *DeviceMock) GetNVLinksCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetNVLinks.RLock()
+	calls = mock.calls.GetNVLinks
+	mock.lockGetNVLinks.RUnlock()
+	return calls
+}
+
 // GetName calls GetNameFunc.
 func (mock *DeviceMock) GetName() (string, error) {
 	if mock.GetNameFunc == nil {
@@ -301,6 +509,60 @@ func (mock *DeviceMock) GetNameCalls() []struct {
 	return calls
 }
 
+// GetNumaNode calls GetNumaNodeFunc.
+func (mock *DeviceMock) GetNumaNode() (bool, int, error) {
+	if mock.GetNumaNodeFunc == nil {
+		panic("DeviceMock.GetNumaNodeFunc: method is nil but Device.GetNumaNode was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetNumaNode.Lock()
+	mock.calls.GetNumaNode = append(mock.calls.GetNumaNode, callInfo)
+	mock.lockGetNumaNode.Unlock()
+	return mock.GetNumaNodeFunc()
+}
+
+// GetNumaNodeCalls gets all the calls that were made to GetNumaNode.
+// Check the length with:
+//
+//	len(mockedDevice.GetNumaNodeCalls())
+func (mock *DeviceMock) GetNumaNodeCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetNumaNode.RLock()
+	calls = mock.calls.GetNumaNode
+	mock.lockGetNumaNode.RUnlock()
+	return calls
+}
+
+// GetPCIBusID calls GetPCIBusIDFunc.
+func (mock *DeviceMock) GetPCIBusID() (string, error) {
+	if mock.GetPCIBusIDFunc == nil {
+		panic("DeviceMock.GetPCIBusIDFunc: method is nil but Device.GetPCIBusID was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetPCIBusID.Lock()
+	mock.calls.GetPCIBusID = append(mock.calls.GetPCIBusID, callInfo)
+	mock.lockGetPCIBusID.Unlock()
+	return mock.GetPCIBusIDFunc()
+}
+
+// GetPCIBusIDCalls gets all the calls that were made to GetPCIBusID.
+// Check the length with:
+//
+//	len(mockedDevice.GetPCIBusIDCalls())
+func (mock *DeviceMock) GetPCIBusIDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetPCIBusID.RLock()
+	calls = mock.calls.GetPCIBusID
+	mock.lockGetPCIBusID.RUnlock()
+	return calls
+}
+
 // GetPCIClass calls GetPCIClassFunc.
 func (mock *DeviceMock) GetPCIClass() (uint32, error) {
 	if mock.GetPCIClassFunc == nil {
@@ -328,6 +590,33 @@ func (mock *DeviceMock) GetPCIClassCalls() []struct {
 	return calls
 }
 
+// GetPCIeLink calls GetPCIeLinkFunc.
+func (mock *DeviceMock) GetPCIeLink() (int, int, error) {
+	if mock.GetPCIeLinkFunc == nil {
+		panic("DeviceMock.GetPCIeLinkFunc: method is nil but Device.GetPCIeLink was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetPCIeLink.Lock()
+	mock.calls.GetPCIeLink = append(mock.calls.GetPCIeLink, callInfo)
+	mock.lockGetPCIeLink.Unlock()
+	return mock.GetPCIeLinkFunc()
+}
+
+// GetPCIeLinkCalls gets all the calls that were made to GetPCIeLink.
+// Check the length with:
+//
+//	len(mockedDevice.GetPCIeLinkCalls())
+func (mock *DeviceMock) GetPCIeLinkCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetPCIeLink.RLock()
+	calls = mock.calls.GetPCIeLink
+	mock.lockGetPCIeLink.RUnlock()
+	return calls
+}
+
 // GetTotalMemoryMiB calls GetTotalMemoryMiBFunc.
 func (mock *DeviceMock) GetTotalMemoryMiB() (uint64, error) {
 	if mock.GetTotalMemoryMiBFunc == nil {
@@ -355,6 +644,60 @@ func (mock *DeviceMock) GetTotalMemoryMiBCalls() []struct {
 	return calls
 }
 
+// GetUUID calls GetUUIDFunc.
+func (mock *DeviceMock) GetUUID() (string, error) {
+	if mock.GetUUIDFunc == nil {
+		panic("DeviceMock.GetUUIDFunc: method is nil but Device.GetUUID was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetUUID.Lock()
+	mock.calls.GetUUID = append(mock.calls.GetUUID, callInfo)
+	mock.lockGetUUID.Unlock()
+	return mock.GetUUIDFunc()
+}
+
+// GetUUIDCalls gets all the calls that were made to GetUUID.
+// Check the length with:
+//
+//	len(mockedDevice.GetUUIDCalls())
+func (mock *DeviceMock) GetUUIDCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetUUID.RLock()
+	calls = mock.calls.GetUUID
+	mock.lockGetUUID.RUnlock()
+	return calls
+}
+
+// GetVbiosVersion calls GetVbiosVersionFunc.
+func (mock *DeviceMock) GetVbiosVersion() (string, error) {
+	if mock.GetVbiosVersionFunc == nil {
+		panic("DeviceMock.GetVbiosVersionFunc: method is nil but Device.GetVbiosVersion was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetVbiosVersion.Lock()
+	mock.calls.GetVbiosVersion = append(mock.calls.GetVbiosVersion, callInfo)
+	mock.lockGetVbiosVersion.Unlock()
+	return mock.GetVbiosVersionFunc()
+}
+
+// GetVbiosVersionCalls gets all the calls that were made to GetVbiosVersion.
+// Check the length with:
+//
+//	len(mockedDevice.GetVbiosVersionCalls())
+func (mock *DeviceMock) GetVbiosVersionCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetVbiosVersion.RLock()
+	calls = mock.calls.GetVbiosVersion
+	mock.lockGetVbiosVersion.RUnlock()
+	return calls
+}
+
 // IsFabricAttached calls IsFabricAttachedFunc.
 func (mock *DeviceMock) IsFabricAttached() (bool, error) {
 	if mock.IsFabricAttachedFunc == nil {
@@ -435,3 +778,30 @@ func (mock *DeviceMock) IsMigEnabledCalls() []struct {
 	mock.lockIsMigEnabled.RUnlock()
 	return calls
 }
+
+// IsPersistenceModeEnabled calls IsPersistenceModeEnabledFunc.
+func (mock *DeviceMock) IsPersistenceModeEnabled() (bool, error) {
+	if mock.IsPersistenceModeEnabledFunc == nil {
+		panic("DeviceMock.IsPersistenceModeEnabledFunc: method is nil but Device.IsPersistenceModeEnabled was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockIsPersistenceModeEnabled.Lock()
+	mock.calls.IsPersistenceModeEnabled = append(mock.calls.IsPersistenceModeEnabled, callInfo)
+	mock.lockIsPersistenceModeEnabled.Unlock()
+	return mock.IsPersistenceModeEnabledFunc()
+}
+
+// IsPersistenceModeEnabledCalls gets all the calls that were made to IsPersistenceModeEnabled.
+// Check the length with:
+//
+//	len(mockedDevice.IsPersistenceModeEnabledCalls())
+func (mock *DeviceMock) IsPersistenceModeEnabledCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockIsPersistenceModeEnabled.RLock()
+	calls = mock.calls.IsPersistenceModeEnabled
+	mock.lockIsPersistenceModeEnabled.RUnlock()
+	return calls
+}
@@ -62,3 +62,8 @@ func (m *withFallBack) GetCudaDriverVersion() (int, int, error) {
 func (m *withFallBack) GetDriverVersion() (string, error) {
 	return m.wraps.GetDriverVersion()
 }
+
+// GetConfComputeState delegates to the wrapped manager
+func (m *withFallBack) GetConfComputeState() (*ConfComputeState, error) {
+	return m.wraps.GetConfComputeState()
+}
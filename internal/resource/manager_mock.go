@@ -17,6 +17,9 @@ var _ Manager = &ManagerMock{}
 //
 //		// make and configure a mocked Manager
 //		mockedManager := &ManagerMock{
+//			GetConfComputeStateFunc: func() (*ConfComputeState, error) {
+//				panic("mock out the GetConfComputeState method")
+//			},
 //			GetCudaDriverVersionFunc: func() (int, int, error) {
 //				panic("mock out the GetCudaDriverVersion method")
 //			},
@@ -39,6 +42,9 @@ var _ Manager = &ManagerMock{}
 //
 //	}
 type ManagerMock struct {
+	// GetConfComputeStateFunc mocks the GetConfComputeState method.
+	GetConfComputeStateFunc func() (*ConfComputeState, error)
+
 	// GetCudaDriverVersionFunc mocks the GetCudaDriverVersion method.
 	GetCudaDriverVersionFunc func() (int, int, error)
 
@@ -56,6 +62,9 @@ type ManagerMock struct {
 
 	// calls tracks calls to the methods.
 	calls struct {
+		// GetConfComputeState holds details about calls to the GetConfComputeState method.
+		GetConfComputeState []struct {
+		}
 		// GetCudaDriverVersion holds details about calls to the GetCudaDriverVersion method.
 		GetCudaDriverVersion []struct {
 		}
@@ -72,6 +81,7 @@ type ManagerMock struct {
 		Shutdown []struct {
 		}
 	}
+	lockGetConfComputeState  sync.RWMutex
 	lockGetCudaDriverVersion sync.RWMutex
 	lockGetDevices           sync.RWMutex
 	lockGetDriverVersion     sync.RWMutex
@@ -79,6 +89,33 @@ type ManagerMock struct {
 	lockShutdown             sync.RWMutex
 }
 
+// GetConfComputeState calls GetConfComputeStateFunc.
+func (mock *ManagerMock) GetConfComputeState() (*ConfComputeState, error) {
+	if mock.GetConfComputeStateFunc == nil {
+		panic("ManagerMock.GetConfComputeStateFunc: method is nil but Manager.GetConfComputeState was just called")
+	}
+	callInfo := struct {
+	}{}
+	mock.lockGetConfComputeState.Lock()
+	mock.calls.GetConfComputeState = append(mock.calls.GetConfComputeState, callInfo)
+	mock.lockGetConfComputeState.Unlock()
+	return mock.GetConfComputeStateFunc()
+}
+
+// GetConfComputeStateCalls gets all the calls that were made to GetConfComputeState.
+// Check the length with:
+//
+//	len(mockedManager.GetConfComputeStateCalls())
+func (mock *ManagerMock) GetConfComputeStateCalls() []struct {
+} {
+	var calls []struct {
+	}
+	mock.lockGetConfComputeState.RLock()
+	calls = mock.calls.GetConfComputeState
+	mock.lockGetConfComputeState.RUnlock()
+	return calls
+}
+
 // GetCudaDriverVersion calls GetCudaDriverVersionFunc.
 func (mock *ManagerMock) GetCudaDriverVersion() (int, int, error) {
 	if mock.GetCudaDriverVersionFunc == nil {
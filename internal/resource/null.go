@@ -55,3 +55,8 @@ func (l *null) GetCudaDriverVersion() (int, int, error) {
 func (l *null) GetDriverVersion() (string, error) {
 	return "", fmt.Errorf("GetDriverVersion is unsupported")
 }
+
+// GetConfComputeState is not supported
+func (l *null) GetConfComputeState() (*ConfComputeState, error) {
+	return nil, fmt.Errorf("GetConfComputeState is %w", ErrNotSupported)
+}
@@ -18,6 +18,7 @@ package resource
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
@@ -81,6 +82,15 @@ func (d nvmlDevice) GetName() (string, error) {
 	return name, nil
 }
 
+// GetUUID returns the UUID of the device.
+func (d nvmlDevice) GetUUID() (string, error) {
+	id, ret := d.Device.GetUUID()
+	if ret != nvml.SUCCESS {
+		return "", ret
+	}
+	return id, nil
+}
+
 // GetTotalMemoryMiB returns the total memory on a device in mebibytes (2^20 bytes)
 func (d nvmlDevice) GetTotalMemoryMiB() (uint64, error) {
 	info, ret := d.GetMemoryInfo()
@@ -117,3 +127,158 @@ func (d nvmlDevice) GetFabricIDs() (string, string, error) {
 
 	return clusterUUID.String(), cliqueId, nil
 }
+
+// GetNVLinks returns the active NVLinks of the device. A device without
+// NVLink support has no links.
+func (d nvmlDevice) GetNVLinks() ([]NVLink, error) {
+	var links []NVLink
+	for link := 0; link < nvml.NVLINK_MAX_LINKS; link++ {
+		state, ret := d.GetNvLinkState(link)
+		if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_INVALID_ARGUMENT {
+			break
+		}
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get state of NVLink %d: %w", link, ret)
+		}
+		if state != nvml.FEATURE_ENABLED {
+			continue
+		}
+
+		info, ret := d.GetNvLinkRemotePciInfo(link)
+		if ret != nvml.SUCCESS {
+			return nil, fmt.Errorf("failed to get remote PCI info of NVLink %d: %w", link, ret)
+		}
+
+		// Older drivers do not report the remote device type.
+		remoteType := NVLinkRemoteUnknown
+		deviceType, ret := d.GetNvLinkRemoteDeviceType(link)
+		if ret == nvml.SUCCESS {
+			switch deviceType {
+			case nvml.NVLINK_DEVICE_TYPE_GPU:
+				remoteType = NVLinkRemoteGPU
+			case nvml.NVLINK_DEVICE_TYPE_SWITCH:
+				remoteType = NVLinkRemoteSwitch
+			}
+		}
+
+		links = append(links, NVLink{
+			RemoteType:     remoteType,
+			RemotePCIBusID: getPCIBusID(info),
+		})
+	}
+	return links, nil
+}
+
+// GetPCIeLink returns the maximum PCIe link generation and width possible
+// with this device and system.
+func (d nvmlDevice) GetPCIeLink() (int, int, error) {
+	generation, ret := d.GetMaxPcieLinkGeneration()
+	if ret != nvml.SUCCESS {
+		return 0, 0, nvmlError("failed to get max PCIe link generation", ret)
+	}
+	width, ret := d.GetMaxPcieLinkWidth()
+	if ret != nvml.SUCCESS {
+		return 0, 0, nvmlError("failed to get max PCIe link width", ret)
+	}
+	return generation, width, nil
+}
+
+// GetNumaNode returns the NUMA node of the device if it is known.
+func (d nvmlDevice) GetNumaNode() (bool, int, error) {
+	pciBusID, err := d.GetPCIBusID()
+	if err != nil {
+		return false, 0, err
+	}
+	nvDevice, err := nvpci.New().GetGPUByPciBusID(pciBusID)
+	if err != nil {
+		return false, 0, err
+	}
+	if nvDevice.NumaNode < 0 {
+		return false, 0, nil
+	}
+	return true, nvDevice.NumaNode, nil
+}
+
+// getPCIBusID returns the bus ID of the specified PCI info in the format used
+// by GetPCIBusID.
+func getPCIBusID(info nvml.PciInfo) string {
+	var bytes []byte
+	for _, b := range info.BusId {
+		if byte(b) == '\x00' {
+			break
+		}
+		bytes = append(bytes, byte(b))
+	}
+	id := strings.ToLower(string(bytes))
+
+	// NVML reports an 8 digit PCI domain while sysfs uses 4 digits.
+	if strings.Index(id, ":") == 8 && strings.HasPrefix(id, "0000") {
+		id = strings.TrimPrefix(id, "0000")
+	}
+	return id
+}
+
+// GetVbiosVersion returns the VBIOS version of the device.
+func (d nvmlDevice) GetVbiosVersion() (string, error) {
+	version, ret := d.Device.GetVbiosVersion()
+	if ret != nvml.SUCCESS {
+		return "", nvmlError("failed to get VBIOS version", ret)
+	}
+	return version, nil
+}
+
+// GetInforomImageVersion returns the version of the InfoROM image of the device.
+func (d nvmlDevice) GetInforomImageVersion() (string, error) {
+	version, ret := d.Device.GetInforomImageVersion()
+	if ret != nvml.SUCCESS {
+		return "", nvmlError("failed to get InfoROM image version", ret)
+	}
+	return version, nil
+}
+
+// GetECCMode returns whether ECC is currently enabled and whether it will be
+// enabled after the next reboot.
+func (d nvmlDevice) GetECCMode() (bool, bool, error) {
+	current, pending, ret := d.GetEccMode()
+	if ret != nvml.SUCCESS {
+		return false, false, nvmlError("failed to get ECC mode", ret)
+	}
+	return current == nvml.FEATURE_ENABLED, pending == nvml.FEATURE_ENABLED, nil
+}
+
+// IsPersistenceModeEnabled returns whether persistence mode is enabled.
+func (d nvmlDevice) IsPersistenceModeEnabled() (bool, error) {
+	mode, ret := d.GetPersistenceMode()
+	if ret != nvml.SUCCESS {
+		return false, nvmlError("failed to get persistence mode", ret)
+	}
+	return mode == nvml.FEATURE_ENABLED, nil
+}
+
+// GetComputeMode returns the compute mode of the device.
+func (d nvmlDevice) GetComputeMode() (ComputeMode, error) {
+	mode, ret := d.Device.GetComputeMode()
+	if ret != nvml.SUCCESS {
+		return "", nvmlError("failed to get compute mode", ret)
+	}
+	switch mode {
+	case nvml.COMPUTEMODE_DEFAULT:
+		return ComputeModeDefault, nil
+	case nvml.COMPUTEMODE_EXCLUSIVE_THREAD:
+		return ComputeModeExclusiveThread, nil
+	case nvml.COMPUTEMODE_PROHIBITED:
+		return ComputeModeProhibited, nil
+	case nvml.COMPUTEMODE_EXCLUSIVE_PROCESS:
+		return ComputeModeExclusiveProcess, nil
+	}
+	return "", fmt.Errorf("unexpected compute mode %v", mode)
+}
+
+// nvmlError wraps a failed NVML return value. Queries that the device does not
+// support are reported as ErrNotSupported.
+func nvmlError(message string, ret nvml.Return) error {
+	if ret == nvml.ERROR_NOT_SUPPORTED {
+		return fmt.Errorf("%s: %w", message, ErrNotSupported)
+	}
+	return fmt.Errorf("%s: %w", message, ret)
+}
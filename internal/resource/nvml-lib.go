@@ -17,6 +17,8 @@
 package resource
 
 import (
+	"fmt"
+
 	"github.com/NVIDIA/go-nvlib/pkg/nvlib/device"
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 )
@@ -92,3 +94,49 @@ func (l nvmlLib) Shutdown() error {
 	}
 	return nil
 }
+
+// GetConfComputeState returns the confidential computing state of the system.
+// NVML only reports the CC mode for the system as a whole and not for
+// individual devices.
+func (l nvmlLib) GetConfComputeState() (*ConfComputeState, error) {
+	mode, err := l.getSystemConfComputeMode()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &ConfComputeState{
+		Mode: mode,
+	}
+	if mode != ConfComputeModeOff {
+		settings, ret := l.SystemGetConfComputeSettings()
+		switch {
+		case ret == nvml.SUCCESS:
+			state.ProtectedPCIe = settings.MultiGpuMode == nvml.CC_SYSTEM_MULTIGPU_PROTECTED_PCIE
+		case ret != nvml.ERROR_NOT_SUPPORTED && ret != nvml.ERROR_FUNCTION_NOT_FOUND:
+			return nil, fmt.Errorf("failed to get CC settings: %w", ret)
+		}
+	}
+
+	return state, nil
+}
+
+// getSystemConfComputeMode returns the CC mode of the system. This is off if
+// the driver does not support CC.
+func (l nvmlLib) getSystemConfComputeMode() (ConfComputeMode, error) {
+	state, ret := l.SystemGetConfComputeState()
+	if ret == nvml.ERROR_NOT_SUPPORTED || ret == nvml.ERROR_FUNCTION_NOT_FOUND {
+		return ConfComputeModeOff, nil
+	}
+	if ret != nvml.SUCCESS {
+		return "", fmt.Errorf("failed to get CC state: %w", ret)
+	}
+
+	switch {
+	case state.CcFeature != nvml.CC_SYSTEM_FEATURE_ENABLED:
+		return ConfComputeModeOff, nil
+	case state.DevToolsMode == nvml.CC_SYSTEM_DEVTOOLS_MODE_ON:
+		return ConfComputeModeDevtools, nil
+	default:
+		return ConfComputeModeOn, nil
+	}
+}
@@ -104,6 +104,15 @@ func (d nvmlMigDevice) GetName() (string, error) {
 	return resourceName, nil
 }
 
+// GetUUID returns the UUID of the MIG device.
+func (d nvmlMigDevice) GetUUID() (string, error) {
+	uuid, ret := d.MigDevice.GetUUID()
+	if ret != nvml.SUCCESS {
+		return "", ret
+	}
+	return uuid, nil
+}
+
 // GetTotalMemoryMiB returns the total memory on a device in mebibytes (2^20 bytes)
 func (d nvmlMigDevice) GetTotalMemoryMiB() (uint64, error) {
 	attr, err := d.GetAttributes()
@@ -150,3 +159,39 @@ func (d nvmlMigDevice) IsFabricAttached() (bool, error) {
 func (d nvmlMigDevice) GetFabricIDs() (string, string, error) {
 	return "", "", fmt.Errorf("GetFabricIDs is not supported for MIG devices")
 }
+
+func (d nvmlMigDevice) GetPCIBusID() (string, error) {
+	return "", fmt.Errorf("GetPCIBusID is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetNVLinks() ([]NVLink, error) {
+	return nil, fmt.Errorf("GetNVLinks is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetPCIeLink() (int, int, error) {
+	return 0, 0, fmt.Errorf("GetPCIeLink is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetNumaNode() (bool, int, error) {
+	return false, 0, fmt.Errorf("GetNumaNode is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetVbiosVersion() (string, error) {
+	return "", fmt.Errorf("GetVbiosVersion is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetInforomImageVersion() (string, error) {
+	return "", fmt.Errorf("GetInforomImageVersion is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetECCMode() (bool, bool, error) {
+	return false, false, fmt.Errorf("GetECCMode is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) IsPersistenceModeEnabled() (bool, error) {
+	return false, fmt.Errorf("IsPersistenceModeEnabled is %w for MIG devices", ErrNotSupported)
+}
+
+func (d nvmlMigDevice) GetComputeMode() (ComputeMode, error) {
+	return "", fmt.Errorf("GetComputeMode is %w for MIG devices", ErrNotSupported)
+}
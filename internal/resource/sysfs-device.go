@@ -51,6 +51,11 @@ func (d vfioDevice) GetName() (string, error) {
 	return d.nvidiaPCIDevice.DeviceName, nil
 }
 
+// GetUUID is not supported for GPU devices with vfio pci driver.
+func (d vfioDevice) GetUUID() (string, error) {
+	return "", fmt.Errorf("GetUUID is not supported for vfio devices")
+}
+
 // GetTotalMemoryMiB returns the total memory on a device in mebibytes (2^20 bytes)
 func (d vfioDevice) GetTotalMemoryMiB() (uint64, error) {
 	_, val := d.nvidiaPCIDevice.Resources.GetTotalAddressableMemory(true)
@@ -75,3 +80,44 @@ func (d vfioDevice) IsFabricAttached() (bool, error) {
 func (d vfioDevice) GetFabricIDs() (string, string, error) {
 	return "", "", fmt.Errorf("GetFabricIDs is not supported for vfio devices")
 }
+
+// GetPCIBusID returns the PCI address of the device.
+func (d vfioDevice) GetPCIBusID() (string, error) {
+	return d.nvidiaPCIDevice.Address, nil
+}
+
+func (d vfioDevice) GetNVLinks() ([]NVLink, error) {
+	return nil, fmt.Errorf("GetNVLinks is %w for vfio devices", ErrNotSupported)
+}
+
+func (d vfioDevice) GetPCIeLink() (int, int, error) {
+	return 0, 0, fmt.Errorf("GetPCIeLink is %w for vfio devices", ErrNotSupported)
+}
+
+// GetNumaNode returns the NUMA node of the device if it is known.
+func (d vfioDevice) GetNumaNode() (bool, int, error) {
+	if d.nvidiaPCIDevice.NumaNode < 0 {
+		return false, 0, nil
+	}
+	return true, d.nvidiaPCIDevice.NumaNode, nil
+}
+
+func (d vfioDevice) GetVbiosVersion() (string, error) {
+	return "", fmt.Errorf("GetVbiosVersion is %w for vfio devices", ErrNotSupported)
+}
+
+func (d vfioDevice) GetInforomImageVersion() (string, error) {
+	return "", fmt.Errorf("GetInforomImageVersion is %w for vfio devices", ErrNotSupported)
+}
+
+func (d vfioDevice) GetECCMode() (bool, bool, error) {
+	return false, false, fmt.Errorf("GetECCMode is %w for vfio devices", ErrNotSupported)
+}
+
+func (d vfioDevice) IsPersistenceModeEnabled() (bool, error) {
+	return false, fmt.Errorf("IsPersistenceModeEnabled is %w for vfio devices", ErrNotSupported)
+}
+
+func (d vfioDevice) GetComputeMode() (ComputeMode, error) {
+	return "", fmt.Errorf("GetComputeMode is %w for vfio devices", ErrNotSupported)
+}
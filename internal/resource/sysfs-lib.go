@@ -17,6 +17,8 @@
 package resource
 
 import (
+	"fmt"
+
 	"github.com/NVIDIA/go-nvlib/pkg/nvpci"
 	"k8s.io/klog/v2"
 )
@@ -72,3 +74,8 @@ func (l *vfioLib) GetCudaDriverVersion() (int, int, error) {
 func (l *vfioLib) GetDriverVersion() (string, error) {
 	return "unknown.unknown.unknown", nil
 }
+
+// GetConfComputeState is not supported
+func (l *vfioLib) GetConfComputeState() (*ConfComputeState, error) {
+	return nil, fmt.Errorf("GetConfComputeState is %w for vfio devices", ErrNotSupported)
+}
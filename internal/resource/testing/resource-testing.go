@@ -41,18 +41,30 @@ func NewMigEnabledDevice(migs ...*resource.DeviceMock) resource.Device {
 func NewDeviceMock(migEnabled bool) *DeviceMock {
 	d := DeviceMock{resource.DeviceMock{
 		GetNameFunc: func() (string, error) { return "MOCKMODEL", nil },
+		GetUUIDFunc: func() (string, error) { return "", nil },
 		GetCudaComputeCapabilityFunc: func() (int, int, error) {
 			if migEnabled {
 				return 0, 0, nil
 			}
 			return 8, 0, nil
 		},
-		GetTotalMemoryMiBFunc: func() (uint64, error) { return uint64(300), nil },
-		IsFabricAttachedFunc:  func() (bool, error) { return false, nil },
-		IsMigEnabledFunc:      func() (bool, error) { return migEnabled, nil },
-		IsMigCapableFunc:      func() (bool, error) { return migEnabled, nil },
-		GetMigDevicesFunc:     func() ([]resource.Device, error) { return nil, nil },
-		GetPCIClassFunc:       func() (uint32, error) { return 0, nil },
+		GetTotalMemoryMiBFunc:        func() (uint64, error) { return uint64(300), nil },
+		IsFabricAttachedFunc:         func() (bool, error) { return false, nil },
+		IsMigEnabledFunc:             func() (bool, error) { return migEnabled, nil },
+		IsMigCapableFunc:             func() (bool, error) { return migEnabled, nil },
+		GetMigDevicesFunc:            func() ([]resource.Device, error) { return nil, nil },
+		GetPCIClassFunc:              func() (uint32, error) { return 0, nil },
+		GetPCIBusIDFunc:              func() (string, error) { return "", nil },
+		GetNVLinksFunc:               func() ([]resource.NVLink, error) { return nil, nil },
+		GetPCIeLinkFunc:              func() (int, int, error) { return 4, 16, nil },
+		GetNumaNodeFunc:              func() (bool, int, error) { return false, 0, nil },
+		GetVbiosVersionFunc:          func() (string, error) { return "96.00.89.00.01", nil },
+		GetInforomImageVersionFunc:   func() (string, error) { return "G500.0200.00.03", nil },
+		GetECCModeFunc:               func() (bool, bool, error) { return true, true, nil },
+		IsPersistenceModeEnabledFunc: func() (bool, error) { return true, nil },
+		GetComputeModeFunc: func() (resource.ComputeMode, error) {
+			return resource.ComputeModeDefault, nil
+		},
 	}}
 	return &d
 }
@@ -90,6 +102,54 @@ func NewMigDevice(gi int, ci int, gb uint64, attributes ...map[string]interface{
 	}
 }
 
+// WithName sets the name of the mocked device
+func (d *DeviceMock) WithName(name string) *DeviceMock {
+	d.GetNameFunc = func() (string, error) {
+		return name, nil
+	}
+	return d
+}
+
+// WithUUID sets the UUID of the mocked device
+func (d *DeviceMock) WithUUID(uuid string) *DeviceMock {
+	d.GetUUIDFunc = func() (string, error) {
+		return uuid, nil
+	}
+	return d
+}
+
+// WithPCIBusID sets the PCI bus ID of the mocked device
+func (d *DeviceMock) WithPCIBusID(busID string) *DeviceMock {
+	d.GetPCIBusIDFunc = func() (string, error) {
+		return busID, nil
+	}
+	return d
+}
+
+// WithNVLinks sets the active NVLinks of the mocked device
+func (d *DeviceMock) WithNVLinks(links ...resource.NVLink) *DeviceMock {
+	d.GetNVLinksFunc = func() ([]resource.NVLink, error) {
+		return links, nil
+	}
+	return d
+}
+
+// WithPCIeLink sets the PCIe link generation and width of the mocked device
+func (d *DeviceMock) WithPCIeLink(generation int, width int) *DeviceMock {
+	d.GetPCIeLinkFunc = func() (int, int, error) {
+		return generation, width, nil
+	}
+	return d
+}
+
+// WithNumaNode sets the NUMA node of the mocked device
+func (d *DeviceMock) WithNumaNode(node int) *DeviceMock {
+	d.GetNumaNodeFunc = func() (bool, int, error) {
+		return true, node, nil
+	}
+	return d
+}
+
 // WithMigDevices adds the specified MIG devices to the mocked device
 func (d *DeviceMock) WithMigDevices(migs ...*resource.DeviceMock) *DeviceMock {
 	for _, m := range migs {
@@ -127,10 +187,24 @@ func NewManagerMockWithDevices(devices ...resource.Device) *ManagerMock {
 		GetCudaDriverVersionFunc: func() (int, int, error) {
 			return 8, 0, nil
 		},
+		GetConfComputeStateFunc: func() (*resource.ConfComputeState, error) {
+			state := &resource.ConfComputeState{
+				Mode: resource.ConfComputeModeOff,
+			}
+			return state, nil
+		},
 	}}
 	return &manager
 }
 
+// WithConfComputeState sets the confidential computing state reported by the ManagerMock.
+func (m *ManagerMock) WithConfComputeState(state *resource.ConfComputeState) *ManagerMock {
+	m.GetConfComputeStateFunc = func() (*resource.ConfComputeState, error) {
+		return state, nil
+	}
+	return m
+}
+
 // WithErrorOnInit sets the Init function for the ManagerMock to error if called.
 func (m *ManagerMock) WithErrorOnInit(err error) *ManagerMock {
 	m.InitFunc = func() error {
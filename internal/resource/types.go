@@ -16,6 +16,12 @@
 
 package resource
 
+import "errors"
+
+// ErrNotSupported is returned by Device methods for attributes that the
+// device or backend cannot provide.
+var ErrNotSupported = errors.New("not supported")
+
 // Manager defines an interface for managing devices
 //
 //go:generate moq -rm -out manager_mock.go . Manager
@@ -25,6 +31,26 @@ type Manager interface {
 	GetDevices() ([]Device, error)
 	GetDriverVersion() (string, error)
 	GetCudaDriverVersion() (int, int, error)
+	GetConfComputeState() (*ConfComputeState, error)
+}
+
+// ConfComputeMode defines the confidential computing (CC) mode of the system.
+type ConfComputeMode string
+
+// Constants for the supported CC modes.
+const (
+	ConfComputeModeOn       = ConfComputeMode("on")
+	ConfComputeModeOff      = ConfComputeMode("off")
+	ConfComputeModeDevtools = ConfComputeMode("devtools")
+)
+
+// ConfComputeState describes the confidential computing state of a node.
+type ConfComputeState struct {
+	// Mode is the CC mode of the system.
+	Mode ConfComputeMode
+	// ProtectedPCIe indicates whether the GPUs are in the multi-GPU protected
+	// PCIe mode.
+	ProtectedPCIe bool
 }
 
 // Device defines an interface for a device with which labels are associated
@@ -37,9 +63,49 @@ type Device interface {
 	GetMigDevices() ([]Device, error)
 	GetAttributes() (map[string]interface{}, error)
 	GetName() (string, error)
+	GetUUID() (string, error)
 	GetTotalMemoryMiB() (uint64, error)
 	GetDeviceHandleFromMigDeviceHandle() (Device, error)
 	GetCudaComputeCapability() (int, int, error)
 	GetPCIClass() (uint32, error)
 	GetFabricIDs() (string, string, error)
+	GetPCIBusID() (string, error)
+	GetNVLinks() ([]NVLink, error)
+	GetPCIeLink() (int, int, error)
+	GetNumaNode() (bool, int, error)
+	GetVbiosVersion() (string, error)
+	GetInforomImageVersion() (string, error)
+	GetECCMode() (bool, bool, error)
+	IsPersistenceModeEnabled() (bool, error)
+	GetComputeMode() (ComputeMode, error)
+}
+
+// ComputeMode defines the compute mode of a device.
+type ComputeMode string
+
+// Constants for the supported compute modes.
+const (
+	ComputeModeDefault          = ComputeMode("default")
+	ComputeModeExclusiveThread  = ComputeMode("exclusive-thread")
+	ComputeModeProhibited       = ComputeMode("prohibited")
+	ComputeModeExclusiveProcess = ComputeMode("exclusive-process")
+)
+
+// NVLinkRemoteType defines the type of device at the remote end of an NVLink.
+type NVLinkRemoteType string
+
+// Constants for the supported NVLink remote types.
+const (
+	NVLinkRemoteGPU     = NVLinkRemoteType("gpu")
+	NVLinkRemoteSwitch  = NVLinkRemoteType("switch")
+	NVLinkRemoteUnknown = NVLinkRemoteType("unknown")
+)
+
+// NVLink describes an active NVLink of a device.
+type NVLink struct {
+	// RemoteType is the type of the device at the remote end of the link.
+	RemoteType NVLinkRemoteType
+	// RemotePCIBusID is the PCI bus ID of the device at the remote end of the
+	// link.
+	RemotePCIBusID string
 }
@@ -0,0 +1,47 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"k8s.io/klog/v2"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+// getExpectedCCMode returns the confidential computing (CC) mode that GPUs
+// are expected to be in to be advertised.
+func getExpectedCCMode(config *spec.Config) string {
+	if config.Flags.Plugin == nil || config.Flags.Plugin.CCMode == nil {
+		return spec.CCModeAny
+	}
+	return *config.Flags.Plugin.CCMode
+}
+
+// filterByCCMode returns a device map without any devices if the system is
+// not in the expected CC mode. NVML only reports the CC mode of the system,
+// which applies to all GPUs that support CC, so the devices are not filtered
+// individually.
+func (d DeviceMap) filterByCCMode(state *resource.ConfComputeState, expected string) DeviceMap {
+	if string(state.Mode) == expected {
+		return d
+	}
+	for name, devices := range d {
+		klog.Warningf("Not advertising %d devices of resource %v: CC mode of the system is %q, expected %q", len(devices), name, state.Mode, expected)
+	}
+	return make(DeviceMap)
+}
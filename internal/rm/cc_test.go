@@ -0,0 +1,48 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
+)
+
+func TestDeviceMapFilterByCCMode(t *testing.T) {
+	gpu := spec.ResourceName("nvidia.com/gpu")
+	mig := spec.ResourceName("nvidia.com/mig-1g.10gb")
+
+	deviceMap := make(DeviceMap)
+	deviceMap.insert(gpu, &Device{Device: pluginapi.Device{ID: "GPU-0"}, Index: "0"})
+	deviceMap.insert(gpu, &Device{Device: pluginapi.Device{ID: "GPU-1"}, Index: "1"})
+	deviceMap.insert(mig, &Device{Device: pluginapi.Device{ID: "MIG-2-0"}, Index: "2:0"})
+	deviceMap.insert(gpu, &Device{Device: pluginapi.Device{ID: "GPU-3"}, Index: "3"})
+
+	state := &resource.ConfComputeState{
+		Mode: resource.ConfComputeModeOn,
+	}
+
+	filtered := deviceMap.filterByCCMode(state, spec.CCModeOn)
+	require.Equal(t, deviceMap, filtered)
+
+	filtered = deviceMap.filterByCCMode(state, spec.CCModeOff)
+	require.Empty(t, filtered)
+}
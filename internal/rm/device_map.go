@@ -249,11 +249,12 @@ func (d DeviceMap) getIDsOfDevicesToReplicate(r *spec.ReplicatedResource) ([]str
 	}
 
 	// If a specific number of devices for this resource type are to be replicated.
+	// The devices with the lowest indices are selected.
 	if r.Devices.Count > 0 {
 		if r.Devices.Count > len(devices) {
 			return nil, fmt.Errorf("requested %d devices to be replicated, but only %d devices available", r.Devices.Count, len(devices))
 		}
-		return devices.GetIDs()[:r.Devices.Count], nil
+		return devices.GetIDsInIndexOrder()[:r.Devices.Count], nil
 	}
 
 	// If a specific set of devices for this resource type are to be replicated.
@@ -323,21 +324,29 @@ func updateDeviceMapWithReplicas(replicatedResources *spec.ReplicatedResources,
 		if r.Rename != "" {
 			name = r.Rename
 		}
+		var activeThreadPercentage int
+		if r.ActiveThreadPercentage != nil {
+			activeThreadPercentage = *r.ActiveThreadPercentage
+		}
 		for _, id := range ids {
-			for i := 0; i < r.Replicas; i++ {
+			original := oDevices[r.Name][id]
+			replicas := r.ReplicasFor(original.Index, original.ID)
+			for i := 0; i < replicas; i++ {
 				annotatedID := string(NewAnnotatedID(id, i))
-				original := oDevices[r.Name][id]
 				replicatedDevice := Device{
 					Device: pluginapi.Device{
 						ID:       annotatedID,
 						Health:   original.Health,
 						Topology: original.Topology,
 					},
-					Paths:             original.Paths,
-					Index:             original.Index,
-					TotalMemory:       original.TotalMemory,
-					ComputeCapability: original.ComputeCapability,
-					Replicas:          r.Replicas,
+					Paths:                  original.Paths,
+					Index:                  original.Index,
+					TotalMemory:            original.TotalMemory,
+					ComputeCapability:      original.ComputeCapability,
+					Replicas:               replicas,
+					MemoryLimit:            r.MemoryLimit.ForDevice(original.TotalMemory),
+					PinnedMemoryLimit:      r.PinnedMemoryLimit.ForDevice(original.TotalMemory),
+					ActiveThreadPercentage: activeThreadPercentage,
 				}
 				devices.insert(name, &replicatedDevice)
 			}
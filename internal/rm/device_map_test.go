@@ -21,6 +21,7 @@ import (
 
 	"github.com/stretchr/testify/require"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"k8s.io/utils/ptr"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
 )
@@ -131,3 +132,103 @@ func TestDeviceMapInsert(t *testing.T) {
 		})
 	}
 }
+
+func TestUpdateDeviceMapWithHeterogeneousReplicas(t *testing.T) {
+	const gib = 1024 * 1024 * 1024
+	resourceName := spec.ResourceName("nvidia.com/gpu")
+	devices := DeviceMap{
+		resourceName: Devices{
+			"GPU-0": &Device{Device: pluginapi.Device{ID: "GPU-0"}, Index: "0", TotalMemory: 16 * gib},
+			"GPU-1": &Device{Device: pluginapi.Device{ID: "GPU-1"}, Index: "1", TotalMemory: 16 * gib},
+			"GPU-2": &Device{Device: pluginapi.Device{ID: "GPU-2"}, Index: "2", TotalMemory: 16 * gib},
+		},
+	}
+
+	replicated, err := updateDeviceMapWithReplicas(&spec.ReplicatedResources{
+		Resources: []spec.ReplicatedResource{
+			{
+				Name:     resourceName,
+				Devices:  spec.ReplicatedDevices{All: true},
+				Replicas: 4,
+				DeviceReplicas: []spec.DeviceReplicas{
+					{Devices: spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"0"}}, Replicas: 2},
+					{Devices: spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"GPU-1"}}, Replicas: 7},
+				},
+				ActiveThreadPercentage: ptr.To(30),
+				PinnedMemoryLimit:      &spec.ReplicaMemoryLimit{Percent: 25},
+			},
+		},
+	}, devices)
+	require.NoError(t, err)
+
+	replicasPerDevice := make(map[string]int)
+	for _, d := range replicated[resourceName] {
+		replicasPerDevice[d.Index]++
+		require.Equal(t, 30, d.ActiveThreadPercentage)
+		require.EqualValues(t, 4*gib, d.PinnedMemoryLimit)
+		switch d.Index {
+		case "0":
+			require.Equal(t, 2, d.Replicas)
+		case "1":
+			require.Equal(t, 7, d.Replicas)
+		default:
+			require.Equal(t, 4, d.Replicas)
+		}
+	}
+	require.Equal(t, map[string]int{"0": 2, "1": 7, "2": 4}, replicasPerDevice)
+}
+
+func TestUpdateDeviceMapWithPartialReplicas(t *testing.T) {
+	resourceName := spec.ResourceName("nvidia.com/gpu")
+	sharedResourceName := spec.ResourceName("nvidia.com/gpu.shared")
+	gpu1 := "GPU-b1028956-cfa2-0990-bf4a-5da9abb51763"
+	newDevices := func() DeviceMap {
+		return DeviceMap{
+			resourceName: Devices{
+				"GPU-0":  &Device{Device: pluginapi.Device{ID: "GPU-0"}, Index: "0"},
+				gpu1:     &Device{Device: pluginapi.Device{ID: gpu1}, Index: "1"},
+				"GPU-2":  &Device{Device: pluginapi.Device{ID: "GPU-2"}, Index: "2"},
+				"GPU-10": &Device{Device: pluginapi.Device{ID: "GPU-10"}, Index: "10"},
+			},
+		}
+	}
+
+	testCases := []struct {
+		description       string
+		devices           spec.ReplicatedDevices
+		expectedShared    []string
+		expectedExclusive []string
+	}{
+		{
+			description:       "count selects the devices with the lowest indices",
+			devices:           spec.ReplicatedDevices{Count: 3},
+			expectedShared:    []string{"GPU-0::0", "GPU-0::1", gpu1 + "::0", gpu1 + "::1", "GPU-2::0", "GPU-2::1"},
+			expectedExclusive: []string{"GPU-10"},
+		},
+		{
+			description:       "list selects devices by index and UUID",
+			devices:           spec.ReplicatedDevices{List: []spec.ReplicatedDeviceRef{"10", spec.ReplicatedDeviceRef(gpu1)}},
+			expectedShared:    []string{gpu1 + "::0", gpu1 + "::1", "GPU-10::0", "GPU-10::1"},
+			expectedExclusive: []string{"GPU-0", "GPU-2"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			replicated, err := updateDeviceMapWithReplicas(&spec.ReplicatedResources{
+				Resources: []spec.ReplicatedResource{
+					{
+						Name:     resourceName,
+						Rename:   sharedResourceName,
+						Devices:  tc.devices,
+						Replicas: 2,
+					},
+				},
+			}, newDevices())
+			require.NoError(t, err)
+
+			require.ElementsMatch(t, tc.expectedShared, replicated[sharedResourceName].GetIDs())
+			require.ElementsMatch(t, tc.expectedExclusive, replicated[resourceName].GetIDs())
+		})
+	}
+}
@@ -17,7 +17,10 @@
 package rm
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -35,6 +38,17 @@ type Device struct {
 	// Replicas stores the total number of times this device is replicated.
 	// If this is 0 or 1 then the device is not shared.
 	Replicas int
+	// MemoryLimit stores the memory limit in bytes for a replica of this
+	// device. If this is 0 then the memory of the device is not limited.
+	MemoryLimit uint64
+	// PinnedMemoryLimit stores the explicit MPS pinned memory limit in bytes
+	// for a replica of this device. If this is 0 then the limit is derived
+	// from the number of replicas.
+	PinnedMemoryLimit uint64
+	// ActiveThreadPercentage stores the explicit MPS active thread percentage
+	// for a replica of this device. If this is 0 then the percentage is
+	// derived from the number of replicas.
+	ActiveThreadPercentage int
 }
 
 // deviceInfo defines the information the required to construct a Device
@@ -160,6 +174,45 @@ func (ds Devices) GetIDs() []string {
 	return res
 }
 
+// GetIDsInIndexOrder returns the ids from all devices in the Devices ordered
+// by the index of the devices. MIG devices are ordered by the index of their
+// parent GPU first.
+func (ds Devices) GetIDsInIndexOrder() []string {
+	devices := slices.Collect(maps.Values(ds))
+	slices.SortFunc(devices, func(a, b *Device) int {
+		if c := compareIndices(a.Index, b.Index); c != 0 {
+			return c
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+	var res []string
+	for _, d := range devices {
+		res = append(res, d.ID)
+	}
+	return res
+}
+
+// compareIndices compares two device indices of the form <gpu> or <gpu>:<mig>
+// numerically.
+func compareIndices(a, b string) int {
+	as := strings.Split(a, ":")
+	bs := strings.Split(b, ":")
+	for i := 0; i < min(len(as), len(bs)); i++ {
+		ai, aErr := strconv.Atoi(as[i])
+		bi, bErr := strconv.Atoi(bs[i])
+		if aErr != nil || bErr != nil {
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+			continue
+		}
+		if c := cmp.Compare(ai, bi); c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(as), len(bs))
+}
+
 // GetUUIDs returns the uuids associated with the Device in the set.
 func (ds Devices) GetUUIDs() []string {
 	var res []string
@@ -18,32 +18,24 @@ package rm
 
 import (
 	"fmt"
-	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"k8s.io/klog/v2"
-)
 
-const (
-	// envDisableHealthChecks defines the environment variable that is checked to determine whether healthchecks
-	// should be disabled. If this envvar is set to "all" or contains the string "xids", healthchecks are
-	// disabled entirely. If set, the envvar is treated as a comma-separated list of Xids to ignore. Note that
-	// this is in addition to the Application errors that are already ignored.
-	envDisableHealthChecks = "DP_DISABLE_HEALTHCHECKS"
-	// envEnableHealthChecks defines the environment variable that is checked to
-	// determine which XIDs should be explicitly enabled. XIDs specified here
-	// override the ones specified in the `DP_DISABLE_HEALTHCHECKS`.
-	// Note that this also allows individual XIDs to be selected when ALL XIDs
-	// are disabled.
-	envEnableHealthChecks = "DP_ENABLE_HEALTHCHECKS"
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/metrics"
 )
 
-// CheckHealth performs health checks on a set of devices, writing to the 'unhealthy' channel with any unhealthy devices
-func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devices, unhealthy chan<- *Device) error {
-	xids := getDisabledHealthCheckXids()
-	if xids.IsAllDisabled() {
+// checkHealth performs health checks on a set of devices, writing a HealthEvent
+// to the 'health' channel whenever a device is marked unhealthy or has recovered.
+func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devices, health chan<- *HealthEvent) error {
+	healthChecks := r.config.HealthChecks.ForResource(r.resource)
+	xids := getDisabledHealthCheckXids(healthChecks)
+	rules := newHealthRules(healthChecks.Rules)
+	if xids.IsAllDisabled() && len(rules.rules) == 0 {
 		return nil
 	}
 
@@ -63,6 +55,18 @@ func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devic
 
 	klog.Infof("Ignoring the following XIDs for health checks: %v", xids)
 
+	recovery := newHealthRecovery(r.config, r.probeDevice)
+	unhealthy := func(d *Device, reason string) {
+		recovery.markUnhealthy(d)
+		health <- NewUnhealthyEvent(d, reason)
+	}
+	// Devices for which health events cannot be registered are not monitored
+	// and must therefore never be recovered.
+	unmonitored := func(d *Device, reason string) {
+		recovery.markUnmonitored(d)
+		health <- NewUnhealthyEvent(d, reason)
+	}
+
 	eventSet, ret := r.nvml.EventSetCreate()
 	if ret != nvml.SUCCESS {
 		return fmt.Errorf("failed to create event set: %v", ret)
@@ -71,33 +75,41 @@ func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devic
 		_ = eventSet.Free()
 	}()
 
-	parentToDeviceMap := make(map[string]*Device)
+	// Multiple devices (e.g. MIG devices or replicas) may share the same
+	// parent GPU. We track all of them so that events and rule failures for
+	// a GPU are applied to each of its devices.
+	parentToDevices := make(map[string][]*Device)
+	parentToHandle := make(map[string]nvml.Device)
 	deviceIDToGiMap := make(map[string]uint32)
 	deviceIDToCiMap := make(map[string]uint32)
 
-	eventMask := uint64(nvml.EventTypeXidCriticalError | nvml.EventTypeDoubleBitEccError | nvml.EventTypeSingleBitEccError)
+	eventMask := getHealthEventMask(healthChecks)
 	for _, d := range devices {
 		uuid, gi, ci, err := r.getDevicePlacement(d)
 		if err != nil {
 			klog.Warningf("Could not determine device placement for %v: %v; Marking it unhealthy.", d.ID, err)
-			unhealthy <- d
+			unmonitored(d, fmt.Sprintf("could not determine device placement: %v", err))
 			continue
 		}
 		deviceIDToGiMap[d.ID] = gi
 		deviceIDToCiMap[d.ID] = ci
-		parentToDeviceMap[uuid] = d
+		parentToDevices[uuid] = append(parentToDevices[uuid], d)
 
 		gpu, ret := r.nvml.DeviceGetHandleByUUID(uuid)
 		if ret != nvml.SUCCESS {
 			klog.Infof("unable to get device handle from UUID: %v; marking it as unhealthy", ret)
-			unhealthy <- d
+			unmonitored(d, fmt.Sprintf("unable to get device handle from UUID: %v", ret))
+			continue
+		}
+		if _, registered := parentToHandle[uuid]; registered {
 			continue
 		}
+		parentToHandle[uuid] = gpu
 
 		supportedEvents, ret := gpu.GetSupportedEventTypes()
 		if ret != nvml.SUCCESS {
 			klog.Infof("unable to determine the supported events for %v: %v; marking it as unhealthy", d.ID, ret)
-			unhealthy <- d
+			unmonitored(d, fmt.Sprintf("unable to determine the supported events: %v", ret))
 			continue
 		}
 
@@ -107,10 +119,11 @@ func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devic
 			klog.Warningf("Device %v is too old to support healthchecking.", d.ID)
 		case ret != nvml.SUCCESS:
 			klog.Infof("Marking device %v as unhealthy: %v", d.ID, ret)
-			unhealthy <- d
+			unmonitored(d, fmt.Sprintf("unable to register events: %v", ret))
 		}
 	}
 
+	var lastPoll time.Time
 	for {
 		select {
 		case <-stop:
@@ -118,6 +131,23 @@ func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devic
 		default:
 		}
 
+		if len(rules.rules) > 0 && time.Since(lastPoll) >= rules.pollInterval {
+			lastPoll = time.Now()
+			for uuid, gpu := range parentToHandle {
+				for _, err := range rules.check(gpu) {
+					klog.Infof("Health rule failed for %v: %v; marking its devices as unhealthy.", uuid, err)
+					for _, d := range parentToDevices[uuid] {
+						unhealthy(d, err.Error())
+					}
+				}
+			}
+		}
+
+		for _, d := range recovery.recovered() {
+			klog.Infof("Device %v has recovered; marking it as healthy.", d.ID)
+			health <- NewHealthyEvent(d, "device recovered")
+		}
+
 		e, ret := eventSet.Wait(5000)
 		if ret == nvml.ERROR_TIMEOUT {
 			continue
@@ -125,50 +155,87 @@ func (r *nvmlResourceManager) checkHealth(stop <-chan interface{}, devices Devic
 		if ret != nvml.SUCCESS {
 			klog.Infof("Error waiting for event: %v; Marking all devices as unhealthy", ret)
 			for _, d := range devices {
-				unhealthy <- d
+				unhealthy(d, fmt.Sprintf("error waiting for event: %v", ret))
 			}
 			continue
 		}
 
-		if e.EventType != nvml.EventTypeXidCriticalError {
+		var reason string
+		switch {
+		case e.EventType == nvml.EventTypeXidCriticalError:
+			r.recordXid(e)
+			if xids.IsDisabled(e.EventData) {
+				klog.Infof("Skipping event %+v", e)
+				continue
+			}
+			reason = fmt.Sprintf("XidCriticalError: Xid=%d", e.EventData)
+		case e.EventType == nvml.EventTypeDoubleBitEccError && rules.dbeEvents:
+			reason = "DoubleBitEccError"
+		default:
 			klog.Infof("Skipping non-nvmlEventTypeXidCriticalError event: %+v", e)
 			continue
 		}
 
-		if xids.IsDisabled(e.EventData) {
-			klog.Infof("Skipping event %+v", e)
-			continue
-		}
-
 		klog.Infof("Processing event %+v", e)
 		eventUUID, ret := e.Device.GetUUID()
 		if ret != nvml.SUCCESS {
 			// If we cannot reliably determine the device UUID, we mark all devices as unhealthy.
 			klog.Infof("Failed to determine uuid for event %v: %v; Marking all devices as unhealthy.", e, ret)
 			for _, d := range devices {
-				unhealthy <- d
+				unhealthy(d, fmt.Sprintf("failed to determine uuid for event: %v", ret))
 			}
 			continue
 		}
 
-		d, exists := parentToDeviceMap[eventUUID]
+		affected, exists := parentToDevices[eventUUID]
 		if !exists {
 			klog.Infof("Ignoring event for unexpected device: %v", eventUUID)
 			continue
 		}
 
-		if d.IsMigDevice() && e.GpuInstanceId != 0xFFFFFFFF && e.ComputeInstanceId != 0xFFFFFFFF {
-			gi := deviceIDToGiMap[d.ID]
-			ci := deviceIDToCiMap[d.ID]
-			if gi != e.GpuInstanceId || ci != e.ComputeInstanceId {
-				continue
+		for _, d := range affected {
+			if d.IsMigDevice() && e.GpuInstanceId != 0xFFFFFFFF && e.ComputeInstanceId != 0xFFFFFFFF {
+				gi := deviceIDToGiMap[d.ID]
+				ci := deviceIDToCiMap[d.ID]
+				if gi != e.GpuInstanceId || ci != e.ComputeInstanceId {
+					continue
+				}
+				klog.Infof("Event for mig device %v (gi=%v, ci=%v)", d.ID, gi, ci)
 			}
-			klog.Infof("Event for mig device %v (gi=%v, ci=%v)", d.ID, gi, ci)
+
+			klog.Infof("%s on Device=%s; marking device as unhealthy.", reason, d.ID)
+			unhealthy(d, reason)
+		}
+	}
+}
+
+// recordXid records the XID of the specified event in the device plugin metrics.
+func (r *nvmlResourceManager) recordXid(e nvml.EventData) {
+	gpu := "unknown"
+	if e.Device != nil {
+		if uuid, ret := e.Device.GetUUID(); ret == nvml.SUCCESS {
+			gpu = uuid
 		}
+	}
+	metrics.XidErrors.WithLabelValues(string(r.resource), gpu, strconv.FormatUint(e.EventData, 10)).Inc()
+}
 
-		klog.Infof("XidCriticalError: Xid=%d on Device=%s; marking device as unhealthy.", e.EventData, d.ID)
-		unhealthy <- d
+// probeDevice checks whether the specified device can be queried through NVML.
+// This is used to confirm that a device has recovered before marking it as
+// healthy.
+func (r *nvmlResourceManager) probeDevice(d *Device) error {
+	uuid, _, _, err := r.getDevicePlacement(d)
+	if err != nil {
+		return fmt.Errorf("could not determine device placement: %w", err)
 	}
+	gpu, ret := r.nvml.DeviceGetHandleByUUID(uuid)
+	if ret != nvml.SUCCESS {
+		return fmt.Errorf("unable to get device handle from UUID: %v", ret)
+	}
+	if _, ret := gpu.GetMemoryInfo(); ret != nvml.SUCCESS {
+		return fmt.Errorf("unable to get device memory info: %v", ret)
+	}
+	return nil
 }
 
 const allXIDs = 0
@@ -214,17 +281,9 @@ func (h disabledXIDs) IsDisabled(xid uint64) bool {
 //
 // Note that if an XID is explicitly enabled, this takes precedence over it
 // having been disabled either explicitly or implicitly.
-func getDisabledHealthCheckXids() disabledXIDs {
-	disabled := newHealthCheckXIDs(
-		// TODO: We should not read the envvar here directly, but instead
-		// "upgrade" this to a top-level config option.
-		strings.Split(strings.ToLower(os.Getenv(envDisableHealthChecks)), ",")...,
-	)
-	enabled := newHealthCheckXIDs(
-		// TODO: We should not read the envvar here directly, but instead
-		// "upgrade" this to a top-level config option.
-		strings.Split(strings.ToLower(os.Getenv(envEnableHealthChecks)), ",")...,
-	)
+func getDisabledHealthCheckXids(healthChecks spec.HealthChecks) disabledXIDs {
+	disabled := newHealthCheckXIDs(healthChecks.DisabledXIDs)
+	enabled := newHealthCheckXIDs(healthChecks.EnabledXIDs)
 
 	// Add the list of hardcoded disabled (ignored) XIDs:
 	// FIXME: formalize the full list and document it.
@@ -249,33 +308,38 @@ func getDisabledHealthCheckXids() disabledXIDs {
 	return disabled
 }
 
-// newHealthCheckXIDs converts a list of Xids to a healthCheckXIDs map.
-// Special xid values 'all' and 'xids' return a special map that matches all
-// xids.
-// For other xids, these are converted to a uint64 values with invalid values
-// being ignored.
-func newHealthCheckXIDs(xids ...string) disabledXIDs {
+// newHealthCheckXIDs converts the configured XIDs to a disabledXIDs map.
+// If all XIDs are selected, a special map that matches all xids is returned.
+func newHealthCheckXIDs(xids *spec.XIDs) disabledXIDs {
 	output := make(disabledXIDs)
-	for _, xid := range xids {
-		trimmed := strings.TrimSpace(xid)
-		if trimmed == "all" || trimmed == "xids" {
-			// TODO: We should have a different type for "all" and "all-except"
-			return disabledXIDs{allXIDs: true}
-		}
-		if trimmed == "" {
-			continue
-		}
-		id, err := strconv.ParseUint(trimmed, 10, 64)
-		if err != nil {
-			klog.Infof("Ignoring malformed Xid value %v: %v", trimmed, err)
-			continue
-		}
-
+	if xids == nil {
+		return output
+	}
+	if xids.All {
+		// TODO: We should have a different type for "all" and "all-except"
+		return disabledXIDs{allXIDs: true}
+	}
+	for _, id := range xids.IDs {
 		output[id] = true
 	}
 	return output
 }
 
+// getHealthEventMask returns the NVML event mask for the configured health event types.
+func getHealthEventMask(healthChecks spec.HealthChecks) uint64 {
+	var eventMask uint64
+	if healthChecks.WatchesEventType(spec.HealthEventTypeXid) {
+		eventMask |= nvml.EventTypeXidCriticalError
+	}
+	if healthChecks.WatchesEventType(spec.HealthEventTypeDoubleBitEcc) {
+		eventMask |= nvml.EventTypeDoubleBitEccError
+	}
+	if healthChecks.WatchesEventType(spec.HealthEventTypeSingleBitEcc) {
+		eventMask |= nvml.EventTypeSingleBitEccError
+	}
+	return eventMask
+}
+
 // getDevicePlacement returns the placement of the specified device.
 // For a MIG device the placement is defined by the 3-tuple <parent UUID, GI, CI>
 // For a full device the returned 3-tuple is the device's uuid and 0xFFFFFFFF for the other two elements.
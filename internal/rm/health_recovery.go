@@ -0,0 +1,166 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"time"
+
+	"k8s.io/klog/v2"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// HealthEvent represents a transition in the health of a device.
+type HealthEvent struct {
+	Device *Device
+	// Health is the new health of the device. This is one of
+	// pluginapi.Healthy or pluginapi.Unhealthy.
+	Health string
+	Reason string
+	// Source identifies the health check that sent the event. A device that
+	// was marked unhealthy by multiple sources is only marked healthy once
+	// each of them has marked it healthy again. The NVML health checks use
+	// the empty source.
+	Source string
+}
+
+// NewUnhealthyEvent creates a HealthEvent marking the specified device as unhealthy.
+func NewUnhealthyEvent(d *Device, reason string) *HealthEvent {
+	return &HealthEvent{
+		Device: d,
+		Health: pluginapi.Unhealthy,
+		Reason: reason,
+	}
+}
+
+// NewHealthyEvent creates a HealthEvent marking the specified device as healthy.
+func NewHealthyEvent(d *Device, reason string) *HealthEvent {
+	return &HealthEvent{
+		Device: d,
+		Health: pluginapi.Healthy,
+		Reason: reason,
+	}
+}
+
+// IsHealthy returns whether the event marks the device as healthy.
+func (e *HealthEvent) IsHealthy() bool {
+	return e.Health == pluginapi.Healthy
+}
+
+// healthRecovery tracks devices that have been marked unhealthy and determines
+// when these can be returned to a healthy state according to the configured
+// recovery policy.
+type healthRecovery struct {
+	policy      string
+	quietPeriod time.Duration
+	// probe is called for devices that have been quiet for the configured
+	// period if the nvml-probe policy is selected. A device is only recovered
+	// if the probe returns no error.
+	probe func(*Device) error
+	now   func() time.Time
+
+	lastEvent map[string]time.Time
+	devices   map[string]*Device
+	// unmonitored holds the devices for which health events could not be
+	// registered. Since no health check observes these devices, they are
+	// never recovered.
+	unmonitored map[string]bool
+}
+
+// newHealthRecovery creates a healthRecovery instance from the plugin config.
+func newHealthRecovery(config *spec.Config, probe func(*Device) error) *healthRecovery {
+	policy := spec.HealthRecoveryPolicyNone
+	quietPeriod := spec.DefaultHealthRecoveryQuietPeriod
+	if config != nil && config.Flags.Plugin != nil {
+		if config.Flags.Plugin.HealthRecoveryPolicy != nil {
+			policy = *config.Flags.Plugin.HealthRecoveryPolicy
+		}
+		if config.Flags.Plugin.HealthRecoveryQuietPeriod != nil {
+			quietPeriod = time.Duration(*config.Flags.Plugin.HealthRecoveryQuietPeriod)
+		}
+	}
+
+	return &healthRecovery{
+		policy:      policy,
+		quietPeriod: quietPeriod,
+		probe:       probe,
+		now:         time.Now,
+		lastEvent:   make(map[string]time.Time),
+		devices:     make(map[string]*Device),
+		unmonitored: make(map[string]bool),
+	}
+}
+
+// enabled returns whether devices are ever recovered.
+func (h *healthRecovery) enabled() bool {
+	switch h.policy {
+	case spec.HealthRecoveryPolicyQuietPeriod, spec.HealthRecoveryPolicyNVMLProbe:
+		return true
+	}
+	return false
+}
+
+// markUnhealthy records a health event for the specified device. This resets
+// the quiet period for the device.
+func (h *healthRecovery) markUnhealthy(d *Device) {
+	if !h.enabled() || h.unmonitored[d.ID] {
+		return
+	}
+	h.lastEvent[d.ID] = h.now()
+	h.devices[d.ID] = d
+}
+
+// markUnmonitored records that the health of the specified device cannot be
+// monitored. The device is no longer considered for recovery.
+func (h *healthRecovery) markUnmonitored(d *Device) {
+	if !h.enabled() {
+		return
+	}
+	h.unmonitored[d.ID] = true
+	delete(h.lastEvent, d.ID)
+	delete(h.devices, d.ID)
+}
+
+// recovered returns the list of unhealthy devices that are considered
+// recovered. These devices are no longer tracked.
+func (h *healthRecovery) recovered() []*Device {
+	if !h.enabled() {
+		return nil
+	}
+
+	var recovered []*Device
+	for id, last := range h.lastEvent {
+		if h.now().Sub(last) < h.quietPeriod {
+			continue
+		}
+		d := h.devices[id]
+		if h.policy == spec.HealthRecoveryPolicyNVMLProbe && h.probe != nil {
+			if err := h.probe(d); err != nil {
+				klog.Infof("Device %v is not yet recovered: %v", id, err)
+				// We restart the quiet period to avoid probing the device
+				// on every iteration.
+				h.lastEvent[id] = h.now()
+				continue
+			}
+		}
+		recovered = append(recovered, d)
+		delete(h.lastEvent, id)
+		delete(h.devices, id)
+	}
+	return recovered
+}
@@ -0,0 +1,178 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+	"k8s.io/utils/ptr"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+func TestHealthRecovery(t *testing.T) {
+	testCases := []struct {
+		description       string
+		policy            string
+		quietPeriod       time.Duration
+		probeErr          error
+		elapsed           time.Duration
+		expectedRecovered []string
+		expectedTracked   int
+	}{
+		{
+			description: "none policy never recovers",
+			policy:      spec.HealthRecoveryPolicyNone,
+			quietPeriod: time.Minute,
+			elapsed:     time.Hour,
+		},
+		{
+			description:     "quiet-period policy does not recover before the period",
+			policy:          spec.HealthRecoveryPolicyQuietPeriod,
+			quietPeriod:     time.Minute,
+			elapsed:         30 * time.Second,
+			expectedTracked: 1,
+		},
+		{
+			description:       "quiet-period policy recovers after the period",
+			policy:            spec.HealthRecoveryPolicyQuietPeriod,
+			quietPeriod:       time.Minute,
+			elapsed:           time.Minute,
+			expectedRecovered: []string{"GPU-0"},
+		},
+		{
+			description:       "quiet-period policy ignores probe errors",
+			policy:            spec.HealthRecoveryPolicyQuietPeriod,
+			quietPeriod:       time.Minute,
+			probeErr:          fmt.Errorf("probe failed"),
+			elapsed:           time.Minute,
+			expectedRecovered: []string{"GPU-0"},
+		},
+		{
+			description:       "nvml-probe policy recovers if probe succeeds",
+			policy:            spec.HealthRecoveryPolicyNVMLProbe,
+			quietPeriod:       time.Minute,
+			elapsed:           time.Minute,
+			expectedRecovered: []string{"GPU-0"},
+		},
+		{
+			description:     "nvml-probe policy does not recover if probe fails",
+			policy:          spec.HealthRecoveryPolicyNVMLProbe,
+			quietPeriod:     time.Minute,
+			probeErr:        fmt.Errorf("probe failed"),
+			elapsed:         time.Minute,
+			expectedTracked: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			config := &spec.Config{
+				Flags: spec.Flags{
+					CommandLineFlags: spec.CommandLineFlags{
+						Plugin: &spec.PluginCommandLineFlags{
+							HealthRecoveryPolicy:      ptr.To(tc.policy),
+							HealthRecoveryQuietPeriod: ptr.To(spec.Duration(tc.quietPeriod)),
+						},
+					},
+				},
+			}
+			probe := func(*Device) error {
+				return tc.probeErr
+			}
+
+			now := time.Now()
+			h := newHealthRecovery(config, probe)
+			h.now = func() time.Time { return now }
+
+			d := &Device{Device: pluginapi.Device{ID: "GPU-0"}}
+			h.markUnhealthy(d)
+
+			now = now.Add(tc.elapsed)
+
+			var recovered []string
+			for _, d := range h.recovered() {
+				recovered = append(recovered, d.ID)
+			}
+			require.Equal(t, tc.expectedRecovered, recovered)
+			require.Len(t, h.lastEvent, tc.expectedTracked)
+		})
+	}
+}
+
+func TestHealthRecoveryResetsQuietPeriod(t *testing.T) {
+	config := &spec.Config{
+		Flags: spec.Flags{
+			CommandLineFlags: spec.CommandLineFlags{
+				Plugin: &spec.PluginCommandLineFlags{
+					HealthRecoveryPolicy:      ptr.To(spec.HealthRecoveryPolicyQuietPeriod),
+					HealthRecoveryQuietPeriod: ptr.To(spec.Duration(time.Minute)),
+				},
+			},
+		},
+	}
+
+	now := time.Now()
+	h := newHealthRecovery(config, nil)
+	h.now = func() time.Time { return now }
+
+	d := &Device{Device: pluginapi.Device{ID: "GPU-0"}}
+	h.markUnhealthy(d)
+
+	now = now.Add(45 * time.Second)
+	h.markUnhealthy(d)
+
+	now = now.Add(45 * time.Second)
+	require.Empty(t, h.recovered())
+
+	now = now.Add(15 * time.Second)
+	require.Len(t, h.recovered(), 1)
+	require.Empty(t, h.recovered())
+}
+
+func TestHealthRecoverySkipsUnmonitoredDevices(t *testing.T) {
+	config := &spec.Config{
+		Flags: spec.Flags{
+			CommandLineFlags: spec.CommandLineFlags{
+				Plugin: &spec.PluginCommandLineFlags{
+					HealthRecoveryPolicy:      ptr.To(spec.HealthRecoveryPolicyNVMLProbe),
+					HealthRecoveryQuietPeriod: ptr.To(spec.Duration(time.Minute)),
+				},
+			},
+		},
+	}
+
+	now := time.Now()
+	h := newHealthRecovery(config, func(*Device) error { return nil })
+	h.now = func() time.Time { return now }
+
+	unmonitored := &Device{Device: pluginapi.Device{ID: "GPU-0"}}
+	monitored := &Device{Device: pluginapi.Device{ID: "GPU-1"}}
+	h.markUnmonitored(unmonitored)
+	h.markUnhealthy(unmonitored)
+	h.markUnhealthy(monitored)
+
+	now = now.Add(time.Hour)
+	recovered := h.recovered()
+	require.Len(t, recovered, 1)
+	require.Equal(t, "GPU-1", recovered[0].ID)
+	require.Empty(t, h.lastEvent)
+}
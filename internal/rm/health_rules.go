@@ -0,0 +1,281 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// healthRule defines a rule that is evaluated against a GPU.
+// A rule returns an error if the GPU should be considered unhealthy. Rules
+// that cannot be evaluated (e.g. because a query is not supported by the
+// device) consider the device healthy.
+type healthRule interface {
+	name() string
+	check(nvml.Device) error
+}
+
+// healthRules is a set of rules that are evaluated periodically.
+type healthRules struct {
+	rules        []healthRule
+	pollInterval time.Duration
+	// dbeEvents indicates whether double-bit ECC error events should be
+	// processed.
+	dbeEvents bool
+}
+
+// newHealthRules constructs the set of health rules from the specified config.
+func newHealthRules(config *spec.HealthRules) *healthRules {
+	h := &healthRules{
+		pollInterval: spec.DefaultHealthRulesPollInterval,
+	}
+	if config == nil {
+		return h
+	}
+	if config.PollInterval != nil {
+		h.pollInterval = time.Duration(*config.PollInterval)
+	}
+	if config.DoubleBitECC != nil {
+		threshold := config.DoubleBitECC.Threshold
+		if threshold == 0 {
+			threshold = 1
+		}
+		h.rules = append(h.rules, &doubleBitECCRule{threshold: threshold})
+		h.dbeEvents = true
+	}
+	if config.RetiredPages != nil {
+		h.rules = append(h.rules, &retiredPagesRule{
+			threshold: config.RetiredPages.Threshold,
+			pending:   config.RetiredPages.Pending,
+		})
+	}
+	if config.RemappedRows != nil {
+		h.rules = append(h.rules, &remappedRowsRule{
+			uncorrectableThreshold: config.RemappedRows.UncorrectableThreshold,
+			pending:                config.RemappedRows.Pending,
+		})
+	}
+	if config.Temperature != nil {
+		h.rules = append(h.rules, &temperatureRule{maxCelsius: config.Temperature.MaxCelsius})
+	}
+	if config.Power != nil {
+		h.rules = append(h.rules, &powerRule{maxPercentOfLimit: config.Power.MaxPercentOfLimit})
+	}
+	if config.ClockThrottle != nil {
+		h.rules = append(h.rules, newClockThrottleRule(config.ClockThrottle.Reasons))
+	}
+	return h
+}
+
+// check evaluates all rules against the specified GPU and returns the errors
+// for the rules that fail.
+func (h *healthRules) check(gpu nvml.Device) []error {
+	var errs []error
+	for _, rule := range h.rules {
+		if err := rule.check(gpu); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", rule.name(), err))
+		}
+	}
+	return errs
+}
+
+// doubleBitECCRule checks the number of volatile uncorrected ECC errors.
+type doubleBitECCRule struct {
+	threshold uint64
+}
+
+func (r *doubleBitECCRule) name() string {
+	return "doubleBitECC"
+}
+
+func (r *doubleBitECCRule) check(gpu nvml.Device) error {
+	count, ret := gpu.GetTotalEccErrors(nvml.MEMORY_ERROR_TYPE_UNCORRECTED, nvml.VOLATILE_ECC)
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	if count >= r.threshold {
+		return fmt.Errorf("%d volatile double-bit ECC errors (threshold %d)", count, r.threshold)
+	}
+	return nil
+}
+
+// retiredPagesRule checks the number of pages retired due to ECC errors.
+type retiredPagesRule struct {
+	threshold int
+	pending   bool
+}
+
+func (r *retiredPagesRule) name() string {
+	return "retiredPages"
+}
+
+func (r *retiredPagesRule) check(gpu nvml.Device) error {
+	if r.pending {
+		status, ret := gpu.GetRetiredPagesPendingStatus()
+		if ret == nvml.SUCCESS && status == nvml.FEATURE_ENABLED {
+			return fmt.Errorf("pages are pending retirement")
+		}
+	}
+	if r.threshold == 0 {
+		return nil
+	}
+
+	var total int
+	for _, cause := range []nvml.PageRetirementCause{
+		nvml.PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS,
+		nvml.PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR,
+	} {
+		pages, ret := gpu.GetRetiredPages(cause)
+		if ret != nvml.SUCCESS {
+			return nil
+		}
+		total += len(pages)
+	}
+	if total >= r.threshold {
+		return fmt.Errorf("%d retired pages (threshold %d)", total, r.threshold)
+	}
+	return nil
+}
+
+// remappedRowsRule checks the row remapping state of a device.
+type remappedRowsRule struct {
+	uncorrectableThreshold int
+	pending                bool
+}
+
+func (r *remappedRowsRule) name() string {
+	return "remappedRows"
+}
+
+func (r *remappedRowsRule) check(gpu nvml.Device) error {
+	_, uncorrectable, isPending, failureOccurred, ret := gpu.GetRemappedRows()
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	if failureOccurred {
+		return fmt.Errorf("row remapping failure occurred")
+	}
+	if r.pending && isPending {
+		return fmt.Errorf("row remapping is pending")
+	}
+	if r.uncorrectableThreshold > 0 && uncorrectable >= r.uncorrectableThreshold {
+		return fmt.Errorf("%d rows remapped due to uncorrectable errors (threshold %d)", uncorrectable, r.uncorrectableThreshold)
+	}
+	return nil
+}
+
+// temperatureRule checks the GPU temperature.
+type temperatureRule struct {
+	maxCelsius uint32
+}
+
+func (r *temperatureRule) name() string {
+	return "temperature"
+}
+
+func (r *temperatureRule) check(gpu nvml.Device) error {
+	maxCelsius := r.maxCelsius
+	if maxCelsius == 0 {
+		threshold, ret := gpu.GetTemperatureThreshold(nvml.TEMPERATURE_THRESHOLD_SLOWDOWN)
+		if ret != nvml.SUCCESS {
+			return nil
+		}
+		maxCelsius = threshold
+	}
+	temperature, ret := gpu.GetTemperature(nvml.TEMPERATURE_GPU)
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	if temperature >= maxCelsius {
+		return fmt.Errorf("temperature %dC (threshold %dC)", temperature, maxCelsius)
+	}
+	return nil
+}
+
+// powerRule checks the power usage against the enforced power limit.
+type powerRule struct {
+	maxPercentOfLimit uint32
+}
+
+func (r *powerRule) name() string {
+	return "power"
+}
+
+func (r *powerRule) check(gpu nvml.Device) error {
+	usage, ret := gpu.GetPowerUsage()
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	limit, ret := gpu.GetEnforcedPowerLimit()
+	if ret != nvml.SUCCESS || limit == 0 {
+		return nil
+	}
+	if uint64(usage)*100 > uint64(limit)*uint64(r.maxPercentOfLimit) {
+		return fmt.Errorf("power usage %dmW exceeds %d%% of the enforced limit %dmW", usage, r.maxPercentOfLimit, limit)
+	}
+	return nil
+}
+
+// clockThrottleRule checks the reasons for which the device clocks are throttled.
+type clockThrottleRule struct {
+	mask    uint64
+	reasons []spec.ClockThrottleReason
+}
+
+var clockThrottleReasonMasks = map[spec.ClockThrottleReason]uint64{
+	spec.ClockThrottleReasonHwSlowdown:           nvml.ClocksThrottleReasonHwSlowdown,
+	spec.ClockThrottleReasonHwThermalSlowdown:    nvml.ClocksThrottleReasonHwThermalSlowdown,
+	spec.ClockThrottleReasonHwPowerBrakeSlowdown: nvml.ClocksThrottleReasonHwPowerBrakeSlowdown,
+	spec.ClockThrottleReasonSwThermalSlowdown:    nvml.ClocksEventReasonSwThermalSlowdown,
+	spec.ClockThrottleReasonSwPowerCap:           nvml.ClocksEventReasonSwPowerCap,
+}
+
+func newClockThrottleRule(reasons []spec.ClockThrottleReason) *clockThrottleRule {
+	r := &clockThrottleRule{}
+	for _, reason := range reasons {
+		r.mask |= clockThrottleReasonMasks[reason]
+		r.reasons = append(r.reasons, reason)
+	}
+	return r
+}
+
+func (r *clockThrottleRule) name() string {
+	return "clockThrottle"
+}
+
+func (r *clockThrottleRule) check(gpu nvml.Device) error {
+	current, ret := gpu.GetCurrentClocksEventReasons()
+	if ret != nvml.SUCCESS {
+		return nil
+	}
+	if current&r.mask == 0 {
+		return nil
+	}
+	var active []string
+	for _, reason := range r.reasons {
+		if current&clockThrottleReasonMasks[reason] != 0 {
+			active = append(active, string(reason))
+		}
+	}
+	return fmt.Errorf("clocks throttled: %s", strings.Join(active, ","))
+}
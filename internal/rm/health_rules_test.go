@@ -0,0 +1,304 @@
+/**
+# Copyright (c) 2026, NVIDIA CORPORATION.  All rights reserved.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package rm
+
+import (
+	"testing"
+	"time"
+
+	"github.com/NVIDIA/go-nvml/pkg/nvml"
+	"github.com/stretchr/testify/require"
+	"k8s.io/utils/ptr"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+)
+
+// fakeRulesDevice is a device that returns fixed values for the queries
+// performed by the health rules. All queries return ret, which defaults to
+// nvml.SUCCESS.
+type fakeRulesDevice struct {
+	nvml.Device
+	ret nvml.Return
+
+	dbeCount             uint64
+	retiredPages         int
+	retiredPagesPending  bool
+	remappedUncorrected  int
+	remappingPending     bool
+	remappingFailed      bool
+	temperature          uint32
+	slowdownTemperature  uint32
+	powerUsage           uint32
+	powerLimit           uint32
+	clocksEventReasons   uint64
+	retiredPagesCauseRet map[nvml.PageRetirementCause]nvml.Return
+}
+
+func (d *fakeRulesDevice) GetTotalEccErrors(nvml.MemoryErrorType, nvml.EccCounterType) (uint64, nvml.Return) {
+	return d.dbeCount, d.ret
+}
+
+func (d *fakeRulesDevice) GetRetiredPagesPendingStatus() (nvml.EnableState, nvml.Return) {
+	if d.retiredPagesPending {
+		return nvml.FEATURE_ENABLED, d.ret
+	}
+	return nvml.FEATURE_DISABLED, d.ret
+}
+
+func (d *fakeRulesDevice) GetRetiredPages(cause nvml.PageRetirementCause) ([]uint64, nvml.Return) {
+	if ret, ok := d.retiredPagesCauseRet[cause]; ok && ret != nvml.SUCCESS {
+		return nil, ret
+	}
+	if cause == nvml.PAGE_RETIREMENT_CAUSE_DOUBLE_BIT_ECC_ERROR {
+		return make([]uint64, d.retiredPages), d.ret
+	}
+	return nil, d.ret
+}
+
+func (d *fakeRulesDevice) GetRemappedRows() (int, int, bool, bool, nvml.Return) {
+	return 0, d.remappedUncorrected, d.remappingPending, d.remappingFailed, d.ret
+}
+
+func (d *fakeRulesDevice) GetTemperature(nvml.TemperatureSensors) (uint32, nvml.Return) {
+	return d.temperature, d.ret
+}
+
+func (d *fakeRulesDevice) GetTemperatureThreshold(nvml.TemperatureThresholds) (uint32, nvml.Return) {
+	return d.slowdownTemperature, d.ret
+}
+
+func (d *fakeRulesDevice) GetPowerUsage() (uint32, nvml.Return) {
+	return d.powerUsage, d.ret
+}
+
+func (d *fakeRulesDevice) GetEnforcedPowerLimit() (uint32, nvml.Return) {
+	return d.powerLimit, d.ret
+}
+
+func (d *fakeRulesDevice) GetCurrentClocksEventReasons() (uint64, nvml.Return) {
+	return d.clocksEventReasons, d.ret
+}
+
+func TestHealthRules(t *testing.T) {
+	testCases := []struct {
+		description string
+		rule        healthRule
+		device      *fakeRulesDevice
+		unhealthy   bool
+	}{
+		{
+			description: "dbe below threshold",
+			rule:        &doubleBitECCRule{threshold: 2},
+			device:      &fakeRulesDevice{dbeCount: 1},
+		},
+		{
+			description: "dbe at threshold",
+			rule:        &doubleBitECCRule{threshold: 2},
+			device:      &fakeRulesDevice{dbeCount: 2},
+			unhealthy:   true,
+		},
+		{
+			description: "dbe not supported",
+			rule:        &doubleBitECCRule{threshold: 1},
+			device:      &fakeRulesDevice{dbeCount: 2, ret: nvml.ERROR_NOT_SUPPORTED},
+		},
+		{
+			description: "retired pages below threshold",
+			rule:        &retiredPagesRule{threshold: 3},
+			device:      &fakeRulesDevice{retiredPages: 2},
+		},
+		{
+			description: "retired pages at threshold",
+			rule:        &retiredPagesRule{threshold: 3},
+			device:      &fakeRulesDevice{retiredPages: 3},
+			unhealthy:   true,
+		},
+		{
+			description: "retired pages query fails for one cause",
+			rule:        &retiredPagesRule{threshold: 3},
+			device: &fakeRulesDevice{
+				retiredPages: 3,
+				retiredPagesCauseRet: map[nvml.PageRetirementCause]nvml.Return{
+					nvml.PAGE_RETIREMENT_CAUSE_MULTIPLE_SINGLE_BIT_ECC_ERRORS: nvml.ERROR_UNKNOWN,
+				},
+			},
+		},
+		{
+			description: "retired pages pending ignored",
+			rule:        &retiredPagesRule{},
+			device:      &fakeRulesDevice{retiredPagesPending: true},
+		},
+		{
+			description: "retired pages pending",
+			rule:        &retiredPagesRule{pending: true},
+			device:      &fakeRulesDevice{retiredPagesPending: true},
+			unhealthy:   true,
+		},
+		{
+			description: "row remapping failure",
+			rule:        &remappedRowsRule{},
+			device:      &fakeRulesDevice{remappingFailed: true},
+			unhealthy:   true,
+		},
+		{
+			description: "row remapping pending ignored",
+			rule:        &remappedRowsRule{},
+			device:      &fakeRulesDevice{remappingPending: true},
+		},
+		{
+			description: "row remapping pending",
+			rule:        &remappedRowsRule{pending: true},
+			device:      &fakeRulesDevice{remappingPending: true},
+			unhealthy:   true,
+		},
+		{
+			description: "uncorrectable remapped rows at threshold",
+			rule:        &remappedRowsRule{uncorrectableThreshold: 4},
+			device:      &fakeRulesDevice{remappedUncorrected: 4},
+			unhealthy:   true,
+		},
+		{
+			description: "row remapping not supported",
+			rule:        &remappedRowsRule{pending: true},
+			device:      &fakeRulesDevice{remappingFailed: true, ret: nvml.ERROR_NOT_SUPPORTED},
+		},
+		{
+			description: "temperature below max",
+			rule:        &temperatureRule{maxCelsius: 85},
+			device:      &fakeRulesDevice{temperature: 84},
+		},
+		{
+			description: "temperature at max",
+			rule:        &temperatureRule{maxCelsius: 85},
+			device:      &fakeRulesDevice{temperature: 85},
+			unhealthy:   true,
+		},
+		{
+			description: "temperature at device slowdown threshold",
+			rule:        &temperatureRule{},
+			device:      &fakeRulesDevice{temperature: 90, slowdownTemperature: 90},
+			unhealthy:   true,
+		},
+		{
+			description: "power within limit",
+			rule:        &powerRule{maxPercentOfLimit: 100},
+			device:      &fakeRulesDevice{powerUsage: 300000, powerLimit: 300000},
+		},
+		{
+			description: "power exceeds limit",
+			rule:        &powerRule{maxPercentOfLimit: 90},
+			device:      &fakeRulesDevice{powerUsage: 280000, powerLimit: 300000},
+			unhealthy:   true,
+		},
+		{
+			description: "power limit unknown",
+			rule:        &powerRule{maxPercentOfLimit: 90},
+			device:      &fakeRulesDevice{powerUsage: 280000},
+		},
+		{
+			description: "clocks not throttled for selected reason",
+			rule:        newClockThrottleRule([]spec.ClockThrottleReason{spec.ClockThrottleReasonHwThermalSlowdown}),
+			device:      &fakeRulesDevice{clocksEventReasons: nvml.ClocksEventReasonSwPowerCap},
+		},
+		{
+			description: "clocks throttled for selected reason",
+			rule:        newClockThrottleRule([]spec.ClockThrottleReason{spec.ClockThrottleReasonHwThermalSlowdown}),
+			device:      &fakeRulesDevice{clocksEventReasons: nvml.ClocksThrottleReasonHwThermalSlowdown | nvml.ClocksEventReasonSwPowerCap},
+			unhealthy:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			err := tc.rule.check(tc.device)
+			if tc.unhealthy {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestNewHealthRules(t *testing.T) {
+	testCases := []struct {
+		description          string
+		config               *spec.HealthRules
+		expectedRules        []string
+		expectedPollInterval time.Duration
+		expectedDBEEvents    bool
+	}{
+		{
+			description:          "no rules",
+			expectedPollInterval: spec.DefaultHealthRulesPollInterval,
+		},
+		{
+			description: "all rules",
+			config: &spec.HealthRules{
+				PollInterval:  ptr.To(spec.Duration(time.Minute)),
+				DoubleBitECC:  &spec.DoubleBitECCRule{},
+				RetiredPages:  &spec.RetiredPagesRule{Threshold: 60},
+				RemappedRows:  &spec.RemappedRowsRule{},
+				Temperature:   &spec.TemperatureRule{},
+				Power:         &spec.PowerRule{MaxPercentOfLimit: 110},
+				ClockThrottle: &spec.ClockThrottleRule{Reasons: []spec.ClockThrottleReason{spec.ClockThrottleReasonHwSlowdown}},
+			},
+			expectedRules: []string{
+				"doubleBitECC",
+				"retiredPages",
+				"remappedRows",
+				"temperature",
+				"power",
+				"clockThrottle",
+			},
+			expectedPollInterval: time.Minute,
+			expectedDBEEvents:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			h := newHealthRules(tc.config)
+
+			var names []string
+			for _, r := range h.rules {
+				names = append(names, r.name())
+			}
+			require.Equal(t, tc.expectedRules, names)
+			require.Equal(t, tc.expectedPollInterval, h.pollInterval)
+			require.Equal(t, tc.expectedDBEEvents, h.dbeEvents)
+		})
+	}
+}
+
+func TestHealthRulesCheck(t *testing.T) {
+	h := newHealthRules(&spec.HealthRules{
+		DoubleBitECC: &spec.DoubleBitECCRule{},
+		Temperature:  &spec.TemperatureRule{MaxCelsius: 80},
+		Power:        &spec.PowerRule{MaxPercentOfLimit: 100},
+	})
+
+	errs := h.check(&fakeRulesDevice{
+		dbeCount:    1,
+		temperature: 85,
+		powerUsage:  100,
+		powerLimit:  200,
+	})
+	require.Len(t, errs, 2)
+	require.ErrorContains(t, errs[0], "doubleBitECC")
+	require.ErrorContains(t, errs[1], "temperature")
+}
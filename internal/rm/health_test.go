@@ -18,63 +18,48 @@ package rm
 
 import (
 	"fmt"
-	"strings"
 	"testing"
 
 	"github.com/NVIDIA/go-nvml/pkg/nvml"
 	"github.com/stretchr/testify/require"
 	pluginapi "k8s.io/kubelet/pkg/apis/deviceplugin/v1beta1"
+
+	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
 )
 
 func TestNewHealthCheckXIDs(t *testing.T) {
 	testCases := []struct {
-		input    string
+		input    *spec.XIDs
 		expected disabledXIDs
 	}{
 		{
 			expected: disabledXIDs{},
 		},
 		{
-			input:    ",",
+			input:    &spec.XIDs{},
 			expected: disabledXIDs{},
 		},
 		{
-			input:    "not-an-int",
-			expected: disabledXIDs{},
-		},
-		{
-			input:    "68",
+			input:    &spec.XIDs{IDs: []uint64{68}},
 			expected: disabledXIDs{68: true},
 		},
 		{
-			input:    "-68",
-			expected: disabledXIDs{},
-		},
-		{
-			input:    "68  ",
-			expected: disabledXIDs{68: true},
-		},
-		{
-			input:    "68,",
-			expected: disabledXIDs{68: true},
-		},
-		{
-			input:    ",68",
-			expected: disabledXIDs{68: true},
+			input:    &spec.XIDs{IDs: []uint64{68, 67}},
+			expected: disabledXIDs{67: true, 68: true},
 		},
 		{
-			input:    "68,67",
-			expected: disabledXIDs{67: true, 68: true},
+			input:    &spec.XIDs{All: true},
+			expected: disabledXIDs{0: true},
 		},
 		{
-			input:    "68,not-an-int,67",
-			expected: disabledXIDs{67: true, 68: true},
+			input:    &spec.XIDs{All: true, IDs: []uint64{68}},
+			expected: disabledXIDs{0: true},
 		},
 	}
 
 	for i, tc := range testCases {
 		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
-			xids := newHealthCheckXIDs(strings.Split(tc.input, ",")...)
+			xids := newHealthCheckXIDs(tc.input)
 
 			require.EqualValues(t, tc.expected, xids)
 		})
@@ -84,14 +69,14 @@ func TestNewHealthCheckXIDs(t *testing.T) {
 func TestGetDisabledHealthCheckXids(t *testing.T) {
 	testCases := []struct {
 		description         string
-		enabled             string
-		disabled            string
+		enabled             *spec.XIDs
+		disabled            *spec.XIDs
 		expectedAllDisabled bool
 		expectedContents    disabledXIDs
 		expectedDisabled    map[uint64]bool
 	}{
 		{
-			description:         "empty envvars are default disabled",
+			description:         "unset xids are default disabled",
 			expectedAllDisabled: false,
 			expectedContents: disabledXIDs{
 				13:  true,
@@ -112,7 +97,7 @@ func TestGetDisabledHealthCheckXids(t *testing.T) {
 		},
 		{
 			description:         "disabled is all",
-			disabled:            "all",
+			disabled:            &spec.XIDs{All: true},
 			expectedAllDisabled: true,
 			expectedContents: disabledXIDs{
 				0:   true,
@@ -134,8 +119,8 @@ func TestGetDisabledHealthCheckXids(t *testing.T) {
 			},
 		},
 		{
-			description:         "disabled is xids",
-			disabled:            "xids",
+			description:         "disabled is all with explicit xids",
+			disabled:            &spec.XIDs{All: true, IDs: []uint64{11}},
 			expectedAllDisabled: true,
 			expectedContents: disabledXIDs{
 				0:   true,
@@ -158,7 +143,7 @@ func TestGetDisabledHealthCheckXids(t *testing.T) {
 		},
 		{
 			description:         "enabled is all",
-			enabled:             "all",
+			enabled:             &spec.XIDs{All: true},
 			expectedAllDisabled: false,
 			expectedContents: disabledXIDs{
 				0:   false,
@@ -181,8 +166,8 @@ func TestGetDisabledHealthCheckXids(t *testing.T) {
 		},
 		{
 			description:         "enabled overrides disabled",
-			disabled:            "11",
-			enabled:             "11",
+			disabled:            &spec.XIDs{IDs: []uint64{11}},
+			enabled:             &spec.XIDs{IDs: []uint64{11}},
 			expectedAllDisabled: false,
 			expectedContents: disabledXIDs{
 				11:  false,
@@ -208,10 +193,12 @@ func TestGetDisabledHealthCheckXids(t *testing.T) {
 
 	for _, tc := range testCases {
 		t.Run(tc.description, func(t *testing.T) {
-			t.Setenv(envDisableHealthChecks, tc.disabled)
-			t.Setenv(envEnableHealthChecks, tc.enabled)
+			healthChecks := spec.HealthChecks{
+				DisabledXIDs: tc.disabled,
+				EnabledXIDs:  tc.enabled,
+			}
 
-			xids := getDisabledHealthCheckXids()
+			xids := getDisabledHealthCheckXids(healthChecks)
 			require.EqualValues(t, tc.expectedContents, xids)
 			require.Equal(t, tc.expectedAllDisabled, xids.IsAllDisabled())
 
@@ -409,3 +396,29 @@ func TestGetMigDeviceParts(t *testing.T) {
 		})
 	}
 }
+
+func TestGetHealthEventMask(t *testing.T) {
+	testCases := []struct {
+		description  string
+		healthChecks spec.HealthChecks
+		expected     uint64
+	}{
+		{
+			description: "all event types are watched by default",
+			expected:    nvml.EventTypeXidCriticalError | nvml.EventTypeDoubleBitEccError | nvml.EventTypeSingleBitEccError,
+		},
+		{
+			description: "only selected event types are watched",
+			healthChecks: spec.HealthChecks{
+				EventTypes: []spec.HealthEventType{spec.HealthEventTypeXid},
+			},
+			expected: nvml.EventTypeXidCriticalError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			require.Equal(t, tc.expected, getHealthEventMask(tc.healthChecks))
+		})
+	}
+}
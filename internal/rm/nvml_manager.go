@@ -26,6 +26,7 @@ import (
 	"k8s.io/klog/v2"
 
 	spec "github.com/NVIDIA/k8s-device-plugin/api/config/v1"
+	"github.com/NVIDIA/k8s-device-plugin/internal/resource"
 )
 
 type nvmlResourceManager struct {
@@ -55,6 +56,14 @@ func NewNVMLResourceManagers(infolib info.Interface, nvmllib nvml.Interface, dev
 		return nil, fmt.Errorf("error building device map: %v", err)
 	}
 
+	if ccMode := getExpectedCCMode(config); ccMode != spec.CCModeAny {
+		state, err := resource.NewNVMLManager(nvmllib, devicelib).GetConfComputeState()
+		if err != nil {
+			return nil, fmt.Errorf("error getting CC state: %w", err)
+		}
+		deviceMap = deviceMap.filterByCCMode(state, ccMode)
+	}
+
 	var rms []ResourceManager
 	for resourceName, devices := range deviceMap {
 		if len(devices) == 0 {
@@ -102,9 +111,9 @@ func (r *nvmlResourceManager) GetDevicePaths(ids []string) []string {
 	return append(paths, r.resourceManager.GetDevicePaths(ids)...)
 }
 
-// CheckHealth performs health checks on a set of devices, writing to the 'unhealthy' channel with any unhealthy devices
-func (r *nvmlResourceManager) CheckHealth(stop <-chan interface{}, unhealthy chan<- *Device) error {
-	return r.checkHealth(stop, r.devices, unhealthy)
+// CheckHealth performs health checks on a set of devices, writing to the 'health' channel on any health transitions
+func (r *nvmlResourceManager) CheckHealth(stop <-chan interface{}, health chan<- *HealthEvent) error {
+	return r.checkHealth(stop, r.devices, health)
 }
 
 // getPreferredAllocation runs an allocation algorithm over the inputs.
@@ -0,0 +1,141 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+// Package status implements the status file through which a component
+// reports whether it applied the current contents of its config file.
+package status
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Status is the contents of a status file.
+type Status struct {
+	// ConfigHash is the SHA-256 hash of the contents of the config file that
+	// the status applies to.
+	ConfigHash string `json:"configHash"`
+	// Error is set if the config could not be applied.
+	Error string `json:"error,omitempty"`
+}
+
+// Write reports whether the config with the specified hash was applied. The
+// hash must be taken from the contents that were actually loaded (see
+// HashContents) rather than from the config file when the status is written,
+// since the config file may have been replaced in the meantime. The status
+// file is replaced atomically so that readers never observe a partially
+// written status.
+func Write(statusFile string, configHash string, applyErr error) error {
+	s := Status{
+		ConfigHash: configHash,
+	}
+	if applyErr != nil {
+		s.Error = applyErr.Error()
+	}
+
+	contents, err := json.Marshal(s)
+	if err != nil {
+		return fmt.Errorf("failed to marshal status: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(statusFile), "."+filepath.Base(statusFile))
+	if err != nil {
+		return fmt.Errorf("failed to create status file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write status file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), statusFile); err != nil {
+		return fmt.Errorf("failed to replace status file: %w", err)
+	}
+	return nil
+}
+
+// Read reads the specified status file.
+func Read(statusFile string) (*Status, error) {
+	contents, err := os.ReadFile(statusFile)
+	if err != nil {
+		return nil, err
+	}
+	var s Status
+	if err := json.Unmarshal(contents, &s); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal status: %w", err)
+	}
+	return &s, nil
+}
+
+// Hash returns the hash of the contents of the specified config file. A
+// config file that does not exist is treated as an empty file.
+func Hash(configFile string) (string, error) {
+	var contents []byte
+	if configFile != "" {
+		var err error
+		contents, err = os.ReadFile(configFile)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return "", fmt.Errorf("failed to read config file: %w", err)
+		}
+	}
+	return HashContents(contents), nil
+}
+
+// HashContents returns the hash of the specified config file contents.
+func HashContents(contents []byte) string {
+	hash := sha256.Sum256(contents)
+	return hex.EncodeToString(hash[:])
+}
+
+// Wait waits until the status file reports on the current contents of the
+// specified config file. An error is returned if the config could not be
+// applied or if the context is done before the status is reported.
+func Wait(ctx context.Context, statusFile string, configFile string, interval time.Duration) error {
+	hash, err := Hash(configFile)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		s, err := Read(statusFile)
+		switch {
+		case err != nil && !errors.Is(err, os.ErrNotExist):
+			return fmt.Errorf("failed to read status file: %w", err)
+		case s != nil && s.ConfigHash == hash && s.Error != "":
+			return errors.New(s.Error)
+		case s != nil && s.ConfigHash == hash:
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for the config to be applied: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,94 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package status
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWait(t *testing.T) {
+	testCases := []struct {
+		description   string
+		status        func(statusFile string, configFile string) error
+		expectedError string
+	}{
+		{
+			description: "config applied",
+			status: func(statusFile string, configFile string) error {
+				return Write(statusFile, HashContents([]byte("version: v1")), nil)
+			},
+		},
+		{
+			description: "config failed",
+			status: func(statusFile string, configFile string) error {
+				return Write(statusFile, HashContents([]byte("version: v1")), errors.New("unable to load config"))
+			},
+			expectedError: "unable to load config",
+		},
+		{
+			description: "status of a different config",
+			status: func(statusFile string, configFile string) error {
+				return Write(statusFile, HashContents([]byte("version: v2")), nil)
+			},
+			expectedError: "timed out waiting for the config to be applied: context deadline exceeded",
+		},
+		{
+			description:   "no status",
+			expectedError: "timed out waiting for the config to be applied: context deadline exceeded",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.description, func(t *testing.T) {
+			dir := t.TempDir()
+			configFile := filepath.Join(dir, "config.yaml")
+			statusFile := filepath.Join(dir, "status.json")
+			require.NoError(t, os.WriteFile(configFile, []byte("version: v1"), 0600))
+
+			if tc.status != nil {
+				require.NoError(t, tc.status(statusFile, configFile))
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			defer cancel()
+
+			err := Wait(ctx, statusFile, configFile, 10*time.Millisecond)
+			if tc.expectedError != "" {
+				require.EqualError(t, err, tc.expectedError)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestHashOfMissingFile(t *testing.T) {
+	missing, err := Hash(filepath.Join(t.TempDir(), "missing"))
+	require.NoError(t, err)
+
+	empty, err := Hash("/dev/null")
+	require.NoError(t, err)
+
+	require.Equal(t, empty, missing)
+}
@@ -0,0 +1,107 @@
+/**
+# Copyright 2026 NVIDIA CORPORATION
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+**/
+
+package validate
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/NVIDIA/k8s-device-plugin/api/config/versioned"
+)
+
+type command struct {
+	ignoredFields []string
+	strict        bool
+}
+
+// NewCommand constructs a validate-config command. The specified fields of
+// the config are reported as ignored by the component if they are set.
+func NewCommand(ignoredFields ...string) *cli.Command {
+	v := command{
+		ignoredFields: ignoredFields,
+	}
+
+	c := cli.Command{
+		Name:      "validate-config",
+		Usage:     "Validate one or more config files and report all errors with the path of the offending field",
+		ArgsUsage: "CONFIG_FILE [CONFIG_FILE...]",
+		Action: func(c *cli.Context) error {
+			return v.run(c.App.Writer, c.Args().Slice()...)
+		},
+	}
+
+	c.Flags = []cli.Flag{
+		&cli.BoolFlag{
+			Name:        "strict",
+			Usage:       "treat ignored fields as errors",
+			Destination: &v.strict,
+		},
+	}
+
+	return &c
+}
+
+// run validates the specified config files and returns an error if any of
+// them is invalid.
+func (v command) run(w io.Writer, configFiles ...string) error {
+	if len(configFiles) == 0 {
+		return fmt.Errorf("at least one config file must be specified")
+	}
+
+	var invalid int
+	for _, configFile := range configFiles {
+		if !v.validate(w, configFile) {
+			invalid++
+		}
+	}
+	if invalid > 0 {
+		return fmt.Errorf("%d of %d config files are invalid", invalid, len(configFiles))
+	}
+	return nil
+}
+
+// validate validates a single config file and writes the errors and warnings
+// to the specified writer.
+func (v command) validate(w io.Writer, configFile string) bool {
+	contents, err := os.ReadFile(configFile)
+	if err != nil {
+		fmt.Fprintf(w, "%s: error: %v\n", configFile, err)
+		return false
+	}
+
+	result, err := versioned.ValidateConfig(contents, v.ignoredFields...)
+	if err != nil {
+		fmt.Fprintf(w, "%s: error: %v\n", configFile, err)
+		return false
+	}
+
+	for _, e := range result.Errors {
+		fmt.Fprintf(w, "%s: error: %v\n", configFile, e)
+	}
+	for _, e := range result.Warnings {
+		fmt.Fprintf(w, "%s: warning: %v\n", configFile, e)
+	}
+
+	valid := len(result.Errors) == 0 && (!v.strict || len(result.Warnings) == 0)
+	if valid {
+		fmt.Fprintf(w, "%s: valid\n", configFile)
+	}
+	return valid
+}
@@ -18,6 +18,7 @@ package watch
 import (
 	"os"
 	"os/signal"
+	"path/filepath"
 
 	"github.com/fsnotify/fsnotify"
 )
@@ -40,6 +41,19 @@ func Files(files ...string) (*fsnotify.Watcher, error) {
 	return watcher, nil
 }
 
+// IsConfigFileEvent checks whether a file system event changes the specified
+// config file. This includes the update of a mounted ConfigMap, which replaces
+// the '..data' symlink in the directory of the config file.
+func IsConfigFileEvent(configFile string, event fsnotify.Event) bool {
+	if configFile == "" || event.Op == fsnotify.Chmod {
+		return false
+	}
+	if filepath.Dir(event.Name) != filepath.Dir(configFile) {
+		return false
+	}
+	return event.Name == configFile || filepath.Base(event.Name) == "..data"
+}
+
 // Signals creats a channel for the specified signals.
 func Signals(sigs ...os.Signal) chan os.Signal {
 	sigChan := make(chan os.Signal, 1)